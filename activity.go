@@ -0,0 +1,61 @@
+package lifetime
+
+import (
+	"sync"
+)
+
+// activity counts the services, jobs and tasks that Wait waits for.
+// Unlike a sync.WaitGroup it is safe to add to it at any time, including while it is being waited on
+// with a count of zero, so services can be started concurrently with calls to Wait.
+type activity struct {
+	mu    sync.Mutex
+	count int
+	// idle is closed while the count is zero.
+	idle chan struct{}
+}
+
+// newActivity returns an activity with a count of zero.
+func newActivity() *activity {
+	idle := make(chan struct{})
+	close(idle)
+	return &activity{idle: idle}
+}
+
+// Add adds the given delta to the count. It panics if the count becomes negative.
+func (activity *activity) Add(delta int) {
+	activity.mu.Lock()
+	defer activity.mu.Unlock()
+	if activity.count == 0 && delta > 0 {
+		activity.idle = make(chan struct{})
+	}
+	activity.count += delta
+	switch {
+	case activity.count < 0:
+		panic("lifetime: negative activity count")
+	case activity.count == 0 && delta < 0:
+		close(activity.idle)
+	}
+}
+
+// Done decrements the count by one.
+func (activity *activity) Done() {
+	activity.Add(-1)
+}
+
+// Wait blocks until the count is zero.
+// If the count is increased again before Wait notices that it reached zero, Wait keeps waiting.
+func (activity *activity) Wait() {
+	for {
+		activity.mu.Lock()
+		idle := activity.idle
+		activity.mu.Unlock()
+		<-idle
+
+		activity.mu.Lock()
+		count := activity.count
+		activity.mu.Unlock()
+		if count == 0 {
+			return
+		}
+	}
+}
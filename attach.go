@@ -0,0 +1,52 @@
+package lifetime
+
+import (
+	"fmt"
+)
+
+// Attach adopts another lifetime, such as one managed internally by a library, as if it were a service.
+// When this lifetime shuts down the other lifetime is shut down too, and Wait waits for the services of the
+// other lifetime to finish. The other lifetime holds back the startup phase until it is ready itself.
+// If the other lifetime shuts down because of a service error, the error is treated as a failure of the
+// attached service. The service is named "attached" unless WithName is given.
+// If this lifetime is shut down by a signal, the other lifetime is shut down as if it received the same signal.
+// Use WithCascadeOrder to decide when the other lifetime is shut down.
+func (lifetime *Lifetime) Attach(other *Lifetime, opts ...StartOption) *ServiceHandle {
+	opts = append([]StartOption{WithName("attached")}, opts...)
+	return lifetime.Start(&attachedLifetime{lifetime: other, parent: lifetime}, opts...)
+}
+
+// attachedLifetime is an implementation of Service that runs until another lifetime has finished.
+type attachedLifetime struct {
+	lifetime *Lifetime
+	// parent is the lifetime that the other lifetime is attached to.
+	parent *Lifetime
+}
+
+// Start will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal.
+func (service *attachedLifetime) Start() error {
+	if err := service.lifetime.Wait(); err != nil {
+		return fmt.Errorf("attached lifetime: %w", err)
+	}
+	if cause, err := service.lifetime.Cause(); cause == CauseServiceError {
+		return fmt.Errorf("attached lifetime: %w", err)
+	}
+	return nil
+}
+
+// Stop will stop the service.
+// Stop is not called if Start returned an error.
+func (service *attachedLifetime) Stop() {
+	if signalErr, ok := service.parent.shutdownSignal(); ok {
+		service.lifetime.shutdownWith(CauseSignal, signalErr)
+		return
+	}
+	service.lifetime.Shutdown()
+}
+
+// Ready returns a channel that is closed once the attached lifetime is ready.
+func (service *attachedLifetime) Ready() <-chan struct{} {
+	return service.lifetime.Ready()
+}
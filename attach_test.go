@@ -0,0 +1,65 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"syscall"
+	"testing"
+)
+
+func TestLifetime_Attach(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	library := lifetime.New(context.Background()).HandleErrors(nil)
+	svc := newBlockingService()
+	library.Start(svc)
+
+	lt.Attach(library)
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	if !svc.stopped {
+		t.Errorf("expected the attached lifetime's service to be stopped")
+	}
+	if cause, _ := library.Cause(); cause != lifetime.CauseManual {
+		t.Errorf("expected the shutdown to cascade, got %s", cause)
+	}
+}
+
+func TestLifetime_Attach_Signal(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	library := lifetime.New(context.Background()).HandleErrors(nil)
+	library.Start(newBlockingService())
+
+	lt.Attach(library, lifetime.WithCascadeOrder(lifetime.CascadeFirst))
+	lt.ReportError(&lifetime.SignalError{Signal: syscall.SIGTERM, Err: lifetime.ErrShutdownSignalReceived})
+	_ = lt.Wait()
+
+	if cause, _ := library.Cause(); cause != lifetime.CauseSignal {
+		t.Errorf("expected the signal to cascade, got %s", cause)
+	}
+	if got := library.ExitCode(); got != lt.ExitCode() {
+		t.Errorf("expected the attached lifetime to exit with %d, got %d", lt.ExitCode(), got)
+	}
+}
+
+func TestLifetime_Attach_Failure(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	library := lifetime.New(context.Background()).HandleErrors(nil)
+	library.Start(newBlockingService())
+
+	lt.Start(newBlockingService())
+	lt.Attach(library)
+
+	libraryErr := errors.New("library failed")
+	library.ReportError(libraryErr)
+	_ = lt.Wait()
+
+	cause, err := lt.Cause()
+	if cause != lifetime.CauseServiceError || !errors.Is(err, libraryErr) {
+		t.Errorf("expected the attached failure to shutdown the lifetime, got %s: %v", cause, err)
+	}
+}
@@ -0,0 +1,125 @@
+package lifetime
+
+import (
+	"sort"
+)
+
+// WithPriority sets the priority of the service, which decides which services are paused first when
+// load is shed. Higher values are more important. Defaults to 0. See WithLoadShedding.
+func WithPriority(priority int) StartOption {
+	return func(managed *managedService) {
+		managed.priority = priority
+	}
+}
+
+// WithLoadShedding pauses running services that implement Pauser while a source of a higher priority
+// reports backpressure, and resumes them once the backpressure is relieved. See ReportBackpressure.
+// Services that were paused by other means are left alone, and services that are started while
+// load is being shed are only paused the next time backpressure is reported.
+func WithLoadShedding() Option {
+	return func(lifetime *Lifetime) {
+		lifetime.loadShedding = true
+	}
+}
+
+// ReportBackpressure reports whether the given source, usually the name of a service, is overloaded.
+// An EventBackpressure is emitted when a source becomes overloaded and an EventBackpressureRelieved once
+// it recovers. The priority of a source is that of the running service with the same name, or 0 if there isn't one.
+// See WithLoadShedding.
+func (lifetime *Lifetime) ReportBackpressure(source string, overloaded bool) {
+	lifetime.backpressureMu.Lock()
+	defer lifetime.backpressureMu.Unlock()
+
+	_, wasOverloaded := lifetime.overloaded[source]
+	if overloaded == wasOverloaded {
+		return
+	}
+	eventType := EventBackpressureRelieved
+	if overloaded {
+		eventType = EventBackpressure
+		lifetime.overloaded[source] = lifetime.priorityOf(source)
+	} else {
+		delete(lifetime.overloaded, source)
+	}
+	lifetime.emit(Event{Type: eventType, Source: source})
+	lifetime.log(LevelWarn, "lifetime "+string(eventType), Field{Key: "source", Value: source})
+
+	if lifetime.loadShedding {
+		lifetime.shedLoad()
+	}
+}
+
+// Overloaded returns the sources that are currently reporting backpressure, sorted by name.
+func (lifetime *Lifetime) Overloaded() []string {
+	lifetime.backpressureMu.Lock()
+	defer lifetime.backpressureMu.Unlock()
+	sources := make([]string, 0, len(lifetime.overloaded))
+	for source := range lifetime.overloaded {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// priorityOf returns the highest priority of the running services with the given name, or 0 if there aren't any.
+func (lifetime *Lifetime) priorityOf(name string) int {
+	lifetime.servicesMu.Lock()
+	defer lifetime.servicesMu.Unlock()
+	priority, found := 0, false
+	for managed := range lifetime.services {
+		if managed.name == name && (!found || managed.priority > priority) {
+			priority, found = managed.priority, true
+		}
+	}
+	return priority
+}
+
+// shedLoad pauses the services with a lower priority than the most important overloaded source,
+// and resumes the services it previously paused that no longer need to be.
+// It must be called with backpressureMu held.
+func (lifetime *Lifetime) shedLoad() {
+	threshold, shedding := 0, false
+	for _, priority := range lifetime.overloaded {
+		if !shedding || priority > threshold {
+			threshold, shedding = priority, true
+		}
+	}
+
+	for managed := range lifetime.shed {
+		if shedding && managed.priority < threshold {
+			continue
+		}
+		delete(lifetime.shed, managed)
+		select {
+		case <-managed.exited:
+			continue
+		default:
+		}
+		if err := managed.setPaused(false); err != nil {
+			managed.log(LevelWarn, "lifetime could not resume service", err)
+		}
+	}
+	if !shedding {
+		return
+	}
+
+	lifetime.servicesMu.Lock()
+	var candidates []*managedService
+	for managed := range lifetime.services {
+		if _, ok := managed.svc.(Pauser); ok && managed.priority < threshold {
+			candidates = append(candidates, managed)
+		}
+	}
+	lifetime.servicesMu.Unlock()
+
+	for _, managed := range candidates {
+		if _, ok := lifetime.shed[managed]; ok || managed.isPaused() {
+			continue
+		}
+		if err := managed.setPaused(true); err != nil {
+			managed.log(LevelWarn, "lifetime could not pause service", err)
+			continue
+		}
+		lifetime.shed[managed] = struct{}{}
+	}
+}
@@ -0,0 +1,64 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"testing"
+)
+
+func TestLifetime_ReportBackpressure(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithLoadShedding()).HandleErrors(nil)
+
+	events := make(chan lifetime.Event, 10)
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventBackpressure || event.Type == lifetime.EventBackpressureRelieved {
+			events <- event
+		}
+	})
+
+	lt.Start(newBlockingService(), lifetime.WithName("api"), lifetime.WithPriority(10))
+	consumer := lt.Start(&pausableService{blockingService: newBlockingService()}, lifetime.WithName("consumer"))
+	reports := lt.Start(&pausableService{blockingService: newBlockingService()}, lifetime.WithName("reports"), lifetime.WithPriority(5))
+	billing := lt.Start(&pausableService{blockingService: newBlockingService()}, lifetime.WithName("billing"), lifetime.WithPriority(20))
+	manual := lt.Start(&pausableService{blockingService: newBlockingService()}, lifetime.WithName("manual"))
+	if err := manual.Pause(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lt.ReportBackpressure("api", true)
+	lt.ReportBackpressure("api", true)
+	if event := <-events; event.Type != lifetime.EventBackpressure || event.Source != "api" {
+		t.Errorf("expected backpressure from api, got %s from %q", event.Type, event.Source)
+	}
+	if !consumer.Paused() || !reports.Paused() {
+		t.Errorf("expected lower priority services to be paused")
+	}
+	if billing.Paused() {
+		t.Errorf("expected higher priority services not to be paused")
+	}
+	if got := lt.Status().Overloaded; len(got) != 1 || got[0] != "api" {
+		t.Errorf("expected api to be overloaded, got %v", got)
+	}
+
+	lt.ReportBackpressure("api", false)
+	if event := <-events; event.Type != lifetime.EventBackpressureRelieved {
+		t.Errorf("expected backpressure to be relieved, got %s", event.Type)
+	}
+	if consumer.Paused() || reports.Paused() {
+		t.Errorf("expected shed services to be resumed")
+	}
+	if !manual.Paused() {
+		t.Errorf("expected manually paused services to stay paused")
+	}
+	if got := len(lt.Overloaded()); got != 0 {
+		t.Errorf("expected no overloaded sources, got %d", got)
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(events); got != 0 {
+		t.Errorf("expected no more events, got %d", got)
+	}
+}
@@ -0,0 +1,97 @@
+package lifetime
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Barrier is a named point that services can coordinate on during a shutdown, such as a queue having been
+// drained. One service marks the barrier as done, usually in its Stop func, and others wait for it before
+// doing something that depends on it, such as closing a connection pool.
+// Barriers are returned by Lifetime.Barrier.
+type Barrier struct {
+	lifetime *Lifetime
+	name     string
+	once     sync.Once
+	// passed is closed once the barrier has been marked as done.
+	passed chan struct{}
+}
+
+// BarrierStatus describes a single barrier.
+type BarrierStatus struct {
+	// Name is the name of the barrier.
+	Name string `json:"name"`
+	// Done is true once the barrier has been marked as done.
+	Done bool `json:"done"`
+}
+
+// Barrier returns the barrier with the given name, creating it if it doesn't exist yet.
+// Every call with the same name returns the same barrier, so services don't need to share it explicitly.
+func (lifetime *Lifetime) Barrier(name string) *Barrier {
+	lifetime.barriersMu.Lock()
+	defer lifetime.barriersMu.Unlock()
+	if barrier, ok := lifetime.barriers[name]; ok {
+		return barrier
+	}
+	barrier := &Barrier{lifetime: lifetime, name: name, passed: make(chan struct{})}
+	lifetime.barriers[name] = barrier
+	return barrier
+}
+
+// Name returns the name of the barrier.
+func (barrier *Barrier) Name() string {
+	return barrier.name
+}
+
+// Done marks the barrier as done, releasing anything waiting on it.
+// It is safe to call Done more than once.
+func (barrier *Barrier) Done() {
+	barrier.once.Do(func() {
+		close(barrier.passed)
+		barrier.lifetime.emit(Event{Type: EventBarrierPassed, Barrier: barrier.name})
+		barrier.lifetime.log(LevelDebug, "lifetime "+string(EventBarrierPassed), Field{Key: "barrier", Value: barrier.name})
+	})
+}
+
+// Passed returns a channel that is closed once the barrier has been marked as done.
+func (barrier *Barrier) Passed() <-chan struct{} {
+	return barrier.passed
+}
+
+// Wait blocks until the barrier has been marked as done or the given context is done,
+// in which case an error wrapping the context error is returned.
+func (barrier *Barrier) Wait(ctx context.Context) error {
+	select {
+	case <-barrier.passed:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("barrier %s: %w", barrier.name, ctx.Err())
+	}
+}
+
+// status returns the status of the barrier.
+func (barrier *Barrier) status() BarrierStatus {
+	status := BarrierStatus{Name: barrier.name}
+	select {
+	case <-barrier.passed:
+		status.Done = true
+	default:
+	}
+	return status
+}
+
+// barrierStatuses returns the status of every barrier, sorted by name.
+func (lifetime *Lifetime) barrierStatuses() []BarrierStatus {
+	lifetime.barriersMu.Lock()
+	statuses := make([]BarrierStatus, 0, len(lifetime.barriers))
+	for _, barrier := range lifetime.barriers {
+		statuses = append(statuses, barrier.status())
+	}
+	lifetime.barriersMu.Unlock()
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
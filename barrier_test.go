@@ -0,0 +1,72 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stopHookService is a blocking service that executes a func before it stops.
+type stopHookService struct {
+	*blockingService
+	beforeStop func()
+}
+
+func (s *stopHookService) Stop() {
+	s.beforeStop()
+	s.blockingService.Stop()
+}
+
+func TestLifetime_Barrier(t *testing.T) {
+	lt := lifetime.New(context.Background())
+	if lt.Barrier("ingest-drained") != lt.Barrier("ingest-drained") {
+		t.Fatalf("expected the same barrier to be returned for the same name")
+	}
+
+	var mu sync.Mutex
+	order := make([]string, 0)
+	record := func(name string) {
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+
+	drained := lt.Barrier("ingest-drained")
+	lt.Start(&stopHookService{blockingService: newBlockingService(), beforeStop: func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := drained.Wait(ctx); err != nil {
+			t.Errorf("expected the barrier to be passed, got %v", err)
+		}
+		record("db")
+	}})
+	lt.Start(&stopHookService{blockingService: newBlockingService(), beforeStop: func() {
+		time.Sleep(time.Millisecond * 10)
+		record("ingest")
+		drained.Done()
+		drained.Done()
+	}})
+
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	if len(order) != 2 || order[0] != "ingest" || order[1] != "db" {
+		t.Errorf("expected ingest to drain before the db was closed, got %v", order)
+	}
+	status := lt.Status()
+	if len(status.Barriers) != 1 || !status.Barriers[0].Done {
+		t.Errorf("expected the barrier to be reported as done, got %+v", status.Barriers)
+	}
+}
+
+func TestBarrier_Wait_Timeout(t *testing.T) {
+	lt := lifetime.New(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := lt.Barrier("never").Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline exceeded error, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package lifetime
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"sort"
+	"sync/atomic"
+)
+
+// BuildInfo identifies the binary that is running.
+type BuildInfo struct {
+	// GoVersion is the version of Go the binary was built with.
+	GoVersion string `json:"goVersion"`
+	// Path is the package path of the main package.
+	Path string `json:"path,omitempty"`
+	// Version is the version of the main module.
+	Version string `json:"version,omitempty"`
+	// Revision is the VCS revision the binary was built from, if known.
+	Revision string `json:"revision,omitempty"`
+	// Time is the time of the VCS revision, if known.
+	Time string `json:"time,omitempty"`
+	// Modified is true if the binary was built from a working tree with uncommitted changes.
+	Modified bool `json:"modified"`
+}
+
+// ReadBuildInfo returns the build information embedded in the running binary.
+// VCS details are only available in binaries built with Go 1.18 or later.
+func ReadBuildInfo() BuildInfo {
+	info := BuildInfo{}
+	buildInfo, ok := debug.ReadBuildInfo()
+	if ok {
+		info.Path = buildInfo.Path
+		info.Version = buildInfo.Main.Version
+	}
+	readBuildSettings(&info, buildInfo)
+	return info
+}
+
+// ConfigSummary describes how the lifetime has been configured.
+type ConfigSummary struct {
+	// ShutdownTimeout is the shutdown timeout, if any. See WithShutdownTimeout.
+	ShutdownTimeout string `json:"shutdownTimeout,omitempty"`
+	// StartupTimeout is the startup timeout, if any. See WithStartupTimeout.
+	StartupTimeout string `json:"startupTimeout,omitempty"`
+	// DrainDelay is the drain delay, if any. See WithDrainDelay.
+	DrainDelay string `json:"drainDelay,omitempty"`
+	// StopWarning is the time after which a service that is slow to stop is warned about. See WithStopWarning.
+	StopWarning string `json:"stopWarning,omitempty"`
+	// Signals are the signals that trigger a shutdown by default.
+	Signals []string `json:"signals"`
+	// SignalEscalation is the number of shutdown signals that cause an immediate shutdown.
+	SignalEscalation int `json:"signalEscalation"`
+	// LogLevel is the minimum level of messages that are logged.
+	LogLevel string `json:"logLevel"`
+	// StopBudget is true if the time before the shutdown timeout is divided among services. See WithStopBudget.
+	StopBudget bool `json:"stopBudget"`
+	// StopConcurrency is the maximum number of services that are stopped at the same time, if limited.
+	StopConcurrency int `json:"stopConcurrency,omitempty"`
+	// Disabled are the names of the services that have been disabled, sorted. See WithDisabledServices.
+	Disabled []string `json:"disabled,omitempty"`
+	// Deterministic is true if deterministic mode is enabled.
+	Deterministic bool `json:"deterministic"`
+	// Chaos is true if chaos mode is enabled.
+	Chaos bool `json:"chaos"`
+}
+
+// ConfigSummary returns a summary of the configuration of the lifetime.
+func (lifetime *Lifetime) ConfigSummary() ConfigSummary {
+	summary := ConfigSummary{
+		Signals:          make([]string, len(lifetime.signals)),
+		SignalEscalation: lifetime.signalEscalation,
+		LogLevel:         Level(atomic.LoadInt32(&lifetime.logLevel)).String(),
+		StopBudget:       lifetime.stopBudget,
+		StopConcurrency:  lifetime.stopConcurrency,
+		Deterministic:    lifetime.deterministic != nil,
+		Chaos:            lifetime.chaos != nil,
+	}
+	if lifetime.shutdownTimeout > 0 {
+		summary.ShutdownTimeout = lifetime.shutdownTimeout.String()
+	}
+	if lifetime.startupTimeout > 0 {
+		summary.StartupTimeout = lifetime.startupTimeout.String()
+	}
+	if lifetime.drainDelay > 0 {
+		summary.DrainDelay = lifetime.drainDelay.String()
+	}
+	if lifetime.stopWarningThreshold > 0 {
+		summary.StopWarning = lifetime.stopWarningThreshold.String()
+	}
+	for i, sig := range lifetime.signals {
+		summary.Signals[i] = sig.String()
+	}
+	for name := range lifetime.disabled {
+		summary.Disabled = append(summary.Disabled, name)
+	}
+	sort.Strings(summary.Disabled)
+	return summary
+}
+
+// BuildReport is served on the /buildinfo path of the ProbeHandler.
+type BuildReport struct {
+	// Build identifies the binary that is running.
+	Build BuildInfo `json:"build"`
+	// Hostname is the hostname of the machine the binary is running on.
+	Hostname string `json:"hostname,omitempty"`
+	// Config describes how the lifetime has been configured.
+	Config ConfigSummary `json:"config"`
+}
+
+// serveBuildInfo responds with the JSON encoded BuildReport.
+func (lifetime *Lifetime) serveBuildInfo(rw http.ResponseWriter, r *http.Request) {
+	report := BuildReport{
+		Build:  ReadBuildInfo(),
+		Config: lifetime.ConfigSummary(),
+	}
+	report.Hostname, _ = os.Hostname()
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(report)
+}
@@ -0,0 +1,30 @@
+//go:build go1.18
+// +build go1.18
+
+package lifetime
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// readBuildSettings reads the Go version and VCS details from the build settings.
+func readBuildSettings(info *BuildInfo, buildInfo *debug.BuildInfo) {
+	info.GoVersion = runtime.Version()
+	if buildInfo == nil {
+		return
+	}
+	if buildInfo.GoVersion != "" {
+		info.GoVersion = buildInfo.GoVersion
+	}
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.Time = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !go1.18
+// +build !go1.18
+
+package lifetime
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// readBuildSettings sets the Go version. Build settings are not available before Go 1.18.
+func readBuildSettings(info *BuildInfo, buildInfo *debug.BuildInfo) {
+	info.GoVersion = runtime.Version()
+}
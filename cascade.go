@@ -0,0 +1,83 @@
+package lifetime
+
+import (
+	"errors"
+)
+
+// CascadeOrder decides when a service is stopped relative to the other services during a shutdown.
+// It is most useful for child lifetimes, see Attach, and child processes, see ProcessService, which
+// receive the shutdown signal of the parent when they are stopped.
+type CascadeOrder int
+
+const (
+	// CascadeWithClass stops the service along with the other services of its class. See ServiceClass.
+	CascadeWithClass CascadeOrder = iota
+	// CascadeFirst stops the service before any other services, so the shutdown reaches children straight away.
+	CascadeFirst
+	// CascadeLast stops the service once every other service has been stopped.
+	CascadeLast
+)
+
+// String returns the name of the order.
+func (order CascadeOrder) String() string {
+	switch order {
+	case CascadeFirst:
+		return "first"
+	case CascadeLast:
+		return "last"
+	default:
+		return "with-class"
+	}
+}
+
+// WithCascadeOrder sets when the service is stopped relative to the other services during a shutdown.
+// Defaults to CascadeWithClass.
+func WithCascadeOrder(order CascadeOrder) StartOption {
+	return func(managed *managedService) {
+		managed.cascade = order
+	}
+}
+
+// shutdownSignal returns the signal that triggered the shutdown, if it was triggered by a signal.
+func (lifetime *Lifetime) shutdownSignal() (*SignalError, bool) {
+	cause, err := lifetime.Cause()
+	if cause != CauseSignal {
+		return nil, false
+	}
+	var signalErr *SignalError
+	if !errors.As(err, &signalErr) {
+		return nil, false
+	}
+	return signalErr, true
+}
+
+// cascadeSteps moves the services that use CascadeFirst or CascadeLast out of the given steps and into
+// a step of their own at the start or end. Steps that are left empty are removed.
+func cascadeSteps(steps [][]*managedService) [][]*managedService {
+	first := make([]*managedService, 0)
+	last := make([]*managedService, 0)
+	cascaded := make([][]*managedService, 0, len(steps)+2)
+	for _, step := range steps {
+		remaining := make([]*managedService, 0, len(step))
+		for _, managed := range step {
+			switch managed.cascade {
+			case CascadeFirst:
+				first = append(first, managed)
+			case CascadeLast:
+				last = append(last, managed)
+			default:
+				remaining = append(remaining, managed)
+			}
+		}
+		if len(remaining) > 0 {
+			cascaded = append(cascaded, remaining)
+		}
+	}
+	if len(first) > 0 {
+		cascaded = append([][]*managedService{first}, cascaded...)
+	}
+	if len(last) > 0 {
+		cascaded = append(cascaded, last)
+	}
+	return cascaded
+}
@@ -0,0 +1,39 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"testing"
+)
+
+func TestWithCascadeOrder(t *testing.T) {
+	lt := lifetime.New(context.Background())
+	lt.Start(newBlockingService(), lifetime.WithName("api"))
+	lt.Start(newBlockingService(), lifetime.WithName("db"), lifetime.WithClass(lifetime.ClassStorage))
+	lt.Start(newBlockingService(), lifetime.WithName("child"), lifetime.WithClass(lifetime.ClassStorage),
+		lifetime.WithCascadeOrder(lifetime.CascadeFirst),
+	)
+	lt.Start(newBlockingService(), lifetime.WithName("sidecar"), lifetime.WithCascadeOrder(lifetime.CascadeLast))
+
+	steps := lt.PlanShutdown().Steps
+	got := make([]string, 0, len(steps))
+	for _, step := range steps {
+		if len(step) != 1 {
+			t.Fatalf("expected one service per step, got %v", steps)
+		}
+		got = append(got, step[0].Name)
+	}
+	exp := []string{"child", "api", "db", "sidecar"}
+	if len(got) != len(exp) {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+	for i := range exp {
+		if got[i] != exp[i] {
+			t.Errorf("expected %v, got %v", exp, got)
+			break
+		}
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+}
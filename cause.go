@@ -0,0 +1,81 @@
+package lifetime
+
+import (
+	"errors"
+)
+
+// Cause describes why a shutdown was triggered.
+type Cause int
+
+const (
+	// CauseNone is used when no shutdown has been triggered.
+	CauseNone Cause = iota
+	// CauseSignal is used when a shutdown was triggered by a signal from the os.
+	CauseSignal
+	// CauseServiceError is used when a shutdown was triggered by an error from a service, job or ReportError.
+	CauseServiceError
+	// CauseManual is used when a shutdown was triggered by calling Shutdown.
+	CauseManual
+	// CauseParentContext is used when a shutdown was triggered by the context given to New being done.
+	CauseParentContext
+	// CauseTimeout is used when a shutdown was triggered because a configured timeout was exceeded.
+	CauseTimeout
+)
+
+// String returns the name of the cause.
+func (cause Cause) String() string {
+	switch cause {
+	case CauseNone:
+		return "none"
+	case CauseSignal:
+		return "signal"
+	case CauseServiceError:
+		return "service-error"
+	case CauseManual:
+		return "manual"
+	case CauseParentContext:
+		return "parent-context"
+	case CauseTimeout:
+		return "timeout"
+	default:
+		return "unknown"
+	}
+}
+
+// Cause returns the reason the first shutdown was triggered, along with the underlying error if there was one.
+// CauseNone is returned if no shutdown has been triggered.
+func (lifetime *Lifetime) Cause() (Cause, error) {
+	lifetime.causeMu.Lock()
+	defer lifetime.causeMu.Unlock()
+	return lifetime.cause, lifetime.causeErr
+}
+
+// shutdownWith records the given cause, if no cause has been recorded yet, and triggers a graceful shutdown.
+func (lifetime *Lifetime) shutdownWith(cause Cause, err error) {
+	lifetime.setCause(cause, err)
+	lifetime.cancelFunc()
+}
+
+// setCause records the given cause if no cause has been recorded yet.
+func (lifetime *Lifetime) setCause(cause Cause, err error) {
+	lifetime.causeMu.Lock()
+	defer lifetime.causeMu.Unlock()
+	if lifetime.cause != CauseNone {
+		return
+	}
+	lifetime.cause = cause
+	lifetime.causeErr = err
+	select {
+	case <-lifetime.ready:
+	default:
+		lifetime.causeDuringStartup = true
+	}
+}
+
+// causeOf returns the cause that should be recorded when the given error triggers a shutdown.
+func causeOf(err error) Cause {
+	if errors.Is(err, ErrShutdownSignalReceived) || errors.Is(err, ErrImmediateShutdownSignalReceived) {
+		return CauseSignal
+	}
+	return CauseServiceError
+}
@@ -0,0 +1,72 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+)
+
+func TestLifetime_Cause(t *testing.T) {
+	serviceErr := errors.New("service failed")
+
+	tests := []struct {
+		name     string
+		trigger  func(lt *lifetime.Lifetime, cancelParent context.CancelFunc)
+		expCause lifetime.Cause
+		expErr   error
+	}{
+		{
+			name: "manual",
+			trigger: func(lt *lifetime.Lifetime, cancelParent context.CancelFunc) {
+				lt.Shutdown()
+			},
+			expCause: lifetime.CauseManual,
+		},
+		{
+			name: "service error",
+			trigger: func(lt *lifetime.Lifetime, cancelParent context.CancelFunc) {
+				lt.ReportError(serviceErr)
+			},
+			expCause: lifetime.CauseServiceError,
+			expErr:   serviceErr,
+		},
+		{
+			name: "parent context",
+			trigger: func(lt *lifetime.Lifetime, cancelParent context.CancelFunc) {
+				cancelParent()
+			},
+			expCause: lifetime.CauseParentContext,
+			expErr:   context.Canceled,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			parent, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			lt := lifetime.New(parent).HandleErrors(nil)
+			lt.Start(newBlockingService())
+
+			if cause, _ := lt.Cause(); cause != lifetime.CauseNone {
+				t.Errorf("expected no cause before shutdown, got %s", cause)
+			}
+
+			tc.trigger(lt, cancel)
+			<-lt.Done()
+			if err := lt.Wait(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			cause, err := lt.Cause()
+			if cause != tc.expCause {
+				t.Errorf("expected cause %s, got %s", tc.expCause, cause)
+			}
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("expected error %v, got %v", tc.expErr, err)
+			}
+		})
+	}
+}
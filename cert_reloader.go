@@ -0,0 +1,144 @@
+package lifetime
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CertReloaderOption is used to configure a CertReloader.
+type CertReloaderOption func(reloader *CertReloader)
+
+// WithCertReloadInterval sets how often the certificate and key files are checked for changes.
+// Defaults to 10 seconds.
+func WithCertReloadInterval(interval time.Duration) CertReloaderOption {
+	return func(reloader *CertReloader) {
+		if interval > 0 {
+			reloader.interval = interval
+		}
+	}
+}
+
+// WithCertReloadErrorHandler sets a func that is called when a changed certificate could not be loaded.
+// The previous certificate continues to be used either way.
+// Defaults to ignoring the error.
+func WithCertReloadErrorHandler(handler func(err error)) CertReloaderOption {
+	return func(reloader *CertReloader) {
+		reloader.errorHandler = handler
+	}
+}
+
+// NewCertReloader loads the given certificate and key files and returns a CertReloader that serves them.
+// An error is returned if the files cannot be loaded.
+// The reloader is a Service: once it is started it watches the files and swaps in the new certificate
+// whenever they change, so certificates can be rotated without a restart.
+func NewCertReloader(certFile string, keyFile string, opts ...CertReloaderOption) (*CertReloader, error) {
+	reloader := &CertReloader{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		interval:     time.Second * 10,
+		errorHandler: func(err error) {},
+		stop:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(reloader)
+	}
+	if _, err := reloader.reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// CertReloader serves a certificate that is reloaded from disk whenever it changes.
+// It can be shared by any number of servers, such as those created by NewHTTPHandlerService using
+// WithHTTPTLSConfig, and gRPC servers using credentials.NewTLS.
+type CertReloader struct {
+	certFile     string
+	keyFile      string
+	interval     time.Duration
+	errorHandler func(err error)
+
+	// cert holds the current *tls.Certificate.
+	cert atomic.Value
+	// modified is the time the files were last modified when they were loaded.
+	modified time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// GetCertificate returns the current certificate. It can be used as tls.Config.GetCertificate.
+func (reloader *CertReloader) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return reloader.cert.Load().(*tls.Certificate), nil
+}
+
+// TLSConfig returns a new tls.Config that uses the current certificate.
+func (reloader *CertReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+}
+
+// Start will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal.
+func (reloader *CertReloader) Start() error {
+	ticker := time.NewTicker(reloader.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-reloader.stop:
+			return nil
+		case <-ticker.C:
+			if _, err := reloader.reload(); err != nil {
+				reloader.errorHandler(err)
+			}
+		}
+	}
+}
+
+// Stop will stop the service.
+// Stop is not called if Start returned an error.
+func (reloader *CertReloader) Stop() {
+	reloader.stopOnce.Do(func() {
+		close(reloader.stop)
+	})
+}
+
+// reload loads the certificate if either of the files has been modified since it was last loaded.
+// Returns true if a new certificate was loaded.
+func (reloader *CertReloader) reload() (bool, error) {
+	modified, err := lastModified(reloader.certFile, reloader.keyFile)
+	if err != nil {
+		return false, err
+	}
+	if !modified.After(reloader.modified) {
+		return false, nil
+	}
+	cert, err := tls.LoadX509KeyPair(reloader.certFile, reloader.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("could not load certificate: %w", err)
+	}
+	reloader.cert.Store(&cert)
+	reloader.modified = modified
+	return true, nil
+}
+
+// lastModified returns the latest modification time of the given files.
+func lastModified(files ...string) (time.Time, error) {
+	var latest time.Time
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("could not stat certificate file: %w", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,114 @@
+package lifetime_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/tomwright/lifetime"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert writes a self-signed certificate with the given common name to the given files.
+func writeCert(t *testing.T, certFile string, keyFile string, commonName string, modified time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("could not marshal key: %v", err)
+	}
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("could not write certificate: %v", err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatalf("could not write key: %v", err)
+	}
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.Chtimes(file, modified, modified); err != nil {
+			t.Fatalf("could not set modification time: %v", err)
+		}
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lifetime")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeCert(t, certFile, keyFile, "first", time.Now().Add(-time.Minute))
+
+	reloader, err := lifetime.NewCertReloader(certFile, keyFile, lifetime.WithCertReloadInterval(time.Millisecond*10))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not find a free port: %v", err)
+	}
+	addr := lis.Addr().String()
+	_ = lis.Close()
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(reloader)
+	svc := lifetime.NewHTTPHandlerService(addr, http.NotFoundHandler(), lifetime.WithHTTPTLSConfig(reloader.TLSConfig()))
+	lt.Start(svc)
+	<-svc.(lifetime.Readier).Ready()
+
+	commonName := func() string {
+		conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			t.Fatalf("could not connect: %v", err)
+		}
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+
+	if got := commonName(); got != "first" {
+		t.Errorf("expected the first certificate, got %s", got)
+	}
+
+	writeCert(t, certFile, keyFile, "second", time.Now())
+	deadline := time.Now().Add(time.Second * 5)
+	for commonName() != "second" {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the certificate to be reloaded")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+}
+
+func TestNewCertReloader_MissingFiles(t *testing.T) {
+	if _, err := lifetime.NewCertReloader("missing.crt", "missing.key"); err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,134 @@
+package lifetime
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrChaosFailure is reported for a service when chaos mode injects a failure.
+var ErrChaosFailure = errors.New("chaos failure injected")
+
+// ChaosOption is used to configure chaos mode.
+type ChaosOption func(chaos *chaos)
+
+// WithChaosServiceFailures causes each service to fail with the given probability, at a random
+// time within the given duration of being started. The failure is reported as an ErrChaosFailure
+// for the service.
+func WithChaosServiceFailures(probability float64, within time.Duration) ChaosOption {
+	return func(chaos *chaos) {
+		chaos.failureProbability = probability
+		chaos.failureWithin = within
+	}
+}
+
+// WithChaosStopDelays causes the Stop func of each service to be delayed with the given probability,
+// by a random duration up to max.
+func WithChaosStopDelays(probability float64, max time.Duration) ChaosOption {
+	return func(chaos *chaos) {
+		chaos.stopDelayProbability = probability
+		chaos.stopDelayMax = max
+	}
+}
+
+// WithChaosSignals causes a spurious SIGTERM to be received with the given probability, at a random
+// time within the given duration of the lifetime being created.
+func WithChaosSignals(probability float64, within time.Duration) ChaosOption {
+	return func(chaos *chaos) {
+		chaos.signalProbability = probability
+		chaos.signalWithin = within
+	}
+}
+
+// WithChaos enables chaos mode, which randomly injects service failures, delayed stops and spurious
+// shutdown signals as configured by the given options. The same seed always results in the same
+// decisions being made, so a failing run can be reproduced.
+// Chaos mode is intended for tests only and must never be enabled in production.
+func WithChaos(seed int64, opts ...ChaosOption) Option {
+	return func(lifetime *Lifetime) {
+		chaos := &chaos{
+			lifetime: lifetime,
+			rand:     rand.New(rand.NewSource(seed)),
+		}
+		for _, opt := range opts {
+			opt(chaos)
+		}
+		lifetime.chaos = chaos
+	}
+}
+
+// chaos injects faults into a lifetime.
+type chaos struct {
+	lifetime *Lifetime
+
+	mu   sync.Mutex
+	rand *rand.Rand
+
+	failureProbability   float64
+	failureWithin        time.Duration
+	stopDelayProbability float64
+	stopDelayMax         time.Duration
+	signalProbability    float64
+	signalWithin         time.Duration
+}
+
+// roll decides whether to inject a fault with the given probability and, if so,
+// returns a random duration up to max.
+func (chaos *chaos) roll(probability float64, max time.Duration) (bool, time.Duration) {
+	chaos.mu.Lock()
+	defer chaos.mu.Unlock()
+	if probability <= 0 || chaos.rand.Float64() >= probability {
+		return false, 0
+	}
+	if max <= 0 {
+		return true, 0
+	}
+	return true, time.Duration(chaos.rand.Int63n(int64(max)))
+}
+
+// start schedules a spurious shutdown signal, if one should be injected.
+func (chaos *chaos) start() {
+	chaos.lifetime.log(LevelWarn, "lifetime chaos mode enabled")
+
+	inject, delay := chaos.roll(chaos.signalProbability, chaos.signalWithin)
+	if !inject {
+		return
+	}
+	go func() {
+		if !sleepContext(chaos.lifetime.ctx, delay) {
+			return
+		}
+		chaos.lifetime.log(LevelWarn, "lifetime chaos injected", Field{Key: "fault", Value: "signal"})
+		chaos.lifetime.errors.pushAlways(&SignalError{Signal: syscall.SIGTERM, Err: ErrShutdownSignalReceived})
+	}()
+}
+
+// serviceStarted schedules a failure of the given service, if one should be injected.
+func (chaos *chaos) serviceStarted(managed *managedService) {
+	inject, delay := chaos.roll(chaos.failureProbability, chaos.failureWithin)
+	if !inject {
+		return
+	}
+	go func() {
+		if !sleepContext(managed.ctx, delay) {
+			return
+		}
+		managed.log(LevelWarn, "lifetime chaos injected", nil, Field{Key: "fault", Value: "service-failure"})
+		managed.lifetime.reportError(managed.wrapError(ErrChaosFailure))
+	}()
+}
+
+// beforeStop delays the Stop func of the given service, if a delay should be injected.
+func (chaos *chaos) beforeStop(managed *managedService) {
+	inject, delay := chaos.roll(chaos.stopDelayProbability, chaos.stopDelayMax)
+	if !inject {
+		return
+	}
+	managed.log(LevelWarn, "lifetime chaos injected", nil,
+		Field{Key: "fault", Value: "stop-delay"},
+		Field{Key: "delay", Value: delay},
+	)
+	time.Sleep(delay)
+}
@@ -0,0 +1,47 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+func TestWithChaos_ServiceFailures(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithChaos(1,
+		lifetime.WithChaosServiceFailures(1, time.Millisecond*10),
+		lifetime.WithChaosStopDelays(1, time.Millisecond*10),
+	)).HandleErrors(nil)
+
+	lt.Start(newBlockingService())
+
+	done := make(chan error)
+	go func() {
+		done <- lt.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the injected failure to shutdown the lifetime")
+	}
+
+	cause, err := lt.Cause()
+	if cause != lifetime.CauseServiceError || !errors.Is(err, lifetime.ErrChaosFailure) {
+		t.Errorf("expected chaos failure, got %s: %v", cause, err)
+	}
+}
+
+func TestWithChaos_Signals(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithChaos(1,
+		lifetime.WithChaosSignals(1, time.Millisecond*10),
+	)).HandleErrors(nil)
+
+	lt.Start(newBlockingService())
+	_ = lt.Wait()
+
+	if cause, _ := lt.Cause(); cause != lifetime.CauseSignal {
+		t.Errorf("expected signal cause, got %s", cause)
+	}
+}
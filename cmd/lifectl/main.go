@@ -0,0 +1,112 @@
+// Command lifectl manages running applications that use lifetime through their control socket,
+// or queries their probe handler over HTTP.
+//
+// Usage:
+//
+//	lifectl -socket /run/app/control.sock status
+//	lifectl -socket /run/app/control.sock shutdown
+//	lifectl -socket /run/app/control.sock restart <service>
+//	lifectl -socket /run/app/control.sock log-level <level>
+//	lifectl -url http://localhost:8081 status
+//	lifectl -url http://localhost:8081 buildinfo
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/tomwright/lifetime/lifectl"
+	"os"
+	"time"
+)
+
+func main() {
+	socket := flag.String("socket", "", "Path to the control socket of the application.")
+	url := flag.String("url", "", "Base URL of the probe handler of the application.")
+	timeout := flag.Duration("timeout", time.Second*10, "How long to wait for the command to complete.")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var err error
+	switch {
+	case *socket != "":
+		err = runSocket(ctx, *socket, flag.Args())
+	case *url != "":
+		err = runURL(ctx, *url, flag.Args())
+	default:
+		err = errors.New("one of -socket or -url is required")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "lifectl:", err)
+		os.Exit(1)
+	}
+}
+
+// runSocket executes the given command against the control socket at the given path.
+func runSocket(ctx context.Context, path string, args []string) error {
+	if len(args) == 0 {
+		return errors.New("a command is required: status, shutdown, restart or log-level")
+	}
+	client, err := lifectl.Dial(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	switch args[0] {
+	case "status":
+		status, err := client.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return printJSON(status)
+	case "shutdown":
+		return client.Shutdown(ctx)
+	case "restart":
+		if len(args) != 2 {
+			return errors.New("usage: restart <service>")
+		}
+		return client.Restart(ctx, args[1])
+	case "log-level":
+		if len(args) != 2 {
+			return errors.New("usage: log-level <level>")
+		}
+		return client.SetLogLevel(ctx, args[1])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// runURL executes the given command against the probe handler at the given base URL.
+func runURL(ctx context.Context, url string, args []string) error {
+	if len(args) == 0 {
+		return errors.New("a command is required: status or buildinfo")
+	}
+	switch args[0] {
+	case "status":
+		status, err := lifectl.FetchStatus(ctx, url)
+		if err != nil {
+			return err
+		}
+		return printJSON(status)
+	case "buildinfo":
+		report, err := lifectl.FetchBuildInfo(ctx, url)
+		if err != nil {
+			return err
+		}
+		return printJSON(report)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// printJSON writes the given value to stdout as indented JSON.
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,112 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+)
+
+func TestLifetime_Concurrency(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+		wg := &sync.WaitGroup{}
+		start := make(chan struct{})
+		for j := 0; j < 8; j++ {
+			wg.Add(4)
+			go func() {
+				defer wg.Done()
+				<-start
+				lt.Start(newBlockingService())
+			}()
+			go func() {
+				defer wg.Done()
+				<-start
+				lt.ReportError(errors.New("something went wrong"))
+			}()
+			go func() {
+				defer wg.Done()
+				<-start
+				_ = lt.Status()
+				_ = lt.Services()
+			}()
+			go func() {
+				defer wg.Done()
+				<-start
+				_ = lt.Wait()
+			}()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			lt.Shutdown()
+		}()
+		close(start)
+		wg.Wait()
+
+		if err := lt.Wait(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestLifetime_Concurrency_StartAfterWait(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svc := newBlockingService()
+	handle := lt.Start(svc)
+	<-handle.Done()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestServiceHandle_Concurrency(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		lt := lifetime.New(context.Background()).HandleErrors(nil)
+		handle := lt.Start(&restartableService{})
+		lazy := lt.Lazy(&pausableService{blockingService: newBlockingService()})
+
+		wg := &sync.WaitGroup{}
+		start := make(chan struct{})
+		for j := 0; j < 4; j++ {
+			wg.Add(4)
+			go func() {
+				defer wg.Done()
+				<-start
+				handle.Restart()
+			}()
+			go func() {
+				defer wg.Done()
+				<-start
+				if err := lazy.Ensure(context.Background()); err == nil {
+					_ = lazy.Pause()
+					_ = lazy.Resume()
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				<-start
+				_ = lt.PlanShutdown()
+			}()
+			go func() {
+				defer wg.Done()
+				<-start
+				lt.Shutdown()
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		if err := lt.Wait(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
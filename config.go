@@ -0,0 +1,148 @@
+package lifetime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config is an alternative to functional options for those that prefer to load configuration
+// into a single struct. The zero value of each field leaves the default in place.
+type Config struct {
+	// Preset is applied before the rest of the config. See WithPreset.
+	Preset Preset
+	// ShutdownTimeout is how long a graceful shutdown can take. See WithShutdownTimeout.
+	ShutdownTimeout time.Duration
+	// StartupTimeout is how long the application has to become ready. See WithStartupTimeout.
+	StartupTimeout time.Duration
+	// DrainDelay is how long to wait after a shutdown is triggered before stopping services. See WithDrainDelay.
+	DrainDelay time.Duration
+	// StopWarning is how long a service can take to stop before a warning is logged. See WithStopWarning.
+	// A negative value disables the warnings.
+	StopWarning time.Duration
+	// Signals are the signals that are listened for by Init. See WithSignals.
+	Signals []os.Signal
+	// SignalEscalation is the number of shutdown signals that cause an immediate shutdown. See WithSignalEscalation.
+	// A negative value disables the escalation.
+	SignalEscalation int
+	// ImmediateShutdownReturn causes Wait to return on an immediate shutdown rather than exiting.
+	// See WithImmediateShutdownReturn.
+	ImmediateShutdownReturn bool
+	// ImmediateShutdownExitCode is the code the application exits with on an immediate shutdown.
+	// See WithImmediateShutdownExitCode.
+	ImmediateShutdownExitCode int
+	// ImmediateShutdownExitCodeSet causes ImmediateShutdownExitCode to be used even if it is 0.
+	ImmediateShutdownExitCodeSet bool
+	// ErrorQueueSize is the number of errors that can be queued for the error handler. See WithErrorQueueSize.
+	ErrorQueueSize int
+	// Logger is the logger used by the lifetime. See WithLogger.
+	Logger Logger
+	// LogLevel is the name of the minimum level that is logged, such as info. See WithLogLevel.
+	LogLevel string
+	// ProbeAddr is the address the probe server is served on. The probe server is not started if it is empty.
+	// See ProbeService.
+	ProbeAddr string
+}
+
+// Validate returns an error describing every problem with the config.
+func (config Config) Validate() error {
+	problems := make([]string, 0)
+	if config.Preset != "" {
+		if _, ok := config.Preset.options(); !ok {
+			problems = append(problems, fmt.Sprintf("unknown preset: %s", config.Preset))
+		}
+	}
+	if config.ShutdownTimeout < 0 {
+		problems = append(problems, "shutdown timeout must not be negative")
+	}
+	if config.StartupTimeout < 0 {
+		problems = append(problems, "startup timeout must not be negative")
+	}
+	if config.DrainDelay < 0 {
+		problems = append(problems, "drain delay must not be negative")
+	}
+	if config.ShutdownTimeout > 0 && config.DrainDelay >= config.ShutdownTimeout {
+		problems = append(problems, "drain delay must be less than the shutdown timeout")
+	}
+	if config.ImmediateShutdownExitCode < 0 || config.ImmediateShutdownExitCode > 255 {
+		problems = append(problems, "immediate shutdown exit code must be between 0 and 255")
+	}
+	if config.ErrorQueueSize < 0 {
+		problems = append(problems, "error queue size must not be negative")
+	}
+	if config.LogLevel != "" {
+		if _, err := ParseLevel(config.LogLevel); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New("invalid config: " + strings.Join(problems, "; "))
+}
+
+// Options returns the options that are equivalent to the config.
+// The config should be validated first.
+func (config Config) Options() []Option {
+	opts := make([]Option, 0)
+	if config.Preset != "" {
+		opts = append(opts, WithPreset(config.Preset))
+	}
+	if config.ShutdownTimeout > 0 {
+		opts = append(opts, WithShutdownTimeout(config.ShutdownTimeout))
+	}
+	if config.StartupTimeout > 0 {
+		opts = append(opts, WithStartupTimeout(config.StartupTimeout))
+	}
+	if config.DrainDelay > 0 {
+		opts = append(opts, WithDrainDelay(config.DrainDelay))
+	}
+	if config.StopWarning > 0 {
+		opts = append(opts, WithStopWarning(config.StopWarning))
+	} else if config.StopWarning < 0 {
+		opts = append(opts, WithStopWarning(0))
+	}
+	if len(config.Signals) > 0 {
+		opts = append(opts, WithSignals(config.Signals...))
+	}
+	if config.SignalEscalation > 0 {
+		opts = append(opts, WithSignalEscalation(config.SignalEscalation))
+	} else if config.SignalEscalation < 0 {
+		opts = append(opts, WithSignalEscalation(0))
+	}
+	if config.ImmediateShutdownReturn {
+		opts = append(opts, WithImmediateShutdownReturn())
+	}
+	if config.ImmediateShutdownExitCodeSet || config.ImmediateShutdownExitCode > 0 {
+		opts = append(opts, WithImmediateShutdownExitCode(config.ImmediateShutdownExitCode))
+	}
+	if config.ErrorQueueSize > 0 {
+		opts = append(opts, WithErrorQueueSize(config.ErrorQueueSize))
+	}
+	if config.Logger != nil {
+		opts = append(opts, WithLogger(config.Logger))
+	}
+	if config.LogLevel != "" {
+		if level, err := ParseLevel(config.LogLevel); err == nil {
+			opts = append(opts, WithLogLevel(level))
+		}
+	}
+	return opts
+}
+
+// NewWithConfig validates the given config and returns a new Lifetime configured by it.
+// Any options given are applied after the config.
+// If ProbeAddr is set the probe server is started straight away.
+func NewWithConfig(ctx context.Context, config Config, opts ...Option) (*Lifetime, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	lifetime := New(ctx, append(config.Options(), opts...)...)
+	if config.ProbeAddr != "" {
+		lifetime.Start(lifetime.ProbeService(config.ProbeAddr), WithName("probes"))
+	}
+	return lifetime, nil
+}
@@ -0,0 +1,75 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"github.com/tomwright/lifetime/lifetimetest"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	config := lifetime.Config{
+		Preset:          "unknown",
+		ShutdownTimeout: time.Second,
+		DrainDelay:      time.Second * 2,
+		LogLevel:        "loud",
+	}
+	err := config.Validate()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	for _, exp := range []string{"unknown preset", "drain delay must be less than the shutdown timeout", "unknown log level"} {
+		if !strings.Contains(err.Error(), exp) {
+			t.Errorf("expected error to contain %q, got %q", exp, err.Error())
+		}
+	}
+
+	if _, err := lifetime.NewWithConfig(context.Background(), config); err == nil {
+		t.Errorf("expected NewWithConfig to return the validation error")
+	}
+}
+
+func TestNewWithConfig(t *testing.T) {
+	lt, err := lifetime.NewWithConfig(context.Background(), lifetime.Config{
+		Preset:          lifetime.PresetKubernetes,
+		ShutdownTimeout: time.Millisecond * 50,
+		DrainDelay:      time.Millisecond,
+		LogLevel:        "error",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lt.HandleErrors(nil)
+	lt.Start(&stuckService{})
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != lifetime.ErrShutdownTimeout {
+		t.Errorf("expected ErrShutdownTimeout, got %v", err)
+	}
+}
+
+func TestConfig_ImmediateShutdownExitCodeZero(t *testing.T) {
+	signals := lifetimetest.NewSignals()
+	exits := lifetimetest.NewExitRecorder()
+	lt, err := lifetime.NewWithConfig(context.Background(), lifetime.Config{
+		ImmediateShutdownExitCode:    0,
+		ImmediateShutdownExitCodeSet: true,
+	}, lifetime.WithSignalSource(signals), lifetime.WithExitFunc(exits.Exit))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lt.Init()
+	lt.Start(newBlockingService())
+
+	signals.Send(syscall.SIGTERM)
+	signals.Send(syscall.SIGTERM)
+	<-exits.Exited()
+	_ = lt.Wait()
+
+	if codes := exits.Codes(); len(codes) != 1 || codes[0] != 0 {
+		t.Errorf("expected a single exit with code 0, got %v", codes)
+	}
+}
@@ -0,0 +1,87 @@
+package lifetime
+
+import (
+	"context"
+	"sync"
+)
+
+// contextKey is the key used to store a Lifetime in a context.
+type contextKey struct{}
+
+// IntoContext returns a copy of the given context that holds the given lifetime.
+// It can be retrieved with FromContext.
+func IntoContext(ctx context.Context, lifetime *Lifetime) context.Context {
+	return context.WithValue(ctx, contextKey{}, lifetime)
+}
+
+// FromContext returns the lifetime stored in the given context by IntoContext.
+// The bool is false if the context does not hold a lifetime.
+func FromContext(ctx context.Context) (*Lifetime, bool) {
+	lifetime, ok := ctx.Value(contextKey{}).(*Lifetime)
+	return lifetime, ok && lifetime != nil
+}
+
+// WithContextValue adds a value to the context of the service, so that it can receive configuration or
+// an identity through its context rather than global state. The context is given to services that implement
+// ContextStarter. It can be given more than once to add multiple values.
+func WithContextValue(key interface{}, value interface{}) StartOption {
+	return func(managed *managedService) {
+		managed.values = append(managed.values, contextValue{key: key, value: value})
+	}
+}
+
+// contextValue is a value that is added to the context of a service.
+type contextValue struct {
+	key   interface{}
+	value interface{}
+}
+
+// ReportError reports the given error to the lifetime error handler.
+// Using the default error handler this will trigger a graceful shutdown.
+// It never blocks.
+func (lifetime *Lifetime) ReportError(err error) {
+	if err == nil {
+		return
+	}
+	lifetime.reportError(err)
+}
+
+// TrackTask registers an in-flight task that Wait should wait for, such as a request
+// that must be allowed to finish during a shutdown.
+// The returned func must be called once the task is complete. It is safe to call it more than once.
+func (lifetime *Lifetime) TrackTask() func() {
+	lifetime.serviceWg.Add(1)
+	once := &sync.Once{}
+	return func() {
+		once.Do(lifetime.serviceWg.Done)
+	}
+}
+
+// AttachWaitGroup makes Wait wait for the given wait group, so goroutines that are already tracked by it
+// are allowed to finish during a shutdown.
+func (lifetime *Lifetime) AttachWaitGroup(wg *sync.WaitGroup) {
+	done := lifetime.TrackTask()
+	go func() {
+		wg.Wait()
+		done()
+	}()
+}
+
+// DoneOr returns a copy of the given context that is also cancelled when the lifetime shuts down.
+// The returned cancel func should be called once the context is no longer needed to release its resources.
+func (lifetime *Lifetime) DoneOr(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-lifetime.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// ShuttingDown returns true once a shutdown has been triggered.
+func (lifetime *Lifetime) ShuttingDown() bool {
+	return lifetime.ctx.Err() != nil
+}
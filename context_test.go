@@ -0,0 +1,120 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFromContext(t *testing.T) {
+	if _, ok := lifetime.FromContext(context.Background()); ok {
+		t.Errorf("expected no lifetime in an empty context")
+	}
+
+	lt := lifetime.New(context.Background())
+	ctx := lifetime.IntoContext(context.Background(), lt)
+
+	got, ok := lifetime.FromContext(ctx)
+	if !ok {
+		t.Fatalf("expected lifetime in context")
+	}
+	if got != lt {
+		t.Errorf("expected the same lifetime to be returned")
+	}
+}
+
+func TestLifetime_TrackTask(t *testing.T) {
+	lt := lifetime.New(context.Background())
+
+	done := lt.TrackTask()
+	lt.Shutdown()
+
+	waited := make(chan struct{})
+	go func() {
+		_ = lt.Wait()
+		close(waited)
+	}()
+
+	select {
+	case <-waited:
+		t.Fatalf("expected Wait to block until the task is done")
+	default:
+	}
+
+	if !lt.ShuttingDown() {
+		t.Errorf("expected lifetime to be shutting down")
+	}
+
+	done()
+	done()
+	<-waited
+}
+
+func TestLifetime_AttachWaitGroup(t *testing.T) {
+	lt := lifetime.New(context.Background())
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	finished := make(chan struct{})
+	go func() {
+		defer wg.Done()
+		time.Sleep(time.Millisecond * 50)
+		close(finished)
+	}()
+
+	lt.AttachWaitGroup(wg)
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	select {
+	case <-finished:
+	default:
+		t.Errorf("expected Wait to wait for the wait group")
+	}
+}
+
+func TestLifetime_DoneOr(t *testing.T) {
+	lt := lifetime.New(context.Background())
+
+	ctx, cancel := lt.DoneOr(context.Background())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		t.Fatalf("expected context not to be done before the shutdown")
+	}
+
+	lt.Shutdown()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Errorf("expected context to be done once the lifetime shuts down")
+	}
+}
+
+// tenantKey and regionKey are the context keys used to test WithContextValue.
+type tenantKey struct{}
+type regionKey struct{}
+
+func TestWithContextValue(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	svc := &contextService{started: make(chan struct{})}
+	handle := lt.Start(svc,
+		lifetime.WithContextValue(tenantKey{}, "acme"),
+		lifetime.WithContextValue(regionKey{}, "eu-west-1"),
+	)
+	<-svc.started
+
+	if got := handle.Context().Value(tenantKey{}); got != "acme" {
+		t.Errorf("expected tenant acme, got %v", got)
+	}
+	if got := handle.Context().Value(regionKey{}); got != "eu-west-1" {
+		t.Errorf("expected region eu-west-1, got %v", got)
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+}
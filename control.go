@@ -0,0 +1,312 @@
+package lifetime
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ControlCommand is a command that can be sent to the control socket.
+type ControlCommand string
+
+// The commands understood by the control socket.
+const (
+	// ControlStatus responds with the Status of the lifetime.
+	ControlStatus ControlCommand = "status"
+	// ControlShutdown triggers a graceful shutdown of the application.
+	ControlShutdown ControlCommand = "shutdown"
+	// ControlRestart restarts the running service with the name given in the request.
+	ControlRestart ControlCommand = "restart"
+	// ControlSetLogLevel sets the log level to the level given in the request.
+	ControlSetLogLevel ControlCommand = "set-log-level"
+)
+
+// ControlRequest is a request sent to the control socket.
+// Requests are JSON encoded, one per line.
+type ControlRequest struct {
+	// Command is the command to execute.
+	Command ControlCommand `json:"command"`
+	// Service is the name of the service the command applies to, for ControlRestart.
+	Service string `json:"service,omitempty"`
+	// Level is the name of the log level, for ControlSetLogLevel.
+	Level string `json:"level,omitempty"`
+}
+
+// ControlResponse is the response to a ControlRequest.
+// Responses are JSON encoded, one per line.
+type ControlResponse struct {
+	// OK is true if the command was executed.
+	OK bool `json:"ok"`
+	// Error describes why the command could not be executed, if OK is false.
+	Error string `json:"error,omitempty"`
+	// Status is the status of the lifetime, for ControlStatus.
+	Status *Status `json:"status,omitempty"`
+}
+
+// ErrUnknownControlCommand is used when the control socket receives a command it does not understand.
+var ErrUnknownControlCommand = errors.New("unknown control command")
+
+// ControlOption is used to configure the control socket.
+type ControlOption func(service *controlService)
+
+// WithControlSocketMode sets the file mode of the control socket.
+// Defaults to 0600, so only the user the application runs as can connect.
+func WithControlSocketMode(mode os.FileMode) ControlOption {
+	return func(service *controlService) {
+		service.mode = mode
+	}
+}
+
+// ControlService returns a service that listens on a unix domain socket at the given path, so that tooling
+// can query the status of the application, trigger a shutdown, restart services and change the log level
+// without exposing an HTTP admin port. See ControlRequest.
+// A socket left at the path by a previous run is removed first, but the service fails to start if any other
+// kind of file exists at the path.
+func (lifetime *Lifetime) ControlService(path string, opts ...ControlOption) Service {
+	service := &controlService{
+		lifetime: lifetime,
+		path:     path,
+		mode:     0600,
+		ready:    make(chan struct{}),
+		conns:    map[net.Conn]struct{}{},
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service
+}
+
+// controlService serves the control socket.
+type controlService struct {
+	lifetime *Lifetime
+	path     string
+	mode     os.FileMode
+	ready    chan struct{}
+
+	mu sync.Mutex
+	// listener is the listener of the current run, or nil if the service is not listening.
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	// stopPending is true if Stop was called before Start was listening, so that Start returns straight away.
+	stopPending bool
+	wg          sync.WaitGroup
+}
+
+// Start listens on the socket and serves connections until the service is stopped.
+// The service can be started again once it has been stopped.
+func (service *controlService) Start() error {
+	lis, err := service.listen()
+	if err != nil {
+		service.mu.Lock()
+		service.stopPending = false
+		service.mu.Unlock()
+		return err
+	}
+
+	service.mu.Lock()
+	if service.stopPending {
+		service.stopPending = false
+		service.mu.Unlock()
+		_ = lis.Close()
+		_ = os.Remove(service.path)
+		return nil
+	}
+	service.listener = lis
+	ready := service.ready
+	service.mu.Unlock()
+	close(ready)
+
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if service.stopped(lis) {
+				return nil
+			}
+			return fmt.Errorf("could not accept control connection: %w", err)
+		}
+		if !service.track(lis, conn) {
+			_ = conn.Close()
+			return nil
+		}
+		go service.serve(conn)
+	}
+}
+
+// Stop closes the socket and any open connections, and waits for in-flight commands to complete.
+func (service *controlService) Stop() {
+	service.mu.Lock()
+	if service.listener != nil {
+		_ = service.listener.Close()
+		_ = os.Remove(service.path)
+		service.listener = nil
+		// The next run reports that it is ready once it is listening.
+		service.ready = make(chan struct{})
+	} else {
+		service.stopPending = true
+	}
+	for conn := range service.conns {
+		_ = conn.Close()
+	}
+	service.mu.Unlock()
+	service.wg.Wait()
+}
+
+// listen creates the control socket. The socket is created in a new directory that only the current user can
+// access, given its mode and then moved into place, so that it is never reachable with looser permissions.
+func (service *controlService) listen() (net.Listener, error) {
+	if err := removeStaleSocket(service.path); err != nil {
+		return nil, err
+	}
+	dir, err := ioutil.TempDir(filepath.Dir(service.path), ".control")
+	if err != nil {
+		return nil, fmt.Errorf("could not create control socket directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "sock")
+	lis, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, fmt.Errorf("could not listen on control socket: %w", err)
+	}
+	// The socket is moved, so it is removed by Stop rather than when the listener is closed.
+	lis.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, service.mode); err != nil {
+		_ = lis.Close()
+		return nil, fmt.Errorf("could not set control socket permissions: %w", err)
+	}
+	if err := os.Rename(tmp, service.path); err != nil {
+		_ = lis.Close()
+		return nil, fmt.Errorf("could not move control socket into place: %w", err)
+	}
+	return lis, nil
+}
+
+// removeStaleSocket removes the socket at the given path, if there is one.
+// An error is returned if any other kind of file exists at the path.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not check for an existing control socket: %w", err)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("could not create control socket: %s already exists and is not a socket", path)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("could not remove existing control socket: %w", err)
+	}
+	return nil
+}
+
+// Ready returns a channel that is closed once the service is listening on the socket.
+func (service *controlService) Ready() <-chan struct{} {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	return service.ready
+}
+
+// stopped returns true if the run that is listening with the given listener has been stopped.
+func (service *controlService) stopped(lis net.Listener) bool {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	return service.listener != lis
+}
+
+// track records the given connection so that it is closed when the service is stopped.
+// It returns false if the run that accepted the connection with the given listener has already been stopped.
+func (service *controlService) track(lis net.Listener, conn net.Conn) bool {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if service.listener != lis {
+		return false
+	}
+	service.conns[conn] = struct{}{}
+	service.wg.Add(1)
+	return true
+}
+
+// serve executes each request received on the given connection.
+func (service *controlService) serve(conn net.Conn) {
+	defer func() {
+		service.mu.Lock()
+		delete(service.conns, conn)
+		service.mu.Unlock()
+		_ = conn.Close()
+		service.wg.Done()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var request ControlRequest
+		var response ControlResponse
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			response.Error = fmt.Sprintf("could not decode request: %s", err)
+		} else {
+			response = service.control(request)
+		}
+		if err := encoder.Encode(response); err != nil {
+			return
+		}
+		if response.OK && request.Command == ControlShutdown {
+			// The shutdown is triggered once the response has been sent, as it closes the connection.
+			go service.lifetime.shutdownWith(CauseManual, nil)
+		}
+	}
+}
+
+// control executes the given request.
+// Shutdowns are triggered by the caller once the response has been sent.
+func (service *controlService) control(request ControlRequest) ControlResponse {
+	lifetime := service.lifetime
+	lifetime.log(LevelInfo, "lifetime control command",
+		Field{Key: "command", Value: string(request.Command)},
+	)
+	switch request.Command {
+	case ControlStatus:
+		status := lifetime.Status()
+		return ControlResponse{OK: true, Status: &status}
+	case ControlShutdown:
+		return ControlResponse{OK: true}
+	case ControlRestart:
+		managed := lifetime.runningService(request.Service)
+		if managed == nil {
+			return ControlResponse{Error: fmt.Sprintf("service %q is not running", request.Service)}
+		}
+		if managed.svc == Service(service) {
+			// Restarting the control socket would wait for this request to complete.
+			return ControlResponse{Error: "the control socket cannot restart itself"}
+		}
+		managed.handle.Restart()
+		return ControlResponse{OK: true}
+	case ControlSetLogLevel:
+		level, err := ParseLevel(request.Level)
+		if err != nil {
+			return ControlResponse{Error: err.Error()}
+		}
+		lifetime.SetLogLevel(level)
+		return ControlResponse{OK: true}
+	default:
+		return ControlResponse{Error: fmt.Sprintf("%s: %q", ErrUnknownControlCommand, request.Command)}
+	}
+}
+
+// runningService returns the running service with the given name, or nil if there isn't one.
+func (lifetime *Lifetime) runningService(name string) *managedService {
+	lifetime.servicesMu.Lock()
+	defer lifetime.servicesMu.Unlock()
+	for managed := range lifetime.services {
+		if managed.name == name {
+			return managed
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package lifetime_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/tomwright/lifetime"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLifetime_ControlService(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lifetime")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "control.sock")
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	listening := make(chan struct{})
+	restarted := make(chan struct{})
+	workerStarts := 0
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceReady && event.Service == "control" {
+			close(listening)
+		}
+		if event.Type == lifetime.EventServiceStarted && event.Service == "worker" {
+			if workerStarts++; workerStarts == 2 {
+				close(restarted)
+			}
+		}
+	})
+	lt.Start(&restartableService{}, lifetime.WithName("worker"))
+	lt.Start(lt.ControlService(path), lifetime.WithName("control"))
+	<-listening
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("could not stat control socket: %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("expected the control socket to have mode 0600, got %o", mode)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("could not connect to control socket: %v", err)
+	}
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	send := func(request lifetime.ControlRequest) lifetime.ControlResponse {
+		if err := json.NewEncoder(conn).Encode(request); err != nil {
+			t.Fatalf("could not send request: %v", err)
+		}
+		if !scanner.Scan() {
+			t.Fatalf("expected a response: %v", scanner.Err())
+		}
+		var response lifetime.ControlResponse
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+			t.Fatalf("could not decode response: %v", err)
+		}
+		return response
+	}
+
+	response := send(lifetime.ControlRequest{Command: lifetime.ControlStatus})
+	if !response.OK || response.Status == nil || len(response.Status.Services) != 2 {
+		t.Errorf("unexpected status response: %+v", response)
+	}
+
+	response = send(lifetime.ControlRequest{Command: lifetime.ControlSetLogLevel, Level: "debug"})
+	if !response.OK || lt.ConfigSummary().LogLevel != lifetime.LevelDebug.String() {
+		t.Errorf("expected the log level to be changed, got %+v", response)
+	}
+
+	response = send(lifetime.ControlRequest{Command: lifetime.ControlRestart, Service: "worker"})
+	if !response.OK {
+		t.Errorf("expected the worker to be restarted, got %+v", response)
+	}
+	select {
+	case <-restarted:
+	case <-time.After(time.Second):
+		t.Errorf("expected the worker to be started again")
+	}
+
+	response = send(lifetime.ControlRequest{Command: lifetime.ControlRestart, Service: "missing"})
+	if response.OK || response.Error == "" {
+		t.Errorf("expected an error when restarting a service that is not running, got %+v", response)
+	}
+
+	response = send(lifetime.ControlRequest{Command: "explode"})
+	if response.OK {
+		t.Errorf("expected an unknown command to fail")
+	}
+
+	response = send(lifetime.ControlRequest{Command: lifetime.ControlShutdown})
+	if !response.OK {
+		t.Errorf("expected the shutdown to be accepted, got %+v", response)
+	}
+	_ = lt.Wait()
+	if cause, _ := lt.Cause(); cause != lifetime.CauseManual {
+		t.Errorf("expected a manual shutdown, got %s", cause)
+	}
+}
+
+func TestLifetime_ControlService_ExistingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lifetime")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	t.Run("stale socket", func(t *testing.T) {
+		path := filepath.Join(dir, "stale.sock")
+		lis, err := net.Listen("unix", path)
+		if err != nil {
+			t.Fatalf("could not listen: %v", err)
+		}
+		lis.(*net.UnixListener).SetUnlinkOnClose(false)
+		_ = lis.Close()
+
+		lt := lifetime.New(context.Background()).HandleErrors(nil)
+		handle := lt.Start(lt.ControlService(path))
+		if err := handle.Ensure(context.Background()); err != nil {
+			t.Fatalf("expected the stale socket to be replaced, got %v", err)
+		}
+		lt.Shutdown()
+		if err := lt.Wait(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Lstat(path); !os.IsNotExist(err) {
+			t.Errorf("expected the socket to be removed once stopped, got %v", err)
+		}
+	})
+
+	t.Run("regular file", func(t *testing.T) {
+		path := filepath.Join(dir, "config.json")
+		if err := ioutil.WriteFile(path, []byte("{}"), 0600); err != nil {
+			t.Fatalf("could not write file: %v", err)
+		}
+
+		lt := lifetime.New(context.Background()).HandleErrors(nil)
+		lt.Start(lt.ControlService(path))
+		if err := lt.WaitErr(); err == nil {
+			t.Errorf("expected the service to fail")
+		}
+		if data, err := ioutil.ReadFile(path); err != nil || string(data) != "{}" {
+			t.Errorf("expected the file to be left alone, got %q: %v", data, err)
+		}
+	})
+}
+
+func TestLifetime_ControlService_Restart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lifetime")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "control.sock")
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	handle := lt.Start(lt.ControlService(path), lifetime.WithName("control"))
+	if err := handle.Ensure(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	handle.Restart()
+	if err := handle.Ensure(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("expected the restarted service to be listening, got %v", err)
+	}
+	defer conn.Close()
+	if err := json.NewEncoder(conn).Encode(lifetime.ControlRequest{Command: lifetime.ControlStatus}); err != nil {
+		t.Fatalf("could not send request: %v", err)
+	}
+	var response lifetime.ControlResponse
+	if err := json.NewDecoder(conn).Decode(&response); err != nil || !response.OK {
+		t.Errorf("expected a status response, got %+v: %v", response, err)
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the socket to be removed once stopped, got %v", err)
+	}
+}
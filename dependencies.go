@@ -0,0 +1,133 @@
+package lifetime
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDependencyFailed is returned when a service can't be started because one of its dependencies
+// exited before it was ready. See After.
+var ErrDependencyFailed = errors.New("dependency failed")
+
+// After declares that the service depends on the services controlled by the given handles.
+// The Start func of the service is not executed until every dependency is ready, and dependencies that were
+// registered with Lazy are started. If a dependency exits before it is ready the service fails with
+// ErrDependencyFailed. During a shutdown the service is stopped before its dependencies,
+// even if that means stopping it out of the order of its class.
+func After(dependencies ...*ServiceHandle) StartOption {
+	return func(managed *managedService) {
+		managed.dependencies = append(managed.dependencies, dependencies...)
+	}
+}
+
+// awaitDependencies blocks until every dependency of the service is ready and returns true if the service
+// should then be executed. If a dependency failed, the failure is handled and the service exits.
+// If the service was stopped while waiting, it is not executed and Stop is not called.
+func (managed *managedService) awaitDependencies() bool {
+	err := managed.waitForDependencies()
+
+	managed.mu.Lock()
+	stopping := managed.stopping
+	failed := err != nil && !stopping && managed.ctx.Err() == nil
+	if err == nil && !stopping {
+		managed.waiting = false
+	}
+	managed.failed = failed
+	managed.mu.Unlock()
+
+	if err == nil && !stopping {
+		return true
+	}
+	close(managed.done)
+	if failed {
+		err = managed.wrapError(err)
+		managed.emit(EventServiceFailed, err)
+		managed.handleFailure(err)
+		managed.exit()
+	}
+	return false
+}
+
+// waitForDependencies blocks until every dependency of the service is ready, starting any that are lazy.
+func (managed *managedService) waitForDependencies() error {
+	for _, dependency := range managed.dependencies {
+		if err := dependency.Ensure(managed.ctx); err != nil {
+			if managed.ctx.Err() != nil {
+				return managed.ctx.Err()
+			}
+			return fmt.Errorf("%w: %v", ErrDependencyFailed, err)
+		}
+	}
+	return nil
+}
+
+// dependencySteps moves services into later steps so that no service is stopped before, or at the same time as,
+// a running service that depends on it. Services that depend on each other in a cycle are stopped together
+// in a final step.
+func dependencySteps(steps [][]*managedService) [][]*managedService {
+	// dependents counts the running services that depend on each service and have not yet been placed in a step.
+	dependents := map[*managedService]int{}
+	running := map[*managedService]bool{}
+	for _, step := range steps {
+		for _, managed := range step {
+			running[managed] = true
+		}
+	}
+	hasDependencies := false
+	for managed := range running {
+		for _, dependency := range managed.dependencyServices() {
+			if running[dependency] {
+				dependents[dependency]++
+				hasDependencies = true
+			}
+		}
+	}
+	if !hasDependencies {
+		return steps
+	}
+
+	ordered := make([][]*managedService, 0, len(steps))
+	deferred := make([]*managedService, 0)
+	for _, step := range steps {
+		candidates := append(deferred, step...)
+		for {
+			placed := make([]*managedService, 0, len(candidates))
+			remaining := make([]*managedService, 0, len(candidates))
+			for _, managed := range candidates {
+				if dependents[managed] == 0 {
+					placed = append(placed, managed)
+				} else {
+					remaining = append(remaining, managed)
+				}
+			}
+			if len(placed) == 0 {
+				break
+			}
+			for _, managed := range placed {
+				for _, dependency := range managed.dependencyServices() {
+					if running[dependency] {
+						dependents[dependency]--
+					}
+				}
+			}
+			ordered = append(ordered, placed)
+			candidates = remaining
+		}
+		deferred = candidates
+	}
+	if len(deferred) > 0 {
+		ordered = append(ordered, deferred)
+	}
+	return ordered
+}
+
+// dependencyServices returns the current instances of the dependencies of the service that have been started.
+func (managed *managedService) dependencyServices() []*managedService {
+	services := make([]*managedService, 0, len(managed.dependencies))
+	for _, dependency := range managed.dependencies {
+		if current := dependency.current(); current != nil {
+			services = append(services, current)
+		}
+	}
+	return services
+}
@@ -0,0 +1,91 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+// gatedService is a blocking service that is ready once the ready channel is closed.
+type gatedService struct {
+	*blockingService
+	ready chan struct{}
+}
+
+func (s *gatedService) Ready() <-chan struct{} {
+	return s.ready
+}
+
+func TestAfter(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	db := &gatedService{blockingService: newBlockingService(), ready: make(chan struct{})}
+	dbHandle := lt.Start(db, lifetime.WithName("db"))
+	api := &contextService{started: make(chan struct{})}
+	lt.Start(api, lifetime.WithName("api"), lifetime.WithClass(lifetime.ClassStorage), lifetime.After(dbHandle))
+
+	select {
+	case <-api.started:
+		t.Fatalf("expected the service not to be started before its dependency is ready")
+	case <-time.After(time.Millisecond * 20):
+	}
+	close(db.ready)
+	<-api.started
+
+	steps := lt.PlanShutdown().Steps
+	if len(steps) != 2 || steps[0][0].Name != "api" || steps[1][0].Name != "db" {
+		t.Errorf("expected api to be stopped before db, got %v", steps)
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAfter_DependencyFailed(t *testing.T) {
+	lt := lifetime.New(context.Background())
+
+	failed := make(chan error, 1)
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceFailed && event.Service == "api" {
+			failed <- event.Err
+		}
+	})
+
+	db := lt.Start(&failingService{err: errors.New("connection refused")}, lifetime.WithName("db"), lifetime.NonCritical())
+	api := newBlockingService()
+	lt.Start(api, lifetime.WithName("api"), lifetime.NonCritical(), lifetime.After(db))
+
+	if err := <-failed; !errors.Is(err, lifetime.ErrDependencyFailed) {
+		t.Errorf("expected ErrDependencyFailed, got %v", err)
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.stopped {
+		t.Errorf("expected Stop not to be called on a service that was never executed")
+	}
+}
+
+func TestAfter_StoppedWhileWaiting(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	db := lt.Start(&gatedService{blockingService: newBlockingService(), ready: make(chan struct{})}, lifetime.WithName("db"))
+	api := newBlockingService()
+	handle := lt.Start(api, lifetime.WithName("api"), lifetime.After(db))
+
+	handle.Stop()
+	if api.stopped {
+		t.Errorf("expected Stop not to be called on a service that was never executed")
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package lifetime
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// WithDeterministic enables deterministic mode, which is intended for reproducing and debugging
+// ordering problems in tests. Given the same seed:
+//   - Start does not return until the Start func of the service is about to be executed, so services
+//     are started in the order Start is called.
+//   - Services are stopped one at a time during a shutdown, in an order chosen by the seed.
+//   - Funcs registered against a shutdown stage are executed one at a time, in an order chosen by the seed.
+//   - Errors that are waiting to be handled together are passed to the error handler sorted by their message.
+//
+// Deterministic mode slows down startup and shutdown and should not be used in production.
+func WithDeterministic(seed int64) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.deterministic = &deterministic{
+			rand: rand.New(rand.NewSource(seed)),
+		}
+	}
+}
+
+// deterministic chooses the order of operations in deterministic mode.
+type deterministic struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// perm returns a permutation of n items chosen by the seed.
+func (deterministic *deterministic) perm(n int) []int {
+	deterministic.mu.Lock()
+	defer deterministic.mu.Unlock()
+	return deterministic.rand.Perm(n)
+}
+
+// shuffleServices returns the given services in an order chosen by the seed.
+// The services are first sorted by the order they were started in, so the result
+// does not depend on the order they were given in.
+func (deterministic *deterministic) shuffleServices(services []*managedService) []*managedService {
+	sortBySeq(services)
+	shuffled := make([]*managedService, len(services))
+	for i, j := range deterministic.perm(len(services)) {
+		shuffled[i] = services[j]
+	}
+	return shuffled
+}
+
+// sortBySeq sorts the given services by the order they were started in.
+func sortBySeq(services []*managedService) {
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].seq < services[j].seq
+	})
+}
+
+// shuffleStageFuncs returns the given stage funcs in an order chosen by the seed.
+func (deterministic *deterministic) shuffleStageFuncs(funcs []stageFunc) []stageFunc {
+	shuffled := make([]stageFunc, len(funcs))
+	for i, j := range deterministic.perm(len(funcs)) {
+		shuffled[i] = funcs[j]
+	}
+	return shuffled
+}
+
+// sortErrors sorts the given errors by their message.
+func (deterministic *deterministic) sortErrors(errs []error) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+}
@@ -0,0 +1,86 @@
+package lifetime_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/tomwright/lifetime"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// stopOrder runs a lifetime in deterministic mode with the given seed and returns the order in
+// which its services and stage funcs were stopped.
+func stopOrder(seed int64) []string {
+	lt := lifetime.New(context.Background(), lifetime.WithDeterministic(seed)).HandleErrors(nil)
+
+	var mu sync.Mutex
+	order := make([]string, 0)
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceStopping {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, event.Service)
+		}
+	})
+
+	for i := 0; i < 10; i++ {
+		lt.Start(newBlockingService(), lifetime.WithName(fmt.Sprintf("service-%d", i)))
+		name := fmt.Sprintf("drain-%d", i)
+		lt.RegisterDrain(name, func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	return order
+}
+
+func TestWithDeterministic(t *testing.T) {
+	first := stopOrder(42)
+	if len(first) != 20 {
+		t.Fatalf("expected 20 entries, got %d", len(first))
+	}
+	for i := 0; i < 5; i++ {
+		if got := stopOrder(42); !reflect.DeepEqual(first, got) {
+			t.Fatalf("expected the same order for the same seed:\n%v\n%v", first, got)
+		}
+	}
+}
+
+func TestWithDeterministic_StartOrder(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithDeterministic(1)).HandleErrors(nil)
+
+	var mu sync.Mutex
+	order := make([]string, 0)
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceStarted {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, event.Service)
+		}
+	})
+
+	exp := make([]string, 0)
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("service-%d", i)
+		exp = append(exp, name)
+		lt.Start(newBlockingService(), lifetime.WithName(name))
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(exp, order) {
+		t.Errorf("expected services to start in order:\n%v\n%v", exp, order)
+	}
+}
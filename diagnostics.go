@@ -0,0 +1,76 @@
+package lifetime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"runtime/pprof"
+	"time"
+)
+
+// WithDiagnosticsOnImmediateShutdown writes a DiagnosticBundle to the given file when an immediate shutdown
+// is triggered, before the application exits, so that an application that is force killed during an
+// incident still leaves evidence behind.
+func WithDiagnosticsOnImmediateShutdown(path string) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.diagnosticsFile = path
+	}
+}
+
+// DiagnosticBundle describes the state of the application when an immediate shutdown was triggered.
+type DiagnosticBundle struct {
+	// Time is the time the bundle was written.
+	Time time.Time `json:"time"`
+	// ShutdownID is the correlation ID of the shutdown, if one had begun. See ShutdownID.
+	ShutdownID string `json:"shutdown_id,omitempty"`
+	// Reason is the error that triggered the immediate shutdown.
+	Reason string `json:"reason"`
+	// Status is the status of the lifetime.
+	Status Status `json:"status"`
+	// PendingStops are the names of the services that have been told to stop but have not yet stopped.
+	PendingStops []string `json:"pending_stops"`
+	// Goroutines is a dump of the stack of every goroutine.
+	Goroutines string `json:"goroutines"`
+}
+
+// writeDiagnostics writes a diagnostic bundle to the diagnostics file, if there is one.
+func (lifetime *Lifetime) writeDiagnostics(reason error) {
+	if lifetime.diagnosticsFile == "" {
+		return
+	}
+	bundle := DiagnosticBundle{
+		Time:         time.Now(),
+		ShutdownID:   lifetime.ShutdownID(),
+		Reason:       reason.Error(),
+		Status:       lifetime.Status(),
+		PendingStops: make([]string, 0),
+	}
+	for _, service := range bundle.Status.Services {
+		if service.Stopping {
+			bundle.PendingStops = append(bundle.PendingStops, service.Name)
+		}
+	}
+	goroutines := &bytes.Buffer{}
+	if err := pprof.Lookup("goroutine").WriteTo(goroutines, 2); err == nil {
+		bundle.Goroutines = goroutines.String()
+	}
+
+	if err := writeDiagnosticBundle(lifetime.diagnosticsFile, bundle); err != nil {
+		lifetime.log(LevelWarn, "lifetime could not write diagnostics", Field{Key: "error", Value: err})
+		return
+	}
+	lifetime.log(LevelInfo, "lifetime wrote diagnostics", Field{Key: "file", Value: lifetime.diagnosticsFile})
+}
+
+// writeDiagnosticBundle writes the given bundle to the given file as JSON.
+func writeDiagnosticBundle(path string, bundle DiagnosticBundle) error {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode diagnostics: %w", err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("could not write diagnostics: %w", err)
+	}
+	return nil
+}
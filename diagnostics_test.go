@@ -0,0 +1,53 @@
+package lifetime_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/tomwright/lifetime"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithDiagnosticsOnImmediateShutdown(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lifetime")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "diagnostics.json")
+
+	lt := lifetime.New(context.Background(),
+		lifetime.WithImmediateShutdownReturn(),
+		lifetime.WithDiagnosticsOnImmediateShutdown(path),
+	).HandleErrors(nil)
+	lt.OnEvent(func(event lifetime.Event) {
+		// Force the shutdown once the stuck service has been told to stop.
+		if event.Type == lifetime.EventServiceStopping {
+			lt.ReportError(lifetime.ErrImmediateShutdownSignalReceived)
+		}
+	})
+	lt.Start(&stuckService{}, lifetime.WithName("stuck"))
+	lt.Shutdown()
+
+	if err := lt.Wait(); err != lifetime.ErrImmediateShutdownSignalReceived {
+		t.Fatalf("expected immediate shutdown, got %v", err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected diagnostics to be written: %v", err)
+	}
+	bundle := lifetime.DiagnosticBundle{}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		t.Fatalf("could not parse diagnostics: %v", err)
+	}
+	if len(bundle.PendingStops) != 1 || bundle.PendingStops[0] != "stuck" {
+		t.Errorf("expected the stuck service to be pending, got %v", bundle.PendingStops)
+	}
+	if bundle.ShutdownID != lt.ShutdownID() || !strings.Contains(bundle.Goroutines, "goroutine") {
+		t.Errorf("unexpected diagnostics: %+v", bundle)
+	}
+}
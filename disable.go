@@ -0,0 +1,46 @@
+package lifetime
+
+import (
+	"context"
+	"strings"
+)
+
+// WithDisabledServices disables the services with the given names, so the same binary can be run
+// in a reduced mode or while debugging.
+// Starting a disabled service is a no-op: the service is never executed and its handle reports
+// that it has already finished.
+func WithDisabledServices(names ...string) Option {
+	return func(lifetime *Lifetime) {
+		if lifetime.disabled == nil {
+			lifetime.disabled = map[string]bool{}
+		}
+		for _, name := range names {
+			if name = strings.TrimSpace(name); name != "" {
+				lifetime.disabled[name] = true
+			}
+		}
+	}
+}
+
+// IsDisabled returns true if the service with the given name has been disabled.
+// See WithDisabledServices.
+func (lifetime *Lifetime) IsDisabled(name string) bool {
+	return lifetime.disabled[name]
+}
+
+// skip marks the service as having finished without executing it, because it has been disabled.
+func (managed *managedService) skip() {
+	managed.disabled = true
+	managed.ctx, managed.cancelFunc = context.WithCancel(managed.lifetime.ctx)
+	managed.cancelFunc()
+	close(managed.ready)
+	close(managed.done)
+	close(managed.exited)
+	if managed.started != nil {
+		close(managed.started)
+	}
+	if !managed.replacing {
+		managed.handle.setManaged(managed)
+	}
+	managed.emit(EventServiceDisabled, nil)
+}
@@ -0,0 +1,64 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"os"
+	"testing"
+)
+
+func TestWithDisabledServices(t *testing.T) {
+	_ = os.Setenv(lifetime.EnvDisable, "metrics, cron")
+	defer func() {
+		_ = os.Unsetenv(lifetime.EnvDisable)
+	}()
+
+	lt := lifetime.New(context.Background(), lifetime.WithEnv()).HandleErrors(nil)
+	if !lt.IsDisabled("cron") || lt.IsDisabled("api") {
+		t.Errorf("expected only the services in the environment to be disabled")
+	}
+
+	var disabled []string
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceDisabled {
+			disabled = append(disabled, event.Service)
+		}
+	})
+
+	metrics := &countedStartService{}
+	handle := lt.Start(metrics, lifetime.WithName("metrics"))
+	lt.Start(newBlockingService(), lifetime.WithName("api"))
+
+	select {
+	case <-handle.Done():
+	default:
+		t.Errorf("expected the handle of a disabled service to be done")
+	}
+	handle.Stop()
+
+	if status := lt.Status(); len(status.Services) != 1 || status.Services[0].Name != "api" {
+		t.Errorf("expected only the enabled service to be running, got %+v", status.Services)
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	if metrics.starts != 0 {
+		t.Errorf("expected the disabled service not to be started")
+	}
+	if len(disabled) != 1 || disabled[0] != "metrics" {
+		t.Errorf("expected a disabled event for metrics, got %v", disabled)
+	}
+}
+
+// countedStartService counts the number of times it is started.
+type countedStartService struct {
+	starts int
+}
+
+func (s *countedStartService) Start() error {
+	s.starts++
+	return nil
+}
+
+func (s *countedStartService) Stop() {}
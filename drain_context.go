@@ -0,0 +1,33 @@
+package lifetime
+
+import (
+	"context"
+	"time"
+)
+
+// ShutdownDeadline returns the time by which the shutdown must complete.
+// It returns false if no shutdown has started yet, or if there is no shutdown timeout. See WithShutdownTimeout.
+func (lifetime *Lifetime) ShutdownDeadline() (time.Time, bool) {
+	lifetime.shutdownDeadlineMu.Lock()
+	defer lifetime.shutdownDeadlineMu.Unlock()
+	return lifetime.shutdownDeadline, !lifetime.shutdownDeadline.IsZero()
+}
+
+// DrainContext returns a context derived from the given parent for an outgoing request made while the application
+// is shutting down, such as a final flush to a remote service. Once a shutdown has started the context has the
+// shutdown deadline, so that the request can't use more time than the shutdown has left.
+// Before a shutdown has started, or if there is no shutdown timeout, the context has no deadline of its own.
+func (lifetime *Lifetime) DrainContext(parent context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := lifetime.ShutdownDeadline()
+	if !ok {
+		return context.WithCancel(parent)
+	}
+	return context.WithDeadline(parent, deadline)
+}
+
+// setShutdownDeadline records the time by which the shutdown must complete.
+func (lifetime *Lifetime) setShutdownDeadline(deadline time.Time) {
+	lifetime.shutdownDeadlineMu.Lock()
+	defer lifetime.shutdownDeadlineMu.Unlock()
+	lifetime.shutdownDeadline = deadline
+}
@@ -0,0 +1,40 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+func TestLifetime_DrainContext(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithShutdownTimeout(time.Second*5)).HandleErrors(nil)
+
+	ctx, cancel := lt.DrainContext(context.Background())
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("expected no deadline before the shutdown started")
+	}
+	cancel()
+
+	var remaining time.Duration
+	var hasDeadline bool
+	lt.OnStage(lifetime.StageDrain, "flush", func(stageCtx context.Context) error {
+		ctx, cancel := lt.DrainContext(context.Background())
+		defer cancel()
+		var deadline time.Time
+		deadline, hasDeadline = ctx.Deadline()
+		remaining = time.Until(deadline)
+		return nil
+	})
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasDeadline || remaining <= 0 || remaining > time.Second*5 {
+		t.Errorf("expected the context to have the shutdown deadline, got %v remaining", remaining)
+	}
+	if deadline, ok := lt.ShutdownDeadline(); !ok || deadline.IsZero() {
+		t.Errorf("expected the shutdown deadline to be recorded")
+	}
+}
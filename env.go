@@ -0,0 +1,99 @@
+package lifetime
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The environment variables read by WithEnv.
+const (
+	// EnvPreset sets the preset, as with WithPreset. It is applied before the other variables.
+	EnvPreset = "LIFETIME_PRESET"
+	// EnvShutdownTimeout sets the shutdown timeout as a duration such as 30s, as with WithShutdownTimeout.
+	EnvShutdownTimeout = "LIFETIME_SHUTDOWN_TIMEOUT"
+	// EnvStartupTimeout sets the startup timeout as a duration such as 1m, as with WithStartupTimeout.
+	EnvStartupTimeout = "LIFETIME_STARTUP_TIMEOUT"
+	// EnvDrainDelay sets the drain delay as a duration such as 5s, as with WithDrainDelay.
+	EnvDrainDelay = "LIFETIME_DRAIN_DELAY"
+	// EnvSignalEscalation sets the number of shutdown signals that cause an immediate shutdown,
+	// as with WithSignalEscalation.
+	EnvSignalEscalation = "LIFETIME_SIGNAL_ESCALATION"
+	// EnvStopWarning sets the stop warning threshold as a duration such as 10s, as with WithStopWarning.
+	EnvStopWarning = "LIFETIME_STOP_WARNING"
+	// EnvLogLevel sets the log level to one of debug, info, warn or error, as with WithLogLevel.
+	EnvLogLevel = "LIFETIME_LOG_LEVEL"
+	// EnvDisable disables a comma separated list of services by name, as with WithDisabledServices.
+	EnvDisable = "LIFETIME_DISABLE"
+)
+
+// WithEnv configures the lifetime using the LIFETIME_* environment variables that are set, so that
+// operators can tune the behaviour of each deployment without code changes.
+// Options given after WithEnv override the environment, so it should usually be given last.
+// Invalid values are logged and ignored.
+func WithEnv() Option {
+	return func(lifetime *Lifetime) {
+		lifetime.applyEnv(os.LookupEnv)
+	}
+}
+
+// applyEnv configures the lifetime using the environment variables returned by the given lookup func.
+func (lifetime *Lifetime) applyEnv(lookup func(key string) (string, bool)) {
+	if value, ok := lookup(EnvPreset); ok {
+		WithPreset(Preset(value))(lifetime)
+	}
+
+	durations := []struct {
+		key string
+		opt func(d time.Duration) Option
+	}{
+		{key: EnvShutdownTimeout, opt: WithShutdownTimeout},
+		{key: EnvStartupTimeout, opt: WithStartupTimeout},
+		{key: EnvDrainDelay, opt: WithDrainDelay},
+		{key: EnvStopWarning, opt: WithStopWarning},
+	}
+	for _, d := range durations {
+		value, ok := lookup(d.key)
+		if !ok {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			lifetime.logInvalidEnv(d.key, value, err)
+			continue
+		}
+		d.opt(duration)(lifetime)
+	}
+
+	if value, ok := lookup(EnvSignalEscalation); ok {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			lifetime.logInvalidEnv(EnvSignalEscalation, value, err)
+		} else {
+			WithSignalEscalation(count)(lifetime)
+		}
+	}
+
+	if value, ok := lookup(EnvLogLevel); ok {
+		level, err := ParseLevel(value)
+		if err != nil {
+			lifetime.logInvalidEnv(EnvLogLevel, value, err)
+		} else {
+			WithLogLevel(level)(lifetime)
+		}
+	}
+
+	if value, ok := lookup(EnvDisable); ok {
+		WithDisabledServices(strings.Split(value, ",")...)(lifetime)
+	}
+}
+
+// logInvalidEnv logs that the given environment variable has an invalid value.
+func (lifetime *Lifetime) logInvalidEnv(key string, value string, err error) {
+	lifetime.log(LevelWarn, "lifetime invalid environment variable",
+		Field{Key: "key", Value: key},
+		Field{Key: "value", Value: value},
+		Field{Key: "error", Value: err.Error()},
+	)
+}
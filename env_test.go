@@ -0,0 +1,45 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWithEnv(t *testing.T) {
+	_ = os.Setenv(lifetime.EnvShutdownTimeout, "50ms")
+	_ = os.Setenv(lifetime.EnvDrainDelay, "not-a-duration")
+	defer func() {
+		_ = os.Unsetenv(lifetime.EnvShutdownTimeout)
+		_ = os.Unsetenv(lifetime.EnvDrainDelay)
+	}()
+
+	lt := lifetime.New(context.Background(), lifetime.WithEnv()).HandleErrors(nil)
+	lt.Start(&stuckService{})
+
+	start := time.Now()
+	lt.Shutdown()
+	if err := lt.Wait(); err != lifetime.ErrShutdownTimeout {
+		t.Errorf("expected ErrShutdownTimeout, got %v", err)
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("expected the shutdown timeout to be read from the environment, took %s", took)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, level := range []lifetime.Level{lifetime.LevelDebug, lifetime.LevelInfo, lifetime.LevelWarn, lifetime.LevelError} {
+		got, err := lifetime.ParseLevel(level.String())
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if got != level {
+			t.Errorf("expected %s, got %s", level, got)
+		}
+	}
+	if _, err := lifetime.ParseLevel("loud"); err == nil {
+		t.Errorf("expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,154 @@
+package lifetime
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrorAction is what the lifetime does when a service fails.
+type ErrorAction int
+
+const (
+	// ActionShutdown reports the error to the error handler, which triggers a shutdown by default.
+	ActionShutdown ErrorAction = iota
+	// ActionRetry starts the service again after the retry delay. See WithErrorRetryDelay.
+	ActionRetry
+	// ActionIgnore logs the error and lets the service exit, without affecting the rest of the application.
+	ActionIgnore
+	// ActionDegrade lets the service exit without affecting the rest of the application, and marks
+	// the application as degraded. See Status.
+	ActionDegrade
+)
+
+// String returns the name of the action.
+func (action ErrorAction) String() string {
+	switch action {
+	case ActionRetry:
+		return "retry"
+	case ActionIgnore:
+		return "ignore"
+	case ActionDegrade:
+		return "degrade"
+	default:
+		return "shutdown"
+	}
+}
+
+// defaultErrorRetryDelay is how long the lifetime waits before starting a service that failed with
+// an error classified as ActionRetry.
+const defaultErrorRetryDelay = time.Second
+
+// WithErrorRetryDelay sets how long the lifetime waits before starting a service that failed with an
+// error classified as ActionRetry.
+// Defaults to 1 second.
+func WithErrorRetryDelay(delay time.Duration) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.errorRetryDelay = delay
+	}
+}
+
+// ErrorClassifier decides what action should be taken when a service fails with the given error.
+// It returns false if it does not apply to the error.
+type ErrorClassifier func(err error) (ErrorAction, bool)
+
+// ErrorIs returns an ErrorClassifier that applies the given action to errors that match target using errors.Is.
+func ErrorIs(target error, action ErrorAction) ErrorClassifier {
+	return func(err error) (ErrorAction, bool) {
+		return action, errors.Is(err, target)
+	}
+}
+
+// ErrorMatches returns an ErrorClassifier that applies the given action to errors that the given predicate
+// returns true for. The predicate can use errors.As to match errors by type.
+func ErrorMatches(predicate func(err error) bool, action ErrorAction) ErrorClassifier {
+	return func(err error) (ErrorAction, bool) {
+		return action, predicate(err)
+	}
+}
+
+// AddErrorPolicy registers a classifier that decides what happens when a service fails.
+// Classifiers are checked in the order they were added and the first that applies is used.
+// Errors that no classifier applies to trigger a shutdown, unless the service is NonCritical in
+// which case they are ignored.
+func (lifetime *Lifetime) AddErrorPolicy(classifier ErrorClassifier) {
+	lifetime.errorPoliciesMu.Lock()
+	defer lifetime.errorPoliciesMu.Unlock()
+	lifetime.errorPolicies = append(lifetime.errorPolicies, classifier)
+}
+
+// classifyError returns the action of the first error policy that applies to the given error.
+func (lifetime *Lifetime) classifyError(err error) (ErrorAction, bool) {
+	lifetime.errorPoliciesMu.Lock()
+	policies := lifetime.errorPolicies
+	lifetime.errorPoliciesMu.Unlock()
+
+	for _, classifier := range policies {
+		if action, ok := classifier(err); ok {
+			return action, true
+		}
+	}
+	return ActionShutdown, false
+}
+
+// handleFailure takes the action decided by the error policies for a service that failed with the given error.
+// It must be called before the service exits.
+func (managed *managedService) handleFailure(err error) {
+	managed.mu.Lock()
+	replacing := managed.replacing
+	if replacing {
+		managed.swapErr = err
+	}
+	managed.mu.Unlock()
+	if replacing {
+		// The error is returned by Swap and the instance that was being replaced is left running.
+		return
+	}
+
+	if managed.lifetime.strictFailure() {
+		managed.lifetime.reportError(err)
+		return
+	}
+	action, ok := managed.panicAction(err)
+	if !ok {
+		action, ok = managed.lifetime.classifyError(err)
+	}
+	if !ok && managed.nonCritical {
+		if !managed.lifetime.partialStartup {
+			managed.log(LevelWarn, "lifetime non-critical service failed", err)
+			return
+		}
+		action = ActionDegrade
+	}
+
+	switch action {
+	case ActionRetry:
+		managed.log(LevelWarn, "lifetime service failed, retrying", err,
+			Field{Key: "delay", Value: managed.lifetime.errorRetryDelay},
+		)
+		managed.lifetime.emit(Event{Type: EventServiceRetrying, Service: managed.name, Labels: managed.labels, Metadata: managed.metadata, Err: err})
+		// Track the retry so that Wait doesn't return between the service exiting and starting again.
+		done := managed.lifetime.TrackTask()
+		go func() {
+			defer done()
+			if sleepContext(managed.lifetime.ctx, managed.lifetime.errorRetryDelay) {
+				managed.handle.setHandoffState(snapshot(managed.svc))
+				managed.lifetime.start(managed.handle)
+			}
+		}()
+	case ActionIgnore:
+		managed.log(LevelWarn, "lifetime service failed, ignoring", err)
+	case ActionDegrade:
+		managed.log(LevelWarn, "lifetime service failed, degraded", err)
+		managed.lifetime.degrade(managed.name, err)
+		managed.lifetime.emit(Event{Type: EventServiceDegraded, Service: managed.name, Labels: managed.labels, Metadata: managed.metadata, Err: err})
+	default:
+		managed.lifetime.reportError(err)
+	}
+}
+
+// degrade marks the application as degraded because the named service failed with the given error.
+func (lifetime *Lifetime) degrade(name string, err error) {
+	lifetime.degradedMu.Lock()
+	lifetime.degraded[name] = err
+	lifetime.degradedMu.Unlock()
+}
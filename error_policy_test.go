@@ -0,0 +1,106 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyService fails the given number of times before running until it is stopped.
+type flakyService struct {
+	err      error
+	failures int
+
+	mu     sync.Mutex
+	starts int
+	stop   chan struct{}
+}
+
+func (s *flakyService) Start() error {
+	s.mu.Lock()
+	s.starts++
+	fail := s.starts <= s.failures
+	s.mu.Unlock()
+	if fail {
+		return s.err
+	}
+	<-s.stop
+	return nil
+}
+
+func (s *flakyService) Stop() {
+	close(s.stop)
+}
+
+func TestLifetime_AddErrorPolicy(t *testing.T) {
+	transient := errors.New("transient")
+	permanent := errors.New("permanent")
+
+	t.Run("retry", func(t *testing.T) {
+		lt := lifetime.New(context.Background(), lifetime.WithErrorRetryDelay(time.Millisecond)).HandleErrors(nil)
+		lt.AddErrorPolicy(lifetime.ErrorIs(transient, lifetime.ActionRetry))
+
+		svc := &flakyService{err: transient, failures: 2, stop: make(chan struct{})}
+		started := make(chan struct{})
+		lt.OnEvent(func(event lifetime.Event) {
+			svc.mu.Lock()
+			defer svc.mu.Unlock()
+			if event.Type == lifetime.EventServiceStarted && svc.starts == 2 {
+				close(started)
+			}
+		})
+		lt.Start(svc)
+		<-started
+
+		if lt.ShuttingDown() {
+			t.Errorf("expected a retryable error not to trigger a shutdown")
+		}
+		lt.Shutdown()
+		_ = lt.Wait()
+		svc.mu.Lock()
+		defer svc.mu.Unlock()
+		if svc.starts != 3 {
+			t.Errorf("expected the service to be started 3 times, got %d", svc.starts)
+		}
+	})
+
+	t.Run("degrade", func(t *testing.T) {
+		lt := lifetime.New(context.Background()).HandleErrors(nil)
+		lt.AddErrorPolicy(lifetime.ErrorMatches(func(err error) bool {
+			return errors.Is(err, permanent)
+		}, lifetime.ActionDegrade))
+
+		degraded := make(chan struct{})
+		lt.OnEvent(func(event lifetime.Event) {
+			if event.Type == lifetime.EventServiceDegraded {
+				close(degraded)
+			}
+		})
+		lt.Start(newBlockingService())
+		lt.Start(&failingService{err: permanent}, lifetime.WithName("search"))
+		<-degraded
+
+		status := lt.Status()
+		if status.ShuttingDown || status.Degraded["search"] == "" {
+			t.Errorf("expected the application to be degraded rather than shutting down, got %+v", status)
+		}
+		lt.Shutdown()
+		_ = lt.Wait()
+	})
+
+	t.Run("unmatched", func(t *testing.T) {
+		lt := lifetime.New(context.Background()).HandleErrors(nil)
+		lt.AddErrorPolicy(lifetime.ErrorIs(transient, lifetime.ActionIgnore))
+
+		lt.Start(newBlockingService())
+		lt.Start(&failingService{err: permanent})
+		_ = lt.Wait()
+
+		if cause, _ := lt.Cause(); cause != lifetime.CauseServiceError {
+			t.Errorf("expected an unmatched error to trigger a shutdown, got %s", cause)
+		}
+	})
+}
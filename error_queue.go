@@ -0,0 +1,98 @@
+package lifetime
+
+import (
+	"sync"
+)
+
+// defaultErrorQueueSize is the number of errors that can be queued before errors start being dropped.
+const defaultErrorQueueSize = 64
+
+// newErrorQueue returns an error queue that holds up to size errors.
+func newErrorQueue(size int) *errorQueue {
+	if size < 1 {
+		size = 1
+	}
+	queue := &errorQueue{
+		size:   size,
+		notify: make(chan struct{}, 1),
+	}
+	queue.idle = sync.NewCond(&queue.mu)
+	return queue
+}
+
+// errorQueue is a bounded queue of errors that never blocks the caller.
+// When the queue is full new errors are dropped and counted, which guarantees that the
+// errors that were reported first (and therefore explain why a shutdown happened) are kept.
+type errorQueue struct {
+	mu      sync.Mutex
+	size    int
+	errs    []error
+	dropped uint64
+	// pending is the number of queued errors that have not yet been handled.
+	pending int
+	// idle is signalled whenever pending reaches 0.
+	idle *sync.Cond
+	// notify receives a value whenever errors are added to the queue.
+	notify chan struct{}
+}
+
+// push adds the given error to the queue, or drops it if the queue is full.
+func (queue *errorQueue) push(err error) {
+	queue.add(err, false)
+}
+
+// pushAlways adds the given error to the queue even if the queue is full.
+// It is used for errors that must never be dropped, such as an immediate shutdown.
+func (queue *errorQueue) pushAlways(err error) {
+	queue.add(err, true)
+}
+
+// add adds the given error to the queue and notifies any listener.
+func (queue *errorQueue) add(err error, force bool) {
+	queue.mu.Lock()
+	if !force && len(queue.errs) >= queue.size {
+		queue.dropped++
+		queue.mu.Unlock()
+		return
+	}
+	queue.errs = append(queue.errs, err)
+	queue.pending++
+	queue.mu.Unlock()
+
+	select {
+	case queue.notify <- struct{}{}:
+	default:
+		// A notification is already pending.
+	}
+}
+
+// popAll removes and returns all of the errors in the queue, along with the number of errors
+// that have been dropped since the last call.
+func (queue *errorQueue) popAll() ([]error, uint64) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	errs := queue.errs
+	dropped := queue.dropped
+	queue.errs = nil
+	queue.dropped = 0
+	return errs, dropped
+}
+
+// handled marks the given number of popped errors as handled.
+func (queue *errorQueue) handled(count int) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.pending -= count
+	if queue.pending == 0 {
+		queue.idle.Broadcast()
+	}
+}
+
+// waitIdle blocks until every queued error has been handled.
+func (queue *errorQueue) waitIdle() {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	for queue.pending > 0 {
+		queue.idle.Wait()
+	}
+}
@@ -0,0 +1,69 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+func TestWithErrorQueueSize(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithErrorQueueSize(2))
+
+	handling := make(chan struct{})
+	release := make(chan struct{})
+	received := make(chan error, 10)
+	lt.HandleErrors(func(err error) {
+		received <- err
+		if len(received) == 1 {
+			close(handling)
+			<-release
+		}
+	})
+
+	firstErr := errors.New("first")
+	failingJob := func(err error) lifetime.Job {
+		return func(ctx context.Context) error {
+			return err
+		}
+	}
+
+	lt.Go(failingJob(firstErr))
+	<-handling
+
+	// The handler is blocked, so only 2 of these can be queued.
+	for i := 0; i < 4; i++ {
+		lt.Go(failingJob(errors.New("subsequent")))
+	}
+	lt.Wait()
+	close(release)
+
+	deadline := time.After(time.Second)
+	for lt.DroppedErrors() != 2 {
+		select {
+		case <-deadline:
+			t.Fatalf("expected 2 dropped errors, got %d", lt.DroppedErrors())
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	if got := <-received; got != firstErr {
+		t.Errorf("expected first error to be handled first, got %v", got)
+	}
+}
+
+func TestWait_ReportError(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	reportedErr := errors.New("reported")
+	lt.ReportError(reportedErr)
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The default handler has handled the error by the time Wait returns, so the cause is known.
+	if cause, err := lt.Cause(); cause != lifetime.CauseServiceError || err != reportedErr {
+		t.Errorf("expected the reported error to be the cause, got %s: %v", cause, err)
+	}
+}
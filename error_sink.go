@@ -0,0 +1,177 @@
+package lifetime
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrorReport describes a lifecycle error that is sent to an ErrorSink.
+type ErrorReport struct {
+	// Err is the error.
+	Err error
+	// Type is the type of the event the error was emitted with, such as EventServiceFailed.
+	Type EventType
+	// Time is the time at which the error happened.
+	Time time.Time
+	// Service is the name of the service the error relates to, if any.
+	Service string
+	// Labels are the labels of the service the error relates to, if any.
+	Labels Labels
+	// Metadata is the metadata of the service the error relates to, if it has any.
+	Metadata *ServiceMetadata
+	// Gate is the name of the readiness gate the error relates to, if any.
+	Gate string
+	// Stage is the shutdown stage the error relates to, if any.
+	Stage Stage
+	// ShutdownID is the correlation ID of the shutdown, if the error happened during one. See ShutdownID.
+	ShutdownID string
+	// Suppressed is the number of errors that were not reported since the previous report because of
+	// sampling or rate limiting.
+	Suppressed uint64
+}
+
+// ErrorSink receives lifecycle errors, such as to send them to an APM or error tracking service
+// like Datadog, New Relic or Sentry.
+type ErrorSink interface {
+	// ReportError reports the given error.
+	// It is executed in its own go routine, and Wait waits for it to return.
+	ReportError(report ErrorReport)
+}
+
+// ErrorSinkFunc is a func that implements ErrorSink.
+type ErrorSinkFunc func(report ErrorReport)
+
+// ReportError reports the given error.
+func (fn ErrorSinkFunc) ReportError(report ErrorReport) {
+	fn(report)
+}
+
+// ErrorSinkOption is used to configure an ErrorSink added with AddErrorSink.
+type ErrorSinkOption func(sink *errorSink)
+
+// WithSinkSampleRate sets the fraction of errors that are reported, between 0 and 1.
+// Defaults to 1, which reports every error.
+func WithSinkSampleRate(rate float64) ErrorSinkOption {
+	return func(sink *errorSink) {
+		sink.sampleRate = rate
+	}
+}
+
+// WithSinkRateLimit limits the number of errors that are reported to the given limit per interval,
+// so that a crash loop does not exhaust the quota of the error tracking service.
+func WithSinkRateLimit(limit int, interval time.Duration) ErrorSinkOption {
+	return func(sink *errorSink) {
+		sink.rateLimit = limit
+		sink.rateInterval = interval
+	}
+}
+
+// WithSinkEventTypes limits the errors that are reported to those emitted with the given event types.
+// Defaults to every event type that carries an error other than EventShutdownStarted, whose error is
+// already reported with the event that caused the shutdown.
+func WithSinkEventTypes(types ...EventType) ErrorSinkOption {
+	return func(sink *errorSink) {
+		sink.types = map[EventType]bool{}
+		for _, eventType := range types {
+			sink.types[eventType] = true
+		}
+	}
+}
+
+// AddErrorSink sends every lifecycle error to the given sink, subject to the given sampling options.
+// Lifecycle errors are those emitted with events, such as a service failing or a shutdown stage returning an error.
+func (lifetime *Lifetime) AddErrorSink(sink ErrorSink, opts ...ErrorSinkOption) {
+	errorSink := &errorSink{
+		lifetime:   lifetime,
+		sink:       sink,
+		sampleRate: 1,
+		random:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, opt := range opts {
+		opt(errorSink)
+	}
+	lifetime.OnEvent(errorSink.handle)
+}
+
+// errorSink sends the errors of events to an ErrorSink.
+type errorSink struct {
+	lifetime     *Lifetime
+	sink         ErrorSink
+	sampleRate   float64
+	rateLimit    int
+	rateInterval time.Duration
+	// types are the event types that are reported. Nil means the default types.
+	types map[EventType]bool
+
+	mu     sync.Mutex
+	random *rand.Rand
+	// windowStart is the start of the current rate limit interval.
+	windowStart time.Time
+	// windowCount is the number of errors reported in the current rate limit interval.
+	windowCount int
+	suppressed  uint64
+}
+
+// handle reports the error of the given event if it passes sampling.
+func (sink *errorSink) handle(event Event) {
+	if event.Err == nil || !sink.reports(event.Type) {
+		return
+	}
+
+	sink.mu.Lock()
+	if !sink.allow(event.Time) {
+		sink.suppressed++
+		sink.mu.Unlock()
+		return
+	}
+	suppressed := sink.suppressed
+	sink.suppressed = 0
+	sink.mu.Unlock()
+
+	report := ErrorReport{
+		Err:        event.Err,
+		Type:       event.Type,
+		Time:       event.Time,
+		Service:    event.Service,
+		Labels:     event.Labels,
+		Metadata:   event.Metadata,
+		Gate:       event.Gate,
+		Stage:      event.Stage,
+		ShutdownID: event.ShutdownID,
+		Suppressed: suppressed,
+	}
+	done := sink.lifetime.TrackTask()
+	go func() {
+		defer done()
+		sink.sink.ReportError(report)
+	}()
+}
+
+// reports returns true if errors of the given event type are reported.
+func (sink *errorSink) reports(eventType EventType) bool {
+	if sink.types == nil {
+		return eventType != EventShutdownStarted
+	}
+	return sink.types[eventType]
+}
+
+// allow returns true if an error that happened at the given time passes sampling and rate limiting.
+// It must be called with the mutex held.
+func (sink *errorSink) allow(now time.Time) bool {
+	if sink.sampleRate < 1 && sink.random.Float64() >= sink.sampleRate {
+		return false
+	}
+	if sink.rateLimit <= 0 {
+		return true
+	}
+	if now.Sub(sink.windowStart) >= sink.rateInterval {
+		sink.windowStart = now
+		sink.windowCount = 0
+	}
+	if sink.windowCount >= sink.rateLimit {
+		return false
+	}
+	sink.windowCount++
+	return true
+}
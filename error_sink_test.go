@@ -0,0 +1,78 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink records every error report it receives.
+type recordingSink struct {
+	mu      sync.Mutex
+	reports []lifetime.ErrorReport
+}
+
+func (sink *recordingSink) ReportError(report lifetime.ErrorReport) {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	sink.reports = append(sink.reports, report)
+}
+
+func TestLifetime_AddErrorSink(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	sink := &recordingSink{}
+	lt.AddErrorSink(sink)
+
+	failure := errors.New("failed")
+	lt.Start(&failingService{err: failure}, lifetime.WithName("api"))
+	_ = lt.Wait()
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.reports) != 1 {
+		t.Fatalf("expected 1 report, got %d", len(sink.reports))
+	}
+	report := sink.reports[0]
+	if report.Type != lifetime.EventServiceFailed || report.Service != "api" || !errors.Is(report.Err, failure) {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestWithSinkRateLimit(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(func(err error) {})
+	sink := &recordingSink{}
+	lt.AddErrorSink(sink, lifetime.WithSinkRateLimit(2, time.Hour))
+
+	for i := 0; i < 5; i++ {
+		lt.Go(func(ctx context.Context) error {
+			return errors.New("job failed")
+		})
+	}
+	_ = lt.Wait()
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.reports) != 2 {
+		t.Errorf("expected 2 reports, got %d", len(sink.reports))
+	}
+}
+
+func TestWithSinkSampleRate(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(func(err error) {})
+	sink := &recordingSink{}
+	lt.AddErrorSink(sink, lifetime.WithSinkSampleRate(0))
+
+	lt.Go(func(ctx context.Context) error {
+		return errors.New("job failed")
+	})
+	_ = lt.Wait()
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.reports) != 0 {
+		t.Errorf("expected no reports, got %d", len(sink.reports))
+	}
+}
@@ -0,0 +1,75 @@
+package lifetime
+
+import (
+	"fmt"
+	"os"
+)
+
+// ServiceError is used to wrap an error returned from a service.
+type ServiceError struct {
+	// Service is the name of the service.
+	Service string
+	// Labels are the labels that were attached to the service.
+	Labels Labels
+	// Metadata is the metadata of the service, if it has any.
+	Metadata *ServiceMetadata
+	// Err is the error returned by the service.
+	Err error
+}
+
+// Error returns the error message.
+// The owner, version and docs URL of the service are included if it has metadata.
+func (e *ServiceError) Error() string {
+	msg := fmt.Sprintf("service %s: %s", e.Service, e.Err.Error())
+	if len(e.Labels) > 0 {
+		msg = fmt.Sprintf("service %s [%s]: %s", e.Service, e.Labels, e.Err.Error())
+	}
+	if e.Metadata != nil && e.Metadata.String() != "" {
+		msg += " (" + e.Metadata.String() + ")"
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// SignalError is used when a shutdown signal is received.
+// Err is either ErrShutdownSignalReceived or ErrImmediateShutdownSignalReceived.
+type SignalError struct {
+	// Signal is the signal that was received.
+	Signal os.Signal
+	// Err describes the type of shutdown the signal caused.
+	Err error
+}
+
+// Error returns the error message.
+func (e *SignalError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Err.Error(), e.Signal.String())
+}
+
+// Unwrap returns the underlying error.
+func (e *SignalError) Unwrap() error {
+	return e.Err
+}
+
+// StageError is used when a func registered against a shutdown stage returns an error.
+type StageError struct {
+	// Stage is the stage the func was registered against.
+	Stage Stage
+	// Name is the name the func was registered with.
+	Name string
+	// Err is the error returned by the func.
+	Err error
+}
+
+// Error returns the error message.
+func (e *StageError) Error() string {
+	return fmt.Sprintf("stage %s: %s: %s", e.Stage, e.Name, e.Err.Error())
+}
+
+// Unwrap returns the underlying error.
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
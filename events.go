@@ -0,0 +1,160 @@
+package lifetime
+
+import (
+	"time"
+)
+
+// EventType describes what happened in an Event.
+type EventType string
+
+const (
+	// EventServiceStarted is emitted when the Start func of a service is about to be executed.
+	EventServiceStarted EventType = "service-started"
+	// EventServiceReady is emitted when a service that implements Readier reports that it is ready.
+	EventServiceReady EventType = "service-ready"
+	// EventServiceQuiesced is emitted during a shutdown once a service that implements Quiescer has quiesced.
+	// Err is set if it failed to quiesce.
+	EventServiceQuiesced EventType = "service-quiesced"
+	// EventServiceStopping is emitted when the Stop func of a service is about to be executed.
+	EventServiceStopping EventType = "service-stopping"
+	// EventServiceStopped is emitted once a service has been stopped and its Start func has returned.
+	EventServiceStopped EventType = "service-stopped"
+	// EventServiceFailed is emitted when the Start func of a service returns an error before
+	// the service was stopped.
+	EventServiceFailed EventType = "service-failed"
+	// EventServiceLateError is emitted when the Start func of a service returns an error after
+	// the service was stopped. These errors do not trigger a shutdown.
+	EventServiceLateError EventType = "service-late-error"
+	// EventServiceRetrying is emitted when a service fails with an error classified as ActionRetry,
+	// before it is started again.
+	EventServiceRetrying EventType = "service-retrying"
+	// EventServiceDegraded is emitted when a service fails with an error classified as ActionDegrade.
+	EventServiceDegraded EventType = "service-degraded"
+	// EventServiceDisabled is emitted when a service that has been disabled is started.
+	// See WithDisabledServices.
+	EventServiceDisabled EventType = "service-disabled"
+	// EventServicePanicked is emitted when the Start func of a service panics. Err is a ServiceError
+	// wrapping a PanicError, which contains the panic value and stack. See WithPanicPolicy.
+	EventServicePanicked EventType = "service-panicked"
+	// EventServicePaused is emitted when a service has been paused. See Pauser.
+	EventServicePaused EventType = "service-paused"
+	// EventServiceResumed is emitted when a paused service has been resumed. See Pauser.
+	EventServiceResumed EventType = "service-resumed"
+	// EventServiceKilled is emitted when a service is forcibly terminated because the shutdown timeout
+	// has been exceeded. See Killer.
+	EventServiceKilled EventType = "service-killed"
+	// EventServiceIdle is emitted when a service is stopped because it hasn't been used. See WithIdleStop.
+	EventServiceIdle EventType = "service-idle"
+	// EventServiceSwapped is emitted once a service has been replaced and the old instance has stopped. See Swap.
+	EventServiceSwapped EventType = "service-swapped"
+	// EventServiceStopSlow is emitted when the Stop func of a service has not returned within the
+	// threshold configured by WithStopWarning. It is emitted again each time the time waited doubles.
+	EventServiceStopSlow EventType = "service-stop-slow"
+	// EventReady is emitted when the startup phase of the lifetime has completed.
+	EventReady EventType = "ready"
+	// EventGatePassed is emitted when a readiness gate passes, either for the first time or
+	// after it had previously failed.
+	EventGatePassed EventType = "gate-passed"
+	// EventGateFailed is emitted when a readiness gate fails after previously passing, or
+	// when it fails for the first time.
+	EventGateFailed EventType = "gate-failed"
+	// EventWarmerCompleted is emitted when a warmer returns. Err is set if it failed.
+	EventWarmerCompleted EventType = "warmer-completed"
+	// EventBarrierPassed is emitted when a barrier is marked as done. See Barrier.
+	EventBarrierPassed EventType = "barrier-passed"
+	// EventBackpressure is emitted when a source reports that it is overloaded. See ReportBackpressure.
+	EventBackpressure EventType = "backpressure"
+	// EventBackpressureRelieved is emitted when an overloaded source reports that it has recovered.
+	EventBackpressureRelieved EventType = "backpressure-relieved"
+	// EventMaintenanceStarted is emitted once the services selected by a maintenance window have been
+	// paused or stopped. See ScheduleMaintenance.
+	EventMaintenanceStarted EventType = "maintenance-started"
+	// EventMaintenanceEnded is emitted once a maintenance window has ended and the services have been
+	// resumed or started again.
+	EventMaintenanceEnded EventType = "maintenance-ended"
+	// EventStageStarted is emitted when a shutdown stage is started.
+	EventStageStarted EventType = "stage-started"
+	// EventStageCompleted is emitted once every func in a shutdown stage has returned.
+	// Err is set to the first error returned by a func in the stage.
+	EventStageCompleted EventType = "stage-completed"
+	// EventShutdownStarted is emitted when a shutdown begins. Cause and Err describe what triggered it.
+	EventShutdownStarted EventType = "shutdown-started"
+	// EventShutdownComplete is emitted once every shutdown stage has completed.
+	EventShutdownComplete EventType = "shutdown-complete"
+	// EventJobFailed is emitted when a job executed with Go returns an error.
+	EventJobFailed EventType = "job-failed"
+)
+
+// Event describes something that happened within the lifetime.
+type Event struct {
+	// Type is the type of the event.
+	Type EventType
+	// Time is the time at which the event happened.
+	Time time.Time
+	// Service is the name of the service the event relates to, if any.
+	Service string
+	// Labels are the labels of the service the event relates to, if any.
+	Labels Labels
+	// Metadata is the metadata of the service the event relates to, if it has any.
+	Metadata *ServiceMetadata
+	// Gate is the name of the readiness gate the event relates to, if any.
+	Gate string
+	// Warmer is the name of the warmer the event relates to, if any.
+	Warmer string
+	// Barrier is the name of the barrier the event relates to, if any.
+	Barrier string
+	// Maintenance is the name of the maintenance window the event relates to, if any.
+	Maintenance string
+	// Source is the source of backpressure the event relates to, if any. See ReportBackpressure.
+	Source string
+	// Stage is the shutdown stage the event relates to, if any.
+	Stage Stage
+	// Cause is the cause of the shutdown, for EventShutdownStarted.
+	Cause Cause
+	// Err is the error associated with the event, if any.
+	Err error
+	// ShutdownID is the correlation ID of the shutdown, if the event happened during one. See ShutdownID.
+	ShutdownID string
+	// Duration is how long the action described by the event took, if applicable.
+	// For EventServiceStopped it is the time taken for the service to stop.
+	// For EventServiceQuiesced it is the time taken for the service to quiesce.
+	// For EventStageCompleted it is the time taken for the stage to complete.
+	// For EventReady it is the time taken from the creation of the lifetime until it was ready.
+	// For EventShutdownComplete it is the time taken for the shutdown to complete.
+	// For EventWarmerCompleted it is the time taken for the warmer to return.
+	Duration time.Duration
+	// Runtime is a snapshot of the runtime for EventShutdownStarted and EventShutdownComplete,
+	// if WithRuntimeSnapshots is used.
+	Runtime *RuntimeSnapshot
+}
+
+// EventHandler is used to handle events emitted by the lifetime.
+// Handlers are executed synchronously and must not block.
+type EventHandler func(event Event)
+
+// OnEvent registers a handler that is executed for every event emitted by the lifetime.
+func (lifetime *Lifetime) OnEvent(handler EventHandler) {
+	lifetime.eventHandlersMu.Lock()
+	defer lifetime.eventHandlersMu.Unlock()
+	lifetime.eventHandlers = append(lifetime.eventHandlers, handler)
+}
+
+// emit sends the given event to all registered event handlers.
+func (lifetime *Lifetime) emit(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.ShutdownID == "" {
+		event.ShutdownID = lifetime.ShutdownID()
+	}
+
+	lifetime.history.record(event)
+
+	lifetime.eventHandlersMu.RLock()
+	handlers := lifetime.eventHandlers
+	lifetime.eventHandlersMu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
@@ -0,0 +1,96 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// errOnStopService is a service whose Start func returns an error once it has been stopped.
+type errOnStopService struct {
+	stop chan struct{}
+	err  error
+}
+
+func (s *errOnStopService) Start() error {
+	<-s.stop
+	return s.err
+}
+
+func (s *errOnStopService) Stop() {
+	close(s.stop)
+}
+
+func TestLifetime_OnEvent_LateError(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	var mu sync.Mutex
+	events := make([]lifetime.Event, 0)
+	lt.OnEvent(func(event lifetime.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	})
+
+	lateErr := errors.New("late error")
+	lt.Start(&errOnStopService{stop: make(chan struct{}), err: lateErr}, lifetime.WithLabels(lifetime.Labels{"name": "late"}))
+
+	lt.Shutdown()
+	lt.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, event := range events {
+		if event.Type != lifetime.EventServiceLateError {
+			continue
+		}
+		if !errors.Is(event.Err, lateErr) {
+			t.Errorf("expected late error, got %v", event.Err)
+		}
+		if event.Labels["name"] != "late" {
+			t.Errorf("expected event labels to be set, got %v", event.Labels)
+		}
+		return
+	}
+	t.Errorf("expected a late error event to be emitted")
+}
+
+// slowStopService is a service whose Stop func takes a while to return.
+type slowStopService struct {
+	*blockingService
+	delay time.Duration
+}
+
+func (s *slowStopService) Stop() {
+	time.Sleep(s.delay)
+	s.blockingService.Stop()
+}
+
+func TestWithStopWarning(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithStopWarning(time.Millisecond*10))
+
+	slow := make(chan lifetime.Event, 10)
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceStopSlow {
+			slow <- event
+		}
+	})
+
+	lt.Start(&slowStopService{blockingService: newBlockingService(), delay: time.Millisecond * 50}, lifetime.WithName("slow"))
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(slow) < 2 {
+		t.Fatalf("expected at least 2 slow stop events, got %d", len(slow))
+	}
+	event := <-slow
+	if event.Service != "slow" {
+		t.Errorf("expected service name slow, got %q", event.Service)
+	}
+}
@@ -0,0 +1,87 @@
+package lifetime
+
+import (
+	"errors"
+	"syscall"
+)
+
+const (
+	// ExitCodeOK is used when the application shut down cleanly.
+	ExitCodeOK = 0
+	// ExitCodeRuntimeFailure is used when the application was shut down by an error after it was ready.
+	ExitCodeRuntimeFailure = 1
+	// ExitCodeStartupFailure is used when the application was shut down by an error before it was ready.
+	ExitCodeStartupFailure = 3
+	// ExitCodeShutdownTimeout is used when the graceful shutdown did not complete within the shutdown timeout.
+	ExitCodeShutdownTimeout = 4
+	// defaultImmediateExitCode is the code the application exits with on an immediate shutdown.
+	defaultImmediateExitCode = 1
+	// exitCodeSignalBase is added to the signal number when the application was shut down by a signal,
+	// following the shell convention. For example SIGTERM results in 143.
+	exitCodeSignalBase = 128
+)
+
+// WithImmediateShutdownExitCode sets the code the application exits with on an immediate shutdown, so that
+// supervisors can tell an operator that gave up waiting for the graceful shutdown apart from a crash.
+// It is also returned by ExitCode once an immediate shutdown has been triggered, for use with
+// WithImmediateShutdownReturn.
+// Defaults to 1.
+func WithImmediateShutdownExitCode(code int) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.immediateExitCode = code
+		lifetime.immediateExitCodeSet = true
+	}
+}
+
+// immediateExit returns the code the application exits with on an immediate shutdown.
+func (lifetime *Lifetime) immediateExit() int {
+	if lifetime.immediateExitCodeSet {
+		return lifetime.immediateExitCode
+	}
+	return defaultImmediateExitCode
+}
+
+// ExitCode returns the exit code that a supervisor such as systemd or Kubernetes would expect,
+// based on the cause of the shutdown:
+//   - ExitCodeOK for a manual shutdown, a done parent context or if no shutdown has been triggered.
+//   - 128 + the signal number for a shutdown signal, e.g. 143 for SIGTERM and 130 for SIGINT.
+//   - ExitCodeStartupFailure for an error before the lifetime was ready.
+//   - ExitCodeRuntimeFailure for an error after the lifetime was ready.
+//   - ExitCodeShutdownTimeout if the shutdown did not complete within the shutdown timeout.
+//   - The code given to WithImmediateShutdownExitCode, if it was used, for an immediate shutdown.
+func (lifetime *Lifetime) ExitCode() int {
+	if lifetime.immediateExitCodeSet {
+		select {
+		case <-lifetime.immediate:
+			return lifetime.immediateExitCode
+		default:
+		}
+	}
+	select {
+	case <-lifetime.shutdownTimedOut:
+		return ExitCodeShutdownTimeout
+	default:
+	}
+
+	lifetime.causeMu.Lock()
+	cause, err, duringStartup := lifetime.cause, lifetime.causeErr, lifetime.causeDuringStartup
+	lifetime.causeMu.Unlock()
+
+	switch cause {
+	case CauseNone, CauseManual, CauseParentContext:
+		return ExitCodeOK
+	case CauseSignal:
+		var signalErr *SignalError
+		if errors.As(err, &signalErr) {
+			if sig, ok := signalErr.Signal.(syscall.Signal); ok {
+				return exitCodeSignalBase + int(sig)
+			}
+		}
+		return ExitCodeRuntimeFailure
+	default:
+		if duringStartup {
+			return ExitCodeStartupFailure
+		}
+		return ExitCodeRuntimeFailure
+	}
+}
@@ -0,0 +1,97 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"github.com/tomwright/lifetime/lifetimetest"
+	"syscall"
+	"testing"
+)
+
+// failingService is a service whose Start func returns an error straight away, before it is ready.
+type failingService struct {
+	err error
+}
+
+func (s *failingService) Start() error {
+	return s.err
+}
+
+func (s *failingService) Stop() {}
+
+func (s *failingService) Ready() <-chan struct{} {
+	return make(chan struct{})
+}
+
+func TestLifetime_ExitCode(t *testing.T) {
+	t.Run("clean", func(t *testing.T) {
+		lt := lifetime.New(context.Background()).HandleErrors(nil)
+		lt.Start(newBlockingService())
+		lt.Shutdown()
+		_ = lt.Wait()
+		if got := lt.ExitCode(); got != lifetime.ExitCodeOK {
+			t.Errorf("expected %d, got %d", lifetime.ExitCodeOK, got)
+		}
+	})
+
+	t.Run("startup failure", func(t *testing.T) {
+		lt := lifetime.New(context.Background()).HandleErrors(nil)
+		lt.Start(&failingService{err: errors.New("bind failed")})
+		_ = lt.Wait()
+		if got := lt.ExitCode(); got != lifetime.ExitCodeStartupFailure {
+			t.Errorf("expected %d, got %d", lifetime.ExitCodeStartupFailure, got)
+		}
+	})
+
+	t.Run("runtime failure", func(t *testing.T) {
+		lt := lifetime.New(context.Background()).HandleErrors(nil)
+		lt.Start(newBlockingService())
+		go func() {
+			<-lt.Ready()
+			lt.ReportError(errors.New("connection lost"))
+		}()
+		_ = lt.Wait()
+		if got := lt.ExitCode(); got != lifetime.ExitCodeRuntimeFailure {
+			t.Errorf("expected %d, got %d", lifetime.ExitCodeRuntimeFailure, got)
+		}
+	})
+
+	t.Run("immediate shutdown", func(t *testing.T) {
+		lt := lifetime.New(context.Background(),
+			lifetime.WithImmediateShutdownReturn(),
+			lifetime.WithImmediateShutdownExitCode(75),
+		).HandleErrors(nil)
+		lt.OnEvent(func(event lifetime.Event) {
+			if event.Type == lifetime.EventServiceStopping {
+				lt.ReportError(lifetime.ErrImmediateShutdownSignalReceived)
+			}
+		})
+		lt.Start(&stuckService{})
+		lt.Shutdown()
+		_ = lt.Wait()
+		if got := lt.ExitCode(); got != 75 {
+			t.Errorf("expected 75, got %d", got)
+		}
+	})
+}
+
+func TestWithImmediateShutdownExitCode_Zero(t *testing.T) {
+	signals := lifetimetest.NewSignals()
+	exits := lifetimetest.NewExitRecorder()
+	lt := lifetime.New(context.Background(),
+		lifetime.WithSignalSource(signals),
+		lifetime.WithExitFunc(exits.Exit),
+		lifetime.WithImmediateShutdownExitCode(0),
+	).Init()
+	lt.Start(newBlockingService())
+
+	signals.Send(syscall.SIGTERM)
+	signals.Send(syscall.SIGTERM)
+	<-exits.Exited()
+	_ = lt.Wait()
+
+	if codes := exits.Codes(); len(codes) != 1 || codes[0] != 0 {
+		t.Errorf("expected a single exit with code 0, got %v", codes)
+	}
+}
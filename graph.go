@@ -0,0 +1,117 @@
+package lifetime
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Graph returns a Graphviz DOT description of the lifecycle topology: the running services grouped by
+// their group and labelled with their priority, their dependencies as dashed edges, the order they are
+// stopped in during a shutdown and the shutdown stages that follow.
+// It is based on PlanShutdown.
+func (lifetime *Lifetime) Graph() string {
+	plan := lifetime.PlanShutdown()
+
+	b := &strings.Builder{}
+	b.WriteString("digraph lifetime {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=ellipse];\n")
+	b.WriteString("\t\"shutdown\" [shape=doublecircle];\n")
+
+	// Group the service nodes into clusters.
+	groups := map[string][]PlannedService{}
+	for _, step := range plan.Steps {
+		for _, svc := range step {
+			groups[svc.Group] = append(groups[svc.Group], svc)
+		}
+	}
+	groupNames := make([]string, 0, len(groups))
+	for name := range groups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+	for _, name := range groupNames {
+		indent := "\t"
+		if name != "" {
+			fmt.Fprintf(b, "\tsubgraph %s {\n", strconv.Quote("cluster_"+name))
+			fmt.Fprintf(b, "\t\tlabel=%s;\n", strconv.Quote(name))
+			indent = "\t\t"
+		}
+		for _, svc := range groups[name] {
+			fmt.Fprintf(b, "%s%s [label=%s];\n", indent, graphServiceID(svc.ID), strconv.Quote(graphServiceLabel(svc)))
+		}
+		if name != "" {
+			b.WriteString("\t}\n")
+		}
+	}
+
+	// Link each service to the services it depends on.
+	for _, step := range plan.Steps {
+		for _, svc := range step {
+			for _, dependency := range svc.After {
+				fmt.Fprintf(b, "\t%s -> %s [style=dashed, label=\"after\"];\n", graphServiceID(svc.ID), graphServiceID(dependency))
+			}
+		}
+	}
+
+	// Link each step to the next in the order they are stopped.
+	previous := []string{strconv.Quote("shutdown")}
+	for _, step := range plan.Steps {
+		current := make([]string, 0, len(step))
+		for _, svc := range step {
+			current = append(current, graphServiceID(svc.ID))
+		}
+		graphEdges(b, previous, current)
+		if len(current) > 0 {
+			previous = current
+		}
+	}
+
+	for _, stage := range plan.Stages {
+		if stage.Stage == StageStopServices {
+			continue
+		}
+		id := strconv.Quote("stage:" + string(stage.Stage))
+		label := string(stage.Stage)
+		if len(stage.Funcs) > 0 {
+			label += "\n" + strings.Join(stage.Funcs, "\n")
+		}
+		fmt.Fprintf(b, "\t%s [shape=box, label=%s];\n", id, strconv.Quote(label))
+		graphEdges(b, previous, []string{id})
+		previous = []string{id}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// graphEdges writes an edge from every node in from to every node in to.
+func graphEdges(b *strings.Builder, from []string, to []string) {
+	for _, f := range from {
+		for _, t := range to {
+			fmt.Fprintf(b, "\t%s -> %s;\n", f, t)
+		}
+	}
+}
+
+// graphServiceID returns the ID of the node for the service with the given plan ID.
+func graphServiceID(id uint64) string {
+	return strconv.Quote("service:" + strconv.FormatUint(id, 10))
+}
+
+// graphServiceLabel returns the label of the node for the given service.
+func graphServiceLabel(svc PlannedService) string {
+	label := svc.Name
+	if len(svc.Labels) > 0 {
+		label += "\n" + svc.Labels.String()
+	}
+	if svc.Weight != 1 {
+		label += fmt.Sprintf("\nweight=%v", svc.Weight)
+	}
+	if svc.Priority != 0 {
+		label += fmt.Sprintf("\npriority=%d", svc.Priority)
+	}
+	return label
+}
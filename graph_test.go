@@ -0,0 +1,74 @@
+package lifetime_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/tomwright/lifetime"
+	"strings"
+	"testing"
+)
+
+func TestLifetime_Graph(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	db := lt.Start(newBlockingService(), lifetime.WithName("db"), lifetime.WithPriority(5))
+	lt.Start(newBlockingService(), lifetime.WithName("api"), lifetime.After(db))
+	lt.Group("workers").Start(newBlockingService(), lifetime.WithName("worker"))
+	lt.RegisterDrain("outbox", func(ctx context.Context) error {
+		return nil
+	})
+	if err := db.Ensure(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ids := map[string]string{}
+	for _, step := range lt.PlanShutdown().Steps {
+		for _, svc := range step {
+			ids[svc.Name] = fmt.Sprintf(`"service:%d"`, svc.ID)
+		}
+	}
+
+	graph := lt.Graph()
+
+	for _, exp := range []string{
+		"digraph lifetime {",
+		`subgraph "cluster_workers" {`,
+		ids["worker"] + ` [label="worker"];`,
+		ids["db"] + ` [label="db\npriority=5"];`,
+		ids["api"] + " -> " + ids["db"] + ` [style=dashed, label="after"];`,
+		`"shutdown" -> ` + ids["api"] + ";",
+		ids["db"] + ` -> "stage:drain";`,
+		`"stage:drain" [shape=box, label="drain\noutbox"];`,
+		`"stage:drain" -> "stage:close-storage";`,
+	} {
+		if !strings.Contains(graph, exp) {
+			t.Errorf("expected graph to contain %s, got:\n%s", exp, graph)
+		}
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+}
+
+func TestLifetime_Graph_SameName(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(newBlockingService())
+	lt.Start(newBlockingService(), lifetime.WithClass(lifetime.ClassStateful))
+
+	plan := lt.PlanShutdown()
+	if len(plan.Steps) != 2 || plan.Steps[0][0].Name != plan.Steps[1][0].Name {
+		t.Fatalf("expected two steps with services of the same name, got %v", plan.Steps)
+	}
+	first := fmt.Sprintf(`"service:%d"`, plan.Steps[0][0].ID)
+	second := fmt.Sprintf(`"service:%d"`, plan.Steps[1][0].ID)
+
+	graph := lt.Graph()
+	if first == second || !strings.Contains(graph, first+" -> "+second+";") {
+		t.Errorf("expected an edge between the two services, got:\n%s", graph)
+	}
+	if strings.Contains(graph, first+" -> "+first) || strings.Contains(graph, second+" -> "+second) {
+		t.Errorf("expected no self-loops, got:\n%s", graph)
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+}
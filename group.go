@@ -0,0 +1,106 @@
+package lifetime
+
+import (
+	"context"
+	"sync"
+)
+
+// Group returns the service group with the given name, creating it if it doesn't exist.
+// Services started through a group can be stopped, restarted or drained together
+// without affecting the rest of the application.
+func (lifetime *Lifetime) Group(name string) *Group {
+	lifetime.groupsMu.Lock()
+	defer lifetime.groupsMu.Unlock()
+
+	if group, ok := lifetime.groups[name]; ok {
+		return group
+	}
+	group := &Group{
+		name:     name,
+		lifetime: lifetime,
+	}
+	lifetime.groups[name] = group
+	return group
+}
+
+// Group is a named set of services that are managed together.
+type Group struct {
+	name     string
+	lifetime *Lifetime
+
+	membersMu sync.Mutex
+	members   []groupMember
+}
+
+// groupMember is a service that has been started through a group, along with the options
+// it was started with so that it can be restarted.
+type groupMember struct {
+	svc  Service
+	opts []StartOption
+}
+
+// Name returns the name of the group.
+func (group *Group) Name() string {
+	return group.name
+}
+
+// Start will start the given service as part of the group.
+func (group *Group) Start(svc Service, opts ...StartOption) *ServiceHandle {
+	group.membersMu.Lock()
+	group.members = append(group.members, groupMember{svc: svc, opts: opts})
+	group.membersMu.Unlock()
+
+	return group.start(svc, opts)
+}
+
+// start starts a single member of the group.
+func (group *Group) start(svc Service, opts []StartOption) *ServiceHandle {
+	opts = append([]StartOption{withGroup(group)}, opts...)
+	return group.lifetime.Start(svc, opts...)
+}
+
+// Stop stops all running services in the group and blocks until they have finished execution.
+func (group *Group) Stop() {
+	_ = group.Drain(context.Background())
+}
+
+// Drain stops all running services in the group and waits for them to finish execution
+// or for the given context to be done, whichever happens first.
+// The context error is returned if the services did not finish in time.
+func (group *Group) Drain(ctx context.Context) error {
+	return group.lifetime.stopWhere(ctx, func(managed *managedService) bool {
+		return managed.group == group
+	})
+}
+
+// Restart stops all running services in the group and then starts them again.
+// Services are not restarted if the application is shutting down.
+// Services that implement StateHandoff carry their state over to the new instance.
+// Note that some services, such as those wrapping an http.Server, cannot be started again once stopped.
+func (group *Group) Restart() {
+	group.Stop()
+
+	if group.lifetime.ctx.Err() != nil {
+		return
+	}
+
+	group.membersMu.Lock()
+	members := make([]groupMember, len(group.members))
+	copy(members, group.members)
+	group.membersMu.Unlock()
+
+	for _, member := range members {
+		opts := member.opts
+		if state := snapshot(member.svc); state != nil {
+			opts = append([]StartOption{withHandoffState(state)}, opts...)
+		}
+		group.start(member.svc, opts)
+	}
+}
+
+// withGroup marks the service as belonging to the given group.
+func withGroup(group *Group) StartOption {
+	return func(managed *managedService) {
+		managed.group = group
+	}
+}
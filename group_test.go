@@ -0,0 +1,84 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+)
+
+// restartableService is a service that can be started again after being stopped.
+// Stop may be called before Start has run, in which case the next Start returns immediately.
+type restartableService struct {
+	mu          sync.Mutex
+	stop        chan struct{}
+	pendingStop bool
+	starts      int
+	stops       int
+}
+
+func (s *restartableService) Start() error {
+	s.mu.Lock()
+	s.starts++
+	if s.pendingStop {
+		s.pendingStop = false
+		s.mu.Unlock()
+		return nil
+	}
+	stop := make(chan struct{})
+	s.stop = stop
+	s.mu.Unlock()
+	<-stop
+	return nil
+}
+
+func (s *restartableService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stops++
+	if s.stop == nil {
+		s.pendingStop = true
+		return
+	}
+	close(s.stop)
+	s.stop = nil
+}
+
+func (s *restartableService) counts() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.starts, s.stops
+}
+
+func TestGroup(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	ingestA := &restartableService{}
+	ingestB := &restartableService{}
+	api := newBlockingService()
+
+	if lt.Group("ingestion") != lt.Group("ingestion") {
+		t.Fatalf("expected the same group to be returned")
+	}
+
+	g := lt.Group("ingestion")
+	g.Start(ingestA)
+	g.Start(ingestB)
+	lt.Start(api)
+
+	g.Restart()
+	g.Stop()
+
+	for _, svc := range []*restartableService{ingestA, ingestB} {
+		// Start may not have been called a second time before Stop, but Stop must be called twice.
+		if _, stops := svc.counts(); stops != 2 {
+			t.Errorf("expected 2 stops, got %d", stops)
+		}
+	}
+	if api.stopped {
+		t.Errorf("expected api service to still be running")
+	}
+
+	lt.Shutdown()
+	lt.Wait()
+}
@@ -0,0 +1,122 @@
+package lifetime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ServiceHandle is returned when a service is started and can be used to control
+// the service individually, without affecting the rest of the application.
+type ServiceHandle struct {
+	lifetime *Lifetime
+	opts     []StartOption
+
+	mu sync.RWMutex
+	// svc is the service the handle controls. It is replaced by Swap.
+	svc Service
+	// managed is the current running instance of the service.
+	// It is nil for a service registered with Lazy until the service is started.
+	managed *managedService
+	// ensureMu ensures that a lazy service is only started once. See Ensure.
+	ensureMu sync.Mutex
+	// registered is true once the handle is included by Services. It is guarded by the servicesMu of the lifetime.
+	registered bool
+	// idleStopped is true if the service was stopped because it was idle. See WithIdleStop.
+	idleStopped bool
+
+	usedMu sync.Mutex
+	// lastUsed is when the service was last used. See Touch.
+	lastUsed time.Time
+
+	handoffMu sync.Mutex
+	// handoff is the state to restore when the service is next started. See StateHandoff.
+	handoff *handoffState
+}
+
+// setManaged sets the current running instance of the service.
+func (handle *ServiceHandle) setManaged(managed *managedService) {
+	handle.mu.Lock()
+	defer handle.mu.Unlock()
+	handle.managed = managed
+}
+
+// current returns the current running instance of the service.
+func (handle *ServiceHandle) current() *managedService {
+	handle.mu.RLock()
+	defer handle.mu.RUnlock()
+	return handle.managed
+}
+
+// Service returns the service the handle controls.
+func (handle *ServiceHandle) Service() Service {
+	handle.mu.RLock()
+	defer handle.mu.RUnlock()
+	return handle.svc
+}
+
+// describe returns the current running instance of the service or, for a service registered with Lazy
+// that has not been started, an instance that describes the service without being executed.
+func (handle *ServiceHandle) describe() *managedService {
+	if managed := handle.current(); managed != nil {
+		return managed
+	}
+	return handle.lifetime.newInstance(handle, handle.Service())
+}
+
+// Name returns the name of the service.
+func (handle *ServiceHandle) Name() string {
+	return handle.describe().name
+}
+
+// Labels returns the labels attached to the service.
+func (handle *ServiceHandle) Labels() Labels {
+	return handle.describe().labels
+}
+
+// Context returns the context of the service.
+// It is a child of the lifetime context and is done when the service is stopped,
+// whether individually or as part of a shutdown.
+// For a service registered with Lazy that has not been started, the lifetime context is returned.
+func (handle *ServiceHandle) Context() context.Context {
+	managed := handle.current()
+	if managed == nil {
+		return handle.lifetime.ctx
+	}
+	return managed.ctx
+}
+
+// Done returns a channel that is closed once the service has finished execution.
+// For a service registered with Lazy that has not been started, the channel is closed once the
+// application shuts down, after which the service can no longer be started.
+func (handle *ServiceHandle) Done() <-chan struct{} {
+	managed := handle.current()
+	if managed == nil {
+		return handle.lifetime.ctx.Done()
+	}
+	return managed.exited
+}
+
+// Stop stops the service and blocks until it has finished execution.
+// It does nothing for a service registered with Lazy that has not been started.
+func (handle *ServiceHandle) Stop() {
+	managed := handle.current()
+	if managed == nil {
+		return
+	}
+	managed.stop()
+}
+
+// Restart stops the service and then starts it again with the same options.
+// The service is not restarted if the application is shutting down.
+// Services that implement StateHandoff carry their state over to the new instance.
+// Note that some services, such as those wrapping an http.Server, cannot be started again once stopped.
+func (handle *ServiceHandle) Restart() {
+	handle.Stop()
+
+	if handle.lifetime.ctx.Err() != nil {
+		return
+	}
+	handle.setHandoffState(snapshot(handle.Service()))
+	handle.lifetime.start(handle)
+}
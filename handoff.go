@@ -0,0 +1,60 @@
+package lifetime
+
+// StateHandoff can be implemented by services that hold state, such as offsets or in-memory caches,
+// that should be carried over when the service is restarted, whether by Restart, by Group.Restart or
+// because an error policy retried it. Snapshot is called once the old instance has finished execution,
+// and the state it returns is passed to Restore just before the new instance is started.
+// When a service is replaced by Swap, Snapshot is called while the old instance is still running.
+type StateHandoff interface {
+	// Snapshot returns the state to hand to the new instance.
+	Snapshot() interface{}
+	// Restore receives the state returned by Snapshot.
+	Restore(state interface{})
+}
+
+// handoffState is the state of a service waiting to be restored. See StateHandoff.
+type handoffState struct {
+	value interface{}
+}
+
+// snapshot takes the state of the given service, if it implements StateHandoff.
+// It returns nil if it does not.
+func snapshot(svc Service) *handoffState {
+	handoff, ok := svc.(StateHandoff)
+	if !ok {
+		return nil
+	}
+	return &handoffState{value: handoff.Snapshot()}
+}
+
+// withHandoffState restores the given state when the service is started.
+func withHandoffState(state *handoffState) StartOption {
+	return func(managed *managedService) {
+		managed.handle.setHandoffState(state)
+	}
+}
+
+// setHandoffState sets the state to restore when the service is next started.
+func (handle *ServiceHandle) setHandoffState(state *handoffState) {
+	if state == nil {
+		return
+	}
+	handle.handoffMu.Lock()
+	defer handle.handoffMu.Unlock()
+	handle.handoff = state
+}
+
+// restore passes the state taken from the previous instance of the service, if any, to Restore.
+func (managed *managedService) restore() {
+	managed.handle.handoffMu.Lock()
+	state := managed.handle.handoff
+	managed.handle.handoff = nil
+	managed.handle.handoffMu.Unlock()
+
+	if state == nil {
+		return
+	}
+	if handoff, ok := managed.svc.(StateHandoff); ok {
+		handoff.Restore(state.value)
+	}
+}
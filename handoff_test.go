@@ -0,0 +1,108 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// handoffService records the state handed to it when it is restarted.
+type handoffService struct {
+	lifetime.Service
+	snapshot func() interface{}
+
+	mu       sync.Mutex
+	restored []interface{}
+}
+
+func (s *handoffService) Snapshot() interface{} {
+	return s.snapshot()
+}
+
+func (s *handoffService) Restore(state interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.restored = append(s.restored, state)
+}
+
+func (s *handoffService) states() []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]interface{}{}, s.restored...)
+}
+
+func TestServiceHandle_Restart_StateHandoff(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	inner := &restartableService{}
+	svc := &handoffService{Service: inner, snapshot: func() interface{} {
+		starts, _ := inner.counts()
+		return starts
+	}}
+	handle := lt.Start(svc)
+	handle.Restart()
+	handle.Restart()
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	states := svc.states()
+	if len(states) != 2 || states[0] != 1 || states[1] != 2 {
+		t.Errorf("expected the state of each instance to be restored, got %v", states)
+	}
+}
+
+func TestLifetime_AddErrorPolicy_StateHandoff(t *testing.T) {
+	transient := errors.New("transient")
+	lt := lifetime.New(context.Background(), lifetime.WithErrorRetryDelay(time.Millisecond)).HandleErrors(nil)
+	lt.AddErrorPolicy(lifetime.ErrorIs(transient, lifetime.ActionRetry))
+
+	inner := &flakyService{err: transient, failures: 1, stop: make(chan struct{})}
+	svc := &handoffService{Service: inner, snapshot: func() interface{} {
+		return "offset"
+	}}
+	retried := make(chan struct{})
+	lt.OnEvent(func(event lifetime.Event) {
+		inner.mu.Lock()
+		defer inner.mu.Unlock()
+		if event.Type == lifetime.EventServiceStarted && inner.starts == 1 {
+			close(retried)
+		}
+	})
+	lt.Start(svc)
+	<-retried
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if states := svc.states(); len(states) != 1 || states[0] != "offset" {
+		t.Errorf("expected the state to be restored once, got %v", states)
+	}
+}
+
+func TestGroup_Restart_StateHandoff(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	svc := &handoffService{Service: &restartableService{}, snapshot: func() interface{} {
+		return "cache"
+	}}
+	g := lt.Group("ingestion")
+	g.Start(svc)
+	g.Restart()
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if states := svc.states(); len(states) != 1 || states[0] != "cache" {
+		t.Errorf("expected the state to be restored once, got %v", states)
+	}
+}
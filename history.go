@@ -0,0 +1,109 @@
+package lifetime
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultHistorySize is the number of entries kept in the history by default.
+const defaultHistorySize = 256
+
+// WithHistorySize sets the number of entries kept in the history, with the oldest entries being
+// discarded first. A size of 0 disables the history.
+// Defaults to 256.
+func WithHistorySize(size int) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.history = newHistory(size)
+	}
+}
+
+// HistoryEntry is a record of a single transition within the lifetime.
+type HistoryEntry struct {
+	// Time is the time at which the transition happened.
+	Time time.Time `json:"time"`
+	// Type is the type of event that describes the transition.
+	Type EventType `json:"type"`
+	// Service is the name of the service the transition relates to, if any.
+	Service string `json:"service,omitempty"`
+	// Labels are the labels of the service the transition relates to, if any.
+	Labels Labels `json:"labels,omitempty"`
+	// Gate is the name of the readiness gate the transition relates to, if any.
+	Gate string `json:"gate,omitempty"`
+	// Stage is the shutdown stage the transition relates to, if any.
+	Stage Stage `json:"stage,omitempty"`
+	// Cause is the cause of the shutdown, for EventShutdownStarted.
+	Cause string `json:"cause,omitempty"`
+	// Error is the error associated with the transition, if any.
+	Error string `json:"error,omitempty"`
+	// Duration is how long the transition took, if applicable.
+	Duration time.Duration `json:"duration,omitempty"`
+	// ShutdownID is the correlation ID of the shutdown, if the transition happened during one.
+	ShutdownID string `json:"shutdownId,omitempty"`
+}
+
+// History returns the recorded transitions of the lifetime, oldest first.
+// Every emitted event is recorded, up to the size set by WithHistorySize.
+func (lifetime *Lifetime) History() []HistoryEntry {
+	return lifetime.history.entries()
+}
+
+// newHistory returns a history that keeps up to the given number of entries.
+func newHistory(size int) *history {
+	if size < 0 {
+		size = 0
+	}
+	return &history{
+		buf: make([]HistoryEntry, 0, size),
+		max: size,
+	}
+}
+
+// history is a bounded, in-memory record of transitions.
+type history struct {
+	mu   sync.Mutex
+	buf  []HistoryEntry
+	max  int
+	next int
+}
+
+// record adds an entry for the given event, discarding the oldest entry if the history is full.
+func (history *history) record(event Event) {
+	if history.max == 0 {
+		return
+	}
+	entry := HistoryEntry{
+		Time:       event.Time,
+		Type:       event.Type,
+		Service:    event.Service,
+		Labels:     event.Labels,
+		Gate:       event.Gate,
+		Stage:      event.Stage,
+		Duration:   event.Duration,
+		ShutdownID: event.ShutdownID,
+	}
+	if event.Cause != CauseNone {
+		entry.Cause = event.Cause.String()
+	}
+	if event.Err != nil {
+		entry.Error = event.Err.Error()
+	}
+
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	if len(history.buf) < history.max {
+		history.buf = append(history.buf, entry)
+		return
+	}
+	history.buf[history.next] = entry
+	history.next = (history.next + 1) % history.max
+}
+
+// entries returns a copy of the entries, oldest first.
+func (history *history) entries() []HistoryEntry {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	entries := make([]HistoryEntry, 0, len(history.buf))
+	entries = append(entries, history.buf[history.next:]...)
+	entries = append(entries, history.buf[:history.next]...)
+	return entries
+}
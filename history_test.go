@@ -0,0 +1,52 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+)
+
+func TestLifetime_History(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(newBlockingService(), lifetime.WithName("worker"))
+	lt.ReportError(errors.New("something broke"))
+	_ = lt.Wait()
+
+	history := lt.History()
+
+	var started, stopped, shutdown bool
+	for _, entry := range history {
+		switch entry.Type {
+		case lifetime.EventServiceStarted:
+			started = entry.Service == "worker"
+		case lifetime.EventServiceStopped:
+			stopped = entry.Service == "worker"
+		case lifetime.EventShutdownStarted:
+			shutdown = entry.Cause == lifetime.CauseServiceError.String() && entry.Error == "something broke"
+		}
+	}
+	if !started || !stopped || !shutdown {
+		t.Errorf("expected start, stop and shutdown to be recorded, got %+v", history)
+	}
+	if last := history[len(history)-1]; last.Type != lifetime.EventShutdownComplete {
+		t.Errorf("expected the last entry to be shutdown complete, got %s", last.Type)
+	}
+}
+
+func TestWithHistorySize(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithHistorySize(2)).HandleErrors(nil)
+	for i := 0; i < 3; i++ {
+		lt.Start(newBlockingService())
+	}
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	history := lt.History()
+	if len(history) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(history))
+	}
+	if history[1].Type != lifetime.EventShutdownComplete {
+		t.Errorf("expected the newest entries to be kept, got %+v", history)
+	}
+}
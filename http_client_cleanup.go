@@ -0,0 +1,80 @@
+package lifetime
+
+import (
+	"context"
+	"sync"
+)
+
+// IdleConnectionCloser is implemented by http.Transport and http.Client.
+type IdleConnectionCloser interface {
+	CloseIdleConnections()
+}
+
+// HTTPClientCleanup closes the idle connections of the http transports used for outgoing requests, and cancels
+// any requests still in progress, during the StageCloseStorage stage of a shutdown. This stops sockets from
+// lingering once the application has exited.
+// It is returned by Lifetime.HTTPClientCleanup.
+type HTTPClientCleanup struct {
+	mu         sync.Mutex
+	transports []IdleConnectionCloser
+	// cancelFuncs are the cancel funcs of the contexts that have been returned by Context and not yet cancelled.
+	cancelFuncs map[*context.CancelFunc]struct{}
+	// closed is true once the cleanup has been executed, after which new contexts are cancelled straight away.
+	closed bool
+}
+
+// HTTPClientCleanup returns a HTTPClientCleanup that is executed during the StageCloseStorage stage, registered
+// with the given name.
+func (lifetime *Lifetime) HTTPClientCleanup(name string) *HTTPClientCleanup {
+	cleanup := &HTTPClientCleanup{cancelFuncs: map[*context.CancelFunc]struct{}{}}
+	lifetime.OnStage(StageCloseStorage, name, func(ctx context.Context) error {
+		cleanup.close()
+		return nil
+	})
+	return cleanup
+}
+
+// AddTransport registers a transport, or client, whose idle connections are closed during the cleanup.
+func (cleanup *HTTPClientCleanup) AddTransport(transport IdleConnectionCloser) {
+	cleanup.mu.Lock()
+	defer cleanup.mu.Unlock()
+	cleanup.transports = append(cleanup.transports, transport)
+}
+
+// Context returns a context for an outgoing request that is done when the given parent is done, the returned
+// cancel func is called or the cleanup is executed, whichever happens first.
+func (cleanup *HTTPClientCleanup) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	cleanup.mu.Lock()
+	defer cleanup.mu.Unlock()
+	if cleanup.closed {
+		cancel()
+		return ctx, cancel
+	}
+	key := &cancel
+	cleanup.cancelFuncs[key] = struct{}{}
+	return ctx, func() {
+		cancel()
+		cleanup.mu.Lock()
+		delete(cleanup.cancelFuncs, key)
+		cleanup.mu.Unlock()
+	}
+}
+
+// close cancels the outstanding contexts and closes the idle connections of every transport.
+func (cleanup *HTTPClientCleanup) close() {
+	cleanup.mu.Lock()
+	cleanup.closed = true
+	cancelFuncs := cleanup.cancelFuncs
+	cleanup.cancelFuncs = map[*context.CancelFunc]struct{}{}
+	transports := cleanup.transports
+	cleanup.mu.Unlock()
+
+	for cancel := range cancelFuncs {
+		(*cancel)()
+	}
+	for _, transport := range transports {
+		transport.CloseIdleConnections()
+	}
+}
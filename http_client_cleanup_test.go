@@ -0,0 +1,47 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"net/http"
+	"testing"
+)
+
+// countingTransport counts the number of times its idle connections are closed.
+type countingTransport struct {
+	closed int
+}
+
+func (transport *countingTransport) CloseIdleConnections() {
+	transport.closed++
+}
+
+func TestLifetime_HTTPClientCleanup(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	cleanup := lt.HTTPClientCleanup("outgoing")
+	transport := &countingTransport{}
+	cleanup.AddTransport(transport)
+	cleanup.AddTransport(&http.Client{Transport: &http.Transport{}})
+
+	outstanding, cancelOutstanding := cleanup.Context(context.Background())
+	defer cancelOutstanding()
+	completed, cancelCompleted := cleanup.Context(context.Background())
+	cancelCompleted()
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if transport.closed != 1 {
+		t.Errorf("expected idle connections to be closed once, got %d", transport.closed)
+	}
+	if outstanding.Err() != context.Canceled || completed.Err() != context.Canceled {
+		t.Errorf("expected outstanding contexts to be cancelled")
+	}
+	if late, cancel := cleanup.Context(context.Background()); late.Err() == nil {
+		t.Errorf("expected contexts created after the cleanup to be cancelled")
+		cancel()
+	}
+}
@@ -0,0 +1,58 @@
+package lifetime
+
+import (
+	"time"
+)
+
+// WithIdleStop stops the service once it hasn't been used for the given duration, freeing its resources
+// without affecting the rest of the application. Use is recorded by Ensure and Touch on the handle of the service,
+// and the next call to Ensure starts the service again. It is usually combined with Lazy.
+// Note that some services, such as those wrapping an http.Server, cannot be started again once stopped.
+func WithIdleStop(timeout time.Duration) StartOption {
+	return func(managed *managedService) {
+		managed.idleTimeout = timeout
+	}
+}
+
+// Touch records that the service has been used, which holds back an idle stop. See WithIdleStop.
+func (handle *ServiceHandle) Touch() {
+	handle.usedMu.Lock()
+	defer handle.usedMu.Unlock()
+	handle.lastUsed = time.Now()
+}
+
+// idleFor returns how long it has been since the service was last used.
+func (handle *ServiceHandle) idleFor() time.Duration {
+	handle.usedMu.Lock()
+	defer handle.usedMu.Unlock()
+	return time.Since(handle.lastUsed)
+}
+
+// watchIdle stops the service once it has been idle for its idle timeout.
+// It returns once the service has exited.
+func (managed *managedService) watchIdle() {
+	timer := time.NewTimer(managed.idleTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-managed.exited:
+			return
+		case <-timer.C:
+		}
+
+		handle := managed.handle
+		handle.ensureMu.Lock()
+		idle := handle.idleFor()
+		if idle < managed.idleTimeout {
+			handle.ensureMu.Unlock()
+			timer.Reset(managed.idleTimeout - idle)
+			continue
+		}
+		managed.log(LevelInfo, "lifetime service idle, stopping", nil, Field{Key: "idle", Value: idle})
+		handle.idleStopped = true
+		managed.stop()
+		managed.lifetime.emit(Event{Type: EventServiceIdle, Service: managed.name, Labels: managed.labels, Metadata: managed.metadata})
+		handle.ensureMu.Unlock()
+		return
+	}
+}
@@ -0,0 +1,72 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithIdleStop(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	idle := make(chan struct{}, 1)
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceIdle {
+			idle <- struct{}{}
+		}
+	})
+
+	svc := &restartableService{}
+	handle := lt.Lazy(svc, lifetime.WithName("search"), lifetime.WithIdleStop(20*time.Millisecond))
+	if err := handle.Ensure(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-idle:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the service to be stopped once idle")
+	}
+	if got := len(lt.Status().Services); got != 0 {
+		t.Errorf("expected no running services, got %d", got)
+	}
+
+	if err := handle.Ensure(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if starts, _ := svc.counts(); starts != 2 {
+		t.Errorf("expected the service to be started again, got %d starts", starts)
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestServiceHandle_Touch(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	var idle int32
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceIdle {
+			atomic.AddInt32(&idle, 1)
+		}
+	})
+
+	handle := lt.Start(&restartableService{}, lifetime.WithIdleStop(50*time.Millisecond))
+	for i := 0; i < 10; i++ {
+		time.Sleep(10 * time.Millisecond)
+		handle.Touch()
+	}
+	if got := atomic.LoadInt32(&idle); got != 0 {
+		t.Errorf("expected a used service not to be stopped, got %d idle stops", got)
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
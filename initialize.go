@@ -0,0 +1,90 @@
+package lifetime
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// waitForRetryInterval is how long the WaitFor initializers wait between attempts.
+const waitForRetryInterval = time.Millisecond * 250
+
+// Initializer is executed before services are started, to prepare or wait for anything they depend on.
+type Initializer func(ctx context.Context) error
+
+// Pinger is implemented by dependencies that can be pinged, such as *sql.DB.
+type Pinger interface {
+	// PingContext checks that the dependency is available.
+	PingContext(ctx context.Context) error
+}
+
+// Initialize executes the given initializers in order using the lifetime context.
+// It stops at and returns the first error.
+func (lifetime *Lifetime) Initialize(initializers ...Initializer) error {
+	for _, initializer := range initializers {
+		if err := initializer(lifetime.ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WaitForTCP returns an initializer that waits until a TCP connection can be made to the given address.
+// An error is returned if no connection could be made within the timeout.
+func WaitForTCP(addr string, timeout time.Duration) Initializer {
+	return waitFor(fmt.Sprintf("tcp %s", addr), timeout, func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+}
+
+// WaitForHTTP returns an initializer that waits until a GET request to the given url responds
+// with a 2xx status code.
+// An error is returned if no such response was received within the timeout.
+func WaitForHTTP(url string, timeout time.Duration) Initializer {
+	return waitFor(fmt.Sprintf("http %s", url), timeout, func(ctx context.Context) error {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+		return nil
+	})
+}
+
+// WaitForPing returns an initializer that waits until the given pinger can be pinged.
+// An error is returned if no ping succeeded within the timeout.
+func WaitForPing(pinger Pinger, timeout time.Duration) Initializer {
+	return waitFor(fmt.Sprintf("ping %T", pinger), timeout, pinger.PingContext)
+}
+
+// waitFor returns an initializer that executes the given attempt until it succeeds or the timeout is reached.
+func waitFor(target string, timeout time.Duration, attempt func(ctx context.Context) error) Initializer {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		for {
+			err := attempt(ctx)
+			if err == nil {
+				return nil
+			}
+			if !sleepContext(ctx, waitForRetryInterval) {
+				return fmt.Errorf("could not wait for %s within %s: %w", target, timeout, err)
+			}
+		}
+	}
+}
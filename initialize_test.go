@@ -0,0 +1,56 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// pinger is a lifetime.Pinger that fails a number of times before succeeding.
+type pinger struct {
+	failures int
+}
+
+func (p *pinger) PingContext(ctx context.Context) error {
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("not yet")
+	}
+	return nil
+}
+
+func TestLifetime_Initialize(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	defer lis.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	lt := lifetime.New(context.Background())
+	err = lt.Initialize(
+		lifetime.WaitForTCP(lis.Addr().String(), time.Second),
+		lifetime.WaitForHTTP(server.URL, time.Second),
+		lifetime.WaitForPing(&pinger{failures: 1}, time.Second),
+	)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWaitForPing_Timeout(t *testing.T) {
+	lt := lifetime.New(context.Background())
+	err := lt.Initialize(lifetime.WaitForPing(&pinger{failures: 100}, time.Millisecond*100))
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,33 @@
+package lifetime
+
+import (
+	"context"
+)
+
+// Job is a short-lived unit of work.
+// The given context is the lifetime context and will be done when a shutdown is triggered.
+// Returns an error which is treated as fatal.
+type Job func(ctx context.Context) error
+
+// Go executes the given job in a go routine.
+// Jobs are tracked by Wait in the same way as services, but they have no Stop func and no
+// per-job state is kept, which makes Go suitable for high-churn, short-lived work on hot paths.
+// A job is expected to return once the lifetime context is done.
+func (lifetime *Lifetime) Go(job Job) {
+	lifetime.serviceWg.Add(1)
+	go lifetime.runJob(job)
+}
+
+// runJob executes the given job and reports any error it returns.
+func (lifetime *Lifetime) runJob(job Job) {
+	defer lifetime.serviceWg.Done()
+
+	err := job(lifetime.ctx)
+	if err == nil {
+		return
+	}
+
+	lifetime.emit(Event{Type: EventJobFailed, Err: err})
+
+	lifetime.reportError(err)
+}
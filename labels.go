@@ -0,0 +1,76 @@
+package lifetime
+
+import (
+	"sort"
+	"strings"
+)
+
+// Labels are arbitrary key/value pairs that can be attached to a service when it is started.
+// They are included in errors returned from the service and can be used to select a subset
+// of services.
+type Labels map[string]string
+
+// Matches returns true if the labels contain every key/value pair in the given selector.
+// An empty selector matches everything.
+func (labels Labels) Matches(selector Labels) bool {
+	for k, v := range selector {
+		if got, ok := labels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the labels in the format `key=value,key=value`, sorted by key.
+func (labels Labels) String() string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + labels[k]
+	}
+	return strings.Join(pairs, ",")
+}
+
+// StartOption is used to configure a service when it is started.
+type StartOption func(managed *managedService)
+
+// WithName sets the name of the service, which is used in logs, events and errors.
+// Defaults to the type name of the service.
+func WithName(name string) StartOption {
+	return func(managed *managedService) {
+		managed.name = name
+	}
+}
+
+// WithLabels attaches the given labels to the service.
+// It can be used multiple times, with later labels overwriting earlier ones.
+func WithLabels(labels Labels) StartOption {
+	return func(managed *managedService) {
+		if managed.labels == nil {
+			managed.labels = Labels{}
+		}
+		for k, v := range labels {
+			managed.labels[k] = v
+		}
+	}
+}
+
+// NonCritical marks the service as one the application can run without.
+// If the service fails, the failure is logged and emitted as an EventServiceFailed but a shutdown
+// is not triggered. Non-critical services do not hold back the startup phase.
+func NonCritical() StartOption {
+	return func(managed *managedService) {
+		managed.nonCritical = true
+	}
+}
+
+// Criticality can be implemented by a service to declare whether the application can run without it.
+type Criticality interface {
+	// Critical returns false if the service is one the application can run without. See NonCritical.
+	Critical() bool
+}
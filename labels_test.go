@@ -0,0 +1,86 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"testing"
+)
+
+// blockingService is a service that blocks until it is stopped.
+type blockingService struct {
+	stop    chan struct{}
+	stopped bool
+}
+
+func newBlockingService() *blockingService {
+	return &blockingService{stop: make(chan struct{})}
+}
+
+func (s *blockingService) Start() error {
+	<-s.stop
+	return nil
+}
+
+func (s *blockingService) Stop() {
+	s.stopped = true
+	close(s.stop)
+}
+
+func TestLabels_Matches(t *testing.T) {
+	labels := lifetime.Labels{"team": "data", "tier": "worker"}
+
+	tests := []struct {
+		name     string
+		selector lifetime.Labels
+		exp      bool
+	}{
+		{name: "empty selector", selector: nil, exp: true},
+		{name: "single match", selector: lifetime.Labels{"team": "data"}, exp: true},
+		{name: "full match", selector: lifetime.Labels{"team": "data", "tier": "worker"}, exp: true},
+		{name: "value mismatch", selector: lifetime.Labels{"team": "web"}, exp: false},
+		{name: "missing key", selector: lifetime.Labels{"region": "eu"}, exp: false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := labels.Matches(tc.selector); got != tc.exp {
+				t.Errorf("expected %v, got %v", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestLabels_String(t *testing.T) {
+	got := lifetime.Labels{"tier": "worker", "team": "data"}.String()
+	exp := "team=data,tier=worker"
+	if got != exp {
+		t.Errorf("expected %q, got %q", exp, got)
+	}
+}
+
+func TestLifetime_StopMatching(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	ingest := newBlockingService()
+	api := newBlockingService()
+
+	lt.Start(ingest, lifetime.WithLabels(lifetime.Labels{"group": "ingest"}))
+	lt.Start(api, lifetime.WithLabels(lifetime.Labels{"group": "api"}))
+
+	lt.StopMatching(lifetime.Labels{"group": "ingest"})
+
+	if !ingest.stopped {
+		t.Errorf("expected ingest service to be stopped")
+	}
+	if api.stopped {
+		t.Errorf("expected api service to still be running")
+	}
+
+	lt.Shutdown()
+	lt.Wait()
+
+	if !api.stopped {
+		t.Errorf("expected api service to be stopped")
+	}
+}
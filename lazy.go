@@ -0,0 +1,69 @@
+package lifetime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrShuttingDown is returned by Ensure when a lazy service would be started during a shutdown.
+	ErrShuttingDown = errors.New("lifetime is shutting down")
+	// ErrServiceExited is returned by Ensure when the service exited before it was ready.
+	ErrServiceExited = errors.New("service exited before it was ready")
+)
+
+// Lazy registers a service that is only started the first time Ensure is called on the returned handle,
+// which suits expensive subsystems that aren't always needed. Once started the service is treated like
+// any other, and it is stopped during a shutdown. A service that is never started is never stopped.
+func (lifetime *Lifetime) Lazy(svc Service, opts ...StartOption) *ServiceHandle {
+	return &ServiceHandle{
+		lifetime: lifetime,
+		svc:      svc,
+		opts:     opts,
+	}
+}
+
+// Ensure starts the service if it has not been started yet, such as a service registered with Lazy, or if it
+// was stopped because it was idle, and blocks until the service is ready or the given context is done.
+// It records that the service has been used. See WithIdleStop.
+// ErrShuttingDown is returned if the service has not been started and the application is shutting down,
+// ErrServiceAlreadyStarted is returned if the same service has already been started by another handle,
+// and ErrServiceExited is returned if the service exits before it is ready.
+func (handle *ServiceHandle) Ensure(ctx context.Context) error {
+	handle.Touch()
+	handle.ensureMu.Lock()
+	managed := handle.current()
+	if managed == nil || handle.idleStopped {
+		if handle.lifetime.ctx.Err() != nil {
+			handle.ensureMu.Unlock()
+			return ErrShuttingDown
+		}
+		handle.idleStopped = false
+		if handle.lifetime.start(handle) != handle {
+			handle.ensureMu.Unlock()
+			return ErrServiceAlreadyStarted
+		}
+		managed = handle.current()
+	}
+	handle.ensureMu.Unlock()
+
+	select {
+	case <-managed.ready:
+		return nil
+	default:
+	}
+	select {
+	case <-managed.ready:
+		return nil
+	case <-managed.exited:
+		select {
+		case <-managed.ready:
+			return nil
+		default:
+		}
+		return fmt.Errorf("%s: %w", managed.name, ErrServiceExited)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
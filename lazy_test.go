@@ -0,0 +1,104 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLifetime_Lazy(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	var starts int32
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceStarted {
+			atomic.AddInt32(&starts, 1)
+		}
+	})
+
+	svc := newBlockingService()
+	handle := lt.Lazy(svc, lifetime.WithName("search"))
+	unused := lt.Lazy(newBlockingService(), lifetime.WithName("reports"))
+	if got := atomic.LoadInt32(&starts); got != 0 {
+		t.Fatalf("expected lazy services not to be started, got %d starts", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := handle.Ensure(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&starts); got != 1 {
+		t.Errorf("expected the service to be started once, got %d", got)
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !svc.stopped {
+		t.Errorf("expected the started service to be stopped")
+	}
+	if err := unused.Ensure(context.Background()); !errors.Is(err, lifetime.ErrShuttingDown) {
+		t.Errorf("expected ErrShuttingDown, got %v", err)
+	}
+}
+
+func TestServiceHandle_Ensure_Exited(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	handle := lt.Lazy(&failingService{err: errors.New("no license")}, lifetime.NonCritical())
+	if err := handle.Ensure(context.Background()); !errors.Is(err, lifetime.ErrServiceExited) {
+		t.Errorf("expected ErrServiceExited, got %v", err)
+	}
+	lt.Shutdown()
+	_ = lt.Wait()
+}
+
+func TestServiceHandle_Ensure_AlreadyStarted(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(func(err error) {})
+
+	svc := newBlockingService()
+	lt.Start(svc)
+	if err := lt.Lazy(svc).Ensure(context.Background()); !errors.Is(err, lifetime.ErrServiceAlreadyStarted) {
+		t.Errorf("expected ErrServiceAlreadyStarted, got %v", err)
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLifetime_Lazy_NotStarted(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	handle := lt.Lazy(newBlockingService(), lifetime.WithName("reports"), lifetime.WithLabels(lifetime.Labels{"team": "data"}))
+	if handle.Name() != "reports" {
+		t.Errorf("expected name reports, got %s", handle.Name())
+	}
+	if handle.Labels()["team"] != "data" {
+		t.Errorf("expected the labels to be set, got %v", handle.Labels())
+	}
+	if handle.Metadata() != nil {
+		t.Errorf("expected no metadata, got %v", handle.Metadata())
+	}
+	if handle.Context().Err() != nil {
+		t.Errorf("expected the context not to be done")
+	}
+	select {
+	case <-handle.Done():
+		t.Errorf("expected the service not to be done before the shutdown")
+	default:
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-handle.Done()
+	if handle.Context().Err() == nil {
+		t.Errorf("expected the context to be done after the shutdown")
+	}
+}
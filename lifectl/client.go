@@ -0,0 +1,155 @@
+// Package lifectl is a client for the control socket and probe handler of applications that use lifetime,
+// so that scripts and operators can query the status of running instances and trigger shutdowns.
+package lifectl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/tomwright/lifetime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client sends commands to the control socket of a running application. See lifetime.ControlService.
+// It is safe to use from multiple go routines, with commands being sent one at a time.
+type Client struct {
+	mu      sync.Mutex
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+// Dial connects to the control socket at the given path.
+func Dial(ctx context.Context, path string) (*Client, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to control socket: %w", err)
+	}
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return &Client{conn: conn, scanner: scanner}, nil
+}
+
+// Close closes the connection to the control socket.
+func (client *Client) Close() error {
+	return client.conn.Close()
+}
+
+// Status returns the status of the application.
+func (client *Client) Status(ctx context.Context) (lifetime.Status, error) {
+	response, err := client.Send(ctx, lifetime.ControlRequest{Command: lifetime.ControlStatus})
+	if err != nil {
+		return lifetime.Status{}, err
+	}
+	if response.Status == nil {
+		return lifetime.Status{}, errors.New("status missing from response")
+	}
+	return *response.Status, nil
+}
+
+// Shutdown triggers a graceful shutdown of the application, draining its services.
+// It returns once the shutdown has been triggered, rather than once it has completed.
+func (client *Client) Shutdown(ctx context.Context) error {
+	_, err := client.Send(ctx, lifetime.ControlRequest{Command: lifetime.ControlShutdown})
+	return err
+}
+
+// Restart restarts the running service with the given name.
+func (client *Client) Restart(ctx context.Context, service string) error {
+	_, err := client.Send(ctx, lifetime.ControlRequest{Command: lifetime.ControlRestart, Service: service})
+	return err
+}
+
+// SetLogLevel changes the log level of the application to one of debug, info, warn or error.
+func (client *Client) SetLogLevel(ctx context.Context, level string) error {
+	_, err := client.Send(ctx, lifetime.ControlRequest{Command: lifetime.ControlSetLogLevel, Level: level})
+	return err
+}
+
+// Send sends the given request and returns the response.
+// An error is returned if the request could not be sent, or if the application could not execute it.
+func (client *Client) Send(ctx context.Context, request lifetime.ControlRequest) (lifetime.ControlResponse, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Time{}
+	}
+	if err := client.conn.SetDeadline(deadline); err != nil {
+		return lifetime.ControlResponse{}, fmt.Errorf("could not set deadline: %w", err)
+	}
+
+	if err := json.NewEncoder(client.conn).Encode(request); err != nil {
+		return lifetime.ControlResponse{}, fmt.Errorf("could not send request: %w", err)
+	}
+	if !client.scanner.Scan() {
+		err := client.scanner.Err()
+		if err == nil {
+			err = errors.New("connection closed")
+		}
+		return lifetime.ControlResponse{}, fmt.Errorf("could not read response: %w", err)
+	}
+	var response lifetime.ControlResponse
+	if err := json.Unmarshal(client.scanner.Bytes(), &response); err != nil {
+		return lifetime.ControlResponse{}, fmt.Errorf("could not decode response: %w", err)
+	}
+	if !response.OK {
+		return response, &CommandError{Command: request.Command, Message: response.Error}
+	}
+	return response, nil
+}
+
+// CommandError is returned when the application could not execute a command.
+type CommandError struct {
+	// Command is the command that was sent.
+	Command lifetime.ControlCommand
+	// Message is the error message returned by the application.
+	Message string
+}
+
+// Error returns the error message.
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Command, e.Message)
+}
+
+// FetchStatus returns the status of the application served by the probe handler at the given base URL,
+// such as http://10.0.0.1:8081. See lifetime.ProbeHandler.
+func FetchStatus(ctx context.Context, baseURL string) (lifetime.Status, error) {
+	var status lifetime.Status
+	err := fetch(ctx, baseURL, "/status", &status)
+	return status, err
+}
+
+// FetchBuildInfo returns the build report of the application served by the probe handler at the given base URL.
+func FetchBuildInfo(ctx context.Context, baseURL string) (lifetime.BuildReport, error) {
+	var report lifetime.BuildReport
+	err := fetch(ctx, baseURL, "/buildinfo", &report)
+	return report, err
+}
+
+// fetch decodes the JSON response of the given path of the probe handler into v.
+func fetch(ctx context.Context, baseURL string, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURL, "/")+path, nil)
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not fetch %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not fetch %s: unexpected status code %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("could not decode %s: %w", path, err)
+	}
+	return nil
+}
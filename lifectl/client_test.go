@@ -0,0 +1,101 @@
+package lifectl_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"github.com/tomwright/lifetime/lifectl"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// blockingService is a service that blocks until it is stopped.
+type blockingService struct {
+	stop chan struct{}
+}
+
+func (s *blockingService) Start() error {
+	<-s.stop
+	return nil
+}
+
+func (s *blockingService) Stop() {
+	close(s.stop)
+}
+
+func TestClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lifectl")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "control.sock")
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	listening := make(chan struct{})
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceReady && event.Service == "control" {
+			close(listening)
+		}
+	})
+	lt.Start(&blockingService{stop: make(chan struct{})}, lifetime.WithName("worker"))
+	lt.Start(lt.ControlService(path), lifetime.WithName("control"))
+	<-listening
+
+	ctx := context.Background()
+	client, err := lifectl.Dial(ctx, path)
+	if err != nil {
+		t.Fatalf("could not dial: %v", err)
+	}
+	defer client.Close()
+
+	status, err := client.Status(ctx)
+	if err != nil {
+		t.Fatalf("could not get status: %v", err)
+	}
+	if len(status.Services) != 2 || status.Services[1].Name != "worker" {
+		t.Errorf("unexpected status: %+v", status.Services)
+	}
+
+	if err := client.SetLogLevel(ctx, "debug"); err != nil {
+		t.Errorf("could not set log level: %v", err)
+	}
+	var commandErr *lifectl.CommandError
+	if err := client.SetLogLevel(ctx, "loud"); !errors.As(err, &commandErr) {
+		t.Errorf("expected a CommandError for an invalid level, got %v", err)
+	}
+
+	if err := client.Shutdown(ctx); err != nil {
+		t.Errorf("could not shutdown: %v", err)
+	}
+	_ = lt.Wait()
+}
+
+func TestFetchStatus(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(&blockingService{stop: make(chan struct{})}, lifetime.WithName("worker"))
+	server := httptest.NewServer(lt.ProbeHandler())
+	defer server.Close()
+
+	status, err := lifectl.FetchStatus(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("could not fetch status: %v", err)
+	}
+	if len(status.Services) != 1 || status.Services[0].Name != "worker" {
+		t.Errorf("unexpected status: %+v", status.Services)
+	}
+
+	report, err := lifectl.FetchBuildInfo(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("could not fetch build info: %v", err)
+	}
+	if report.Build.GoVersion == "" {
+		t.Errorf("expected the go version to be set")
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+}
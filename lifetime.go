@@ -3,11 +3,12 @@ package lifetime
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
 	"os"
-	"os/signal"
+	"reflect"
 	"sync"
 	"syscall"
+	"time"
 )
 
 var (
@@ -18,32 +19,297 @@ var (
 	// ErrImmediateShutdownSignalReceived is used when a shutdown signal is received for the second time.
 	// It will cause an immediate shutdown.
 	ErrImmediateShutdownSignalReceived = errors.New("immediate shutdown signal received")
+
+	// ErrServiceAlreadyStarted is used when a service is started while it is already running.
+	// The second start is ignored.
+	ErrServiceAlreadyStarted = errors.New("service already started")
 )
 
 // New returns a new Lifetime instance that can be used to control
 // the lifetime of an application.
-func New(ctx context.Context) *Lifetime {
+func New(ctx context.Context, opts ...Option) *Lifetime {
 	ctx, cancel := context.WithCancel(ctx)
-	return &Lifetime{
+	lifetime := &Lifetime{
+		created:    time.Now(),
 		ctx:        ctx,
 		cancelFunc: cancel,
-		serviceWg:  &sync.WaitGroup{},
-		errCh:      make(chan error),
+		serviceWg:  newActivity(),
+		errors:     newErrorQueue(defaultErrorQueueSize),
+		logger:     NewStdLogger(nil),
+		logLevel:   int32(LevelInfo),
+		exit:       os.Exit,
+		immediate:  make(chan struct{}),
+		services:   map[*managedService]struct{}{},
+		byService:  map[Service]*managedService{},
+		groups:     map[string]*Group{},
+		barriers:   map[string]*Barrier{},
+		providers:  map[reflect.Type]*provider{},
+		stages:     map[Stage][]stageFunc{},
+		overloaded: map[string]int{},
+		shed:       map[*managedService]struct{}{},
+		ready:      make(chan struct{}),
+		signals:    defaultSignals,
+
+		signalEscalation: defaultSignalEscalation,
+		signalSource:     osSignals{},
+		history:          newHistory(defaultHistorySize),
+		errorRetryDelay:  defaultErrorRetryDelay,
+		degraded:         map[string]error{},
+		timings:          newTimingsRecorder(),
+
+		shutdownTimedOut: make(chan struct{}),
+		shutdownComplete: make(chan struct{}),
+
+		stopWarningThreshold: defaultStopWarningThreshold,
+	}
+	for _, opt := range opts {
+		opt(lifetime)
+	}
+	if lifetime.chaos != nil {
+		lifetime.chaos.start()
+	}
+	if lifetime.markers != nil {
+		lifetime.markers.start()
+	}
+	lifetime.timings.start(lifetime)
+	if lifetime.startupTimeout > 0 {
+		go lifetime.watchStartup()
+	}
+	if lifetime.rollback != nil {
+		lifetime.rollback.start(lifetime)
 	}
+	go lifetime.stopOnShutdown()
+	return lifetime
 }
 
 // Lifetime contains some basic functionality you can use to control the lifetime of an application.
+// Every method of a Lifetime is safe to call concurrently from any go routine, including Start, Shutdown,
+// Wait and ReportError, and services can be started while Wait is blocking.
 type Lifetime struct {
+	// created is the time the lifetime was created.
+	created    time.Time
 	ctx        context.Context
 	cancelFunc context.CancelFunc
-	serviceWg  *sync.WaitGroup
-	errCh      chan error
+	serviceWg  *activity
+	errors     *errorQueue
+	logger     Logger
+	logLevel   int32
+	exit       func(code int)
+	// signalSource relays the signals that are listened for. See WithSignalSource.
+	signalSource SignalSource
+
+	causeMu  sync.Mutex
+	cause    Cause
+	causeErr error
+	// causeDuringStartup is true if the shutdown was triggered before the lifetime was ready.
+	causeDuringStartup bool
+	// startupErr is returned by Wait if the startup failed, such as by exceeding the startup timeout.
+	startupErr error
+	// partialStartup is true if NonCritical services that fail are recorded as degraded.
+	partialStartup bool
+	// panicPolicy is what happens when a service panics. See WithPanicPolicy.
+	panicPolicy PanicPolicy
+	// strictStartup is true if any failure during the startup phase fails the startup.
+	strictStartup bool
+	// rollback records a failed startup, if WithStartupRollback is used.
+	rollback *startupRollback
+	// startupTimeout is how long the lifetime has to become ready, if greater than 0.
+	startupTimeout time.Duration
+
+	startupOnce sync.Once
+	// ready is closed once the startup phase has completed.
+	ready chan struct{}
+
+	stopWarningThreshold time.Duration
+	stopWarningStackDump bool
+
+	// signals are the signals that are listened for when no signals are given to HandleSignals.
+	signals []os.Signal
+	// signalEscalation is the number of shutdown signals that cause an immediate shutdown.
+	signalEscalation int
+
+	shutdownTimeout time.Duration
+	drainDelay      time.Duration
+
+	shutdownDeadlineMu sync.Mutex
+	// shutdownDeadline is the time by which the shutdown must complete, once a shutdown with a timeout has started.
+	shutdownDeadline time.Time
+
+	// stopBudget is true if the time before the shutdown timeout is divided among services.
+	stopBudget bool
+	// diagnosticsFile is the file a diagnostic bundle is written to on an immediate shutdown, if any.
+	diagnosticsFile string
+	// profileDir is the directory profiles are written to when a shutdown fails, if any.
+	profileDir string
+	// runtimeSnapshots is true if snapshots of the runtime are attached to shutdown events.
+	runtimeSnapshots bool
+	// disabled contains the names of the services that are not executed when started.
+	disabled map[string]bool
+	// stopConcurrency is the maximum number of services that are stopped at the same time, if greater than 0.
+	stopConcurrency int
+	// reverseStopOrder is true if services are stopped in the reverse of the order they were started in.
+	reverseStopOrder bool
+	// shutdownTimedOut is closed if the shutdown does not complete within the shutdown timeout.
+	shutdownTimedOut chan struct{}
+	// shutdownComplete is closed once a shutdown has completed.
+	shutdownComplete chan struct{}
+
+	shutdownIDMu sync.RWMutex
+	// shutdownID is the correlation ID of the shutdown, once one has begun.
+	shutdownID string
+
+	// chaos is used to inject faults when chaos mode is enabled.
+	chaos *chaos
+	// deterministic is used to order operations when deterministic mode is enabled.
+	deterministic *deterministic
+
+	// immediateExitCode is the code the application exits with on an immediate shutdown, if set.
+	immediateExitCode int
+	// immediateExitCodeSet is true once WithImmediateShutdownExitCode has been used, so that 0 can be set.
+	immediateExitCodeSet bool
+	// immediateReturn is true if an immediate shutdown should unblock Wait rather than exit.
+	immediateReturn bool
+	immediateOnce   sync.Once
+	// immediate is closed when an immediate shutdown is triggered.
+	immediate chan struct{}
+
+	handlingErrorsMu sync.Mutex
+	// handlingErrors is true once HandleErrors has been called with the default handler.
+	handlingErrors bool
+
+	droppedErrorsMu sync.Mutex
+	droppedErrors   uint64
+
+	failuresMu sync.Mutex
+	// failures are the errors that occurred, in the order they occurred. See WaitErr.
+	failures []error
+
+	servicesMu sync.Mutex
+	services   map[*managedService]struct{}
+	// handles are the handles of the services that have been started, in the order they were first started.
+	handles []*ServiceHandle
+	// byService contains the running services that can be used as map keys.
+	// It is used to detect services that are started more than once.
+	byService map[Service]*managedService
+	// stopping is true once the lifetime has begun stopping all services.
+	stopping bool
+	// nextSeq is the sequence number given to the next service that is started.
+	nextSeq uint64
+
+	registrationsMu sync.Mutex
+	// registrations are the services that are waiting to be started by RunProfile.
+	registrations []registration
+
+	groupsMu sync.Mutex
+	groups   map[string]*Group
+
+	reconcileMu sync.Mutex
+	// reconciled are the handles of the services started by Reconcile, by name.
+	reconciled map[string]*ServiceHandle
+
+	gatesMu sync.Mutex
+	gates   []*readinessGate
+
+	warmersMu sync.Mutex
+	warmers   []*warmer
+	// warmersStarted is true once the warmers have been started, after which new warmers run straight away.
+	warmersStarted bool
+
+	barriersMu sync.Mutex
+	barriers   map[string]*Barrier
+
+	providersMu sync.Mutex
+	providers   map[reflect.Type]*provider
+	// provided are the providers that have built their dependency, in the order they were built.
+	provided []*provider
+
+	// beforeStart are executed before any service is started. See OnBeforeStart.
+	beforeStart startHooks
+	// afterStart are executed once the lifetime is ready. See OnAfterStart.
+	afterStart startHooks
+	// shutdownHooks are executed when a shutdown begins. See OnShutdown.
+	shutdownHooks hooks
+	// shutdownCompleteHooks are executed once a shutdown has completed. See OnShutdownComplete.
+	shutdownCompleteHooks hooks
+
+	stagesMu sync.Mutex
+	stages   map[Stage][]stageFunc
+	// stagesPending is true if Wait must wait for the shutdown stages to be executed.
+	stagesPending bool
+	// stagesDone is true once the shutdown stages have been executed.
+	stagesDone bool
+
+	// history records every event that is emitted.
+	history *history
+	// timings records how long the lifetime takes to start up and shut down.
+	timings *timingsRecorder
+
+	// markers maintains the ready and terminating files, if any are configured.
+	markers *markerFiles
+
+	errorPoliciesMu sync.Mutex
+	errorPolicies   []ErrorClassifier
+	// errorRetryDelay is how long to wait before starting a service that failed with a retryable error.
+	errorRetryDelay time.Duration
+
+	degradedMu sync.Mutex
+	// degraded holds the error of each service that failed with an error classified as ActionDegrade.
+	degraded map[string]error
+
+	backpressureMu sync.Mutex
+	// overloaded holds the priority of each source that is reporting backpressure. See ReportBackpressure.
+	overloaded map[string]int
+	// shed contains the services that were paused to shed load. See WithLoadShedding.
+	shed map[*managedService]struct{}
+	// loadShedding is true if services are paused while a source of a higher priority is overloaded.
+	loadShedding bool
+
+	logReopenersMu sync.Mutex
+	logReopeners   []logReopener
+
+	eventHandlersMu sync.RWMutex
+	eventHandlers   []EventHandler
 }
 
+// ErrorHandler is used to handle errors that are received by the lifetime.
+type ErrorHandler func(err error)
+
+// defaultSignals are the signals that are listened for when no signals are given to HandleSignals.
+var defaultSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL}
+
 // Init starts up the required routines for the lifetime instance to work as expected.
+// It is the equivalent of calling HandleErrors(nil) and HandleSignals().
 func (lifetime *Lifetime) Init() *Lifetime {
-	lifetime.handleErrors()
-	lifetime.handleShutdownSignals()
+	return lifetime.
+		HandleErrors(nil).
+		HandleSignals()
+}
+
+// HandleSignals starts a go routine that listens for the given signals and sends
+// an ErrShutdownSignalReceived to the error handler when one is received.
+// If one of the signals is received for a second time an ErrImmediateShutdownSignalReceived is sent instead.
+// See WithSignalEscalation.
+// If no signals are given the signals set by WithSignals are used, which default to SIGINT, SIGTERM and SIGKILL.
+func (lifetime *Lifetime) HandleSignals(sigs ...os.Signal) *Lifetime {
+	if len(sigs) == 0 {
+		sigs = lifetime.signals
+	}
+	lifetime.handleShutdownSignals(sigs)
+	return lifetime
+}
+
+// HandleErrors starts a go routine that passes any errors received by the lifetime to the given handler.
+// If handler is nil the default handler is used, which logs the error and triggers a graceful shutdown,
+// or exits immediately when an ErrImmediateShutdownSignalReceived is received.
+// A custom handler is responsible for triggering a shutdown if it wants one to happen.
+func (lifetime *Lifetime) HandleErrors(handler ErrorHandler) *Lifetime {
+	if handler == nil {
+		handler = lifetime.defaultErrorHandler
+		lifetime.handlingErrorsMu.Lock()
+		lifetime.handlingErrors = true
+		lifetime.handlingErrorsMu.Unlock()
+	}
+	lifetime.handleErrors(handler)
 	return lifetime
 }
 
@@ -60,91 +326,433 @@ func (lifetime *Lifetime) Done() <-chan struct{} {
 }
 
 // Shutdown triggers a graceful shutdown of the application.
+// The shutdown cause is recorded as CauseManual if no other cause has been recorded.
 func (lifetime *Lifetime) Shutdown() {
-	lifetime.cancelFunc()
+	lifetime.shutdownWith(CauseManual, nil)
 }
 
 // Wait will block until all services registered with the Lifetime have finished execution.
-func (lifetime *Lifetime) Wait() {
-	lifetime.serviceWg.Wait()
+// If WithImmediateShutdownReturn is used, Wait will return ErrImmediateShutdownSignalReceived
+// as soon as an immediate shutdown is triggered, without waiting for services to finish.
+// If WithShutdownTimeout is used, Wait will return ErrShutdownTimeout if the shutdown does not complete in time.
+// If WithStartupTimeout is used, Wait will return a StartupTimeoutError if the startup was aborted.
+// If WithStartupRollback is used, Wait will return a StartupReport if a service failed during the startup phase.
+// If errors are handled by the default handler, Wait also waits for every reported error to be handled.
+// Calling Wait also marks the end of service registration for the startup phase. See Ready.
+// Wait can be called from multiple go routines and more than once. A service that is started while Wait is
+// blocking is waited for, as long as it is started before Wait has returned.
+func (lifetime *Lifetime) Wait() error {
+	lifetime.completeStartup()
+
+	done := make(chan struct{})
+	go func() {
+		lifetime.serviceWg.Wait()
+		// Handling an error, such as one returned by the last service, may trigger a shutdown
+		// so the errors must be handled before checking whether there is a shutdown to wait for.
+		lifetime.waitForErrors()
+		if lifetime.ctx.Err() != nil {
+			<-lifetime.shutdownComplete
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return lifetime.startupError()
+	case <-lifetime.immediate:
+		return ErrImmediateShutdownSignalReceived
+	case <-lifetime.shutdownTimedOut:
+		select {
+		case <-done:
+			// The shutdown completed just as the timeout was reached.
+			return lifetime.startupError()
+		default:
+		}
+		return ErrShutdownTimeout
+	}
 }
 
 // Start will start the given service.
 // It also ensures that the service wait group is updated as expected.
-func (lifetime *Lifetime) Start(svc Service) {
+// The returned handle can be used to control the service individually.
+// If the same service is already running the call is ignored, an ErrServiceAlreadyStarted
+// error is reported and the handle of the running service is returned.
+func (lifetime *Lifetime) Start(svc Service, opts ...StartOption) *ServiceHandle {
+	return lifetime.start(&ServiceHandle{
+		lifetime: lifetime,
+		svc:      svc,
+		opts:     opts,
+	})
+}
+
+// start starts a new instance of the service described by the given handle.
+func (lifetime *Lifetime) start(handle *ServiceHandle) *ServiceHandle {
+	return lifetime.launch(lifetime.newInstance(handle, handle.Service()))
+}
+
+// newInstance returns a new instance of the given service, configured by the options of the given handle.
+func (lifetime *Lifetime) newInstance(handle *ServiceHandle, svc Service) *managedService {
+	managed := &managedService{
+		lifetime: lifetime,
+		handle:   handle,
+		svc:      svc,
+		done:     make(chan struct{}),
+		exited:   make(chan struct{}),
+		ready:    make(chan struct{}),
+	}
+	if lifetime.deterministic != nil {
+		managed.started = make(chan struct{})
+	}
+	for _, opt := range handle.opts {
+		opt(managed)
+	}
+	if managed.name == "" {
+		managed.name = fmt.Sprintf("%T", svc)
+	}
+	if classifier, ok := svc.(Classifier); ok && managed.class == ClassUnspecified {
+		managed.class = classifier.Class()
+	}
+	if criticality, ok := svc.(Criticality); ok && !criticality.Critical() {
+		managed.nonCritical = true
+	}
+	managed.resolveMetadata()
+	managed.waiting = len(managed.dependencies) > 0
+	return managed
+}
+
+// launch executes the given instance of a service and returns its handle.
+// If the same service is already running, the handle of the running service is returned instead.
+func (lifetime *Lifetime) launch(managed *managedService) *ServiceHandle {
+	handle := managed.handle
+	if lifetime.disabled[managed.name] {
+		lifetime.register(handle)
+		managed.skip()
+		return handle
+	}
+	if lifetime.runBeforeStart() != nil {
+		lifetime.register(handle)
+		managed.abandon()
+		return handle
+	}
+	comparable := reflect.TypeOf(managed.svc).Comparable()
+
+	lifetime.servicesMu.Lock()
+	if comparable {
+		if existing, ok := lifetime.byService[managed.svc]; ok {
+			lifetime.servicesMu.Unlock()
+			lifetime.reportError(managed.wrapError(ErrServiceAlreadyStarted))
+			return existing.handle
+		}
+		lifetime.byService[managed.svc] = managed
+		managed.comparable = true
+	}
+	lifetime.registerLocked(handle)
+	ctx := lifetime.ctx
+	for _, v := range managed.values {
+		ctx = context.WithValue(ctx, v.key, v.value)
+	}
+	managed.ctx, managed.cancelFunc = context.WithCancel(ctx)
+	managed.seq = lifetime.nextSeq
+	lifetime.nextSeq++
+	if lifetime.rollback != nil {
+		lifetime.rollback.recordStarted(lifetime, managed.name)
+	}
+	if !managed.replacing {
+		handle.setManaged(managed)
+	}
 	lifetime.serviceWg.Add(1)
-	go lifetime.start(svc)
+	lifetime.services[managed] = struct{}{}
+	stopping := lifetime.stopping
+	lifetime.servicesMu.Unlock()
+
+	managed.restore()
+	go managed.run()
+	if managed.idleTimeout > 0 {
+		handle.Touch()
+		go managed.watchIdle()
+	}
+	if managed.started != nil {
+		<-managed.started
+	}
+
+	if lifetime.chaos != nil {
+		lifetime.chaos.serviceStarted(managed)
+	}
+	if stopping {
+		// The application is already shutting down so the service should be stopped straight away.
+		go managed.stop()
+	}
+	return handle
 }
 
-// start executes a service in a go routine.
-// It ensures that the service wait group is updated, and that the service Stop func is
-// executed when an application shutdown is triggered.
-func (lifetime *Lifetime) start(svc Service) {
-	defer lifetime.serviceWg.Done()
+// StopMatching stops all running services that have labels matching the given selector.
+// It blocks until the matching services have finished execution.
+// The rest of the application is unaffected.
+func (lifetime *Lifetime) StopMatching(selector Labels) {
+	_ = lifetime.stopWhere(context.Background(), func(managed *managedService) bool {
+		return managed.labels.Matches(selector)
+	})
+}
 
-	startErrs := make(chan error)
-	startWg := &sync.WaitGroup{}
+// stopWhere stops all running services for which match returns true.
+// It blocks until the services have finished execution or the given context is done.
+func (lifetime *Lifetime) stopWhere(ctx context.Context, match func(managed *managedService) bool) error {
+	lifetime.servicesMu.Lock()
+	matching := make([]*managedService, 0)
+	for managed := range lifetime.services {
+		if match(managed) {
+			matching = append(matching, managed)
+		}
+	}
+	lifetime.servicesMu.Unlock()
 
-	startWg.Add(1)
-	go func() {
-		defer startWg.Done()
-		err := svc.Start()
-		if err != nil {
-			startErrs <- err
+	for _, managed := range matching {
+		go managed.stop()
+	}
+	for _, managed := range matching {
+		select {
+		case <-managed.exited:
+		case <-ctx.Done():
+			return ctx.Err()
 		}
-	}()
+	}
+	return nil
+}
 
-	select {
-	case startErr := <-startErrs:
-		// Something went wrong during start-up.
-		// Report the error.
-		lifetime.errCh <- startErr
-	case <-lifetime.ctx.Done():
-		// The application wants us to shutdown.
-		// Stop the service and wait for the start func to finish.
-		svc.Stop()
-		startWg.Wait()
+// stopOnShutdown waits for the lifetime context to be done and then runs the shutdown stages,
+// the first of which stops every running service.
+// This is the only go routine the lifetime needs in order to manage shutdowns, regardless
+// of the number of services that have been started.
+func (lifetime *Lifetime) stopOnShutdown() {
+	<-lifetime.ctx.Done()
+	shutdownStarted := time.Now()
+
+	// If nothing else recorded a cause, the parent context must have been done.
+	lifetime.setCause(CauseParentContext, lifetime.ctx.Err())
+	cause, causeErr := lifetime.Cause()
+	lifetime.startShutdownID()
+	if cause == CauseServiceError {
+		lifetime.writeProfiles("service-error")
+	}
+	lifetime.emit(Event{Type: EventShutdownStarted, Cause: cause, Err: causeErr, Runtime: lifetime.runtimeSnapshot()})
+
+	lifetime.servicesMu.Lock()
+	lifetime.stopping = true
+	running := make([]*managedService, 0, len(lifetime.services))
+	for managed := range lifetime.services {
+		running = append(running, managed)
+	}
+	lifetime.servicesMu.Unlock()
+
+	lifetime.log(LevelInfo, "lifetime shutdown started",
+		Field{Key: "cause", Value: cause.String()},
+		Field{Key: "services", Value: len(running)},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopWatching := func() {}
+	var budget *stopBudget
+	if lifetime.shutdownTimeout > 0 {
+		deadline := shutdownStarted.Add(lifetime.shutdownTimeout)
+		lifetime.setShutdownDeadline(deadline)
+		if lifetime.stopBudget {
+			budget = newStopBudget(deadline, running)
+		}
+		ctx, cancel = context.WithDeadline(context.Background(), deadline)
+		stopWatching = lifetime.watchShutdown(deadline)
 	}
+	defer cancel()
+
+	lifetime.shutdownHooks.run()
+	lifetime.waitDrainDelay()
+
+	lifetime.runStages(ctx, func() {
+		lifetime.quiesce(ctx, running)
+		for _, step := range lifetime.stopSteps(running, true) {
+			if budget != nil {
+				for i, deadline := range budget.allocate(step) {
+					go step[i].stopWithin(ctx, deadline)
+				}
+			} else {
+				for _, managed := range step {
+					go managed.stopContext(ctx)
+				}
+			}
+			for _, managed := range step {
+				<-managed.exited
+			}
+		}
+	})
+
+	stopWatching()
+	lifetime.emit(Event{
+		Type:     EventShutdownComplete,
+		Duration: time.Since(shutdownStarted),
+		Runtime:  lifetime.runtimeSnapshot(),
+	})
+	lifetime.shutdownCompleteHooks.run()
+	lifetime.log(LevelInfo, "lifetime shutdown complete")
+	close(lifetime.shutdownComplete)
+}
+
+// stopSteps returns the order in which the given services are stopped during a shutdown.
+// Services in the same step are stopped concurrently, and each step starts once the previous one has completed.
+// Services are stopped in the order of their class, see ServiceClass.
+// In deterministic mode each service has its own step, in an order chosen by the seed if shuffle is true
+// or in the order the services were started otherwise.
+// Steps are split so that they contain no more services than the stop concurrency.
+// Services are always stopped before the services they depend on, see After.
+// With WithReverseStopOrder each service has its own step, in the reverse of the order the services were started.
+// If a failed startup is being rolled back, see WithStartupRollback, each service has its own step in the
+// reverse of the order the services were started.
+func (lifetime *Lifetime) stopSteps(running []*managedService, shuffle bool) [][]*managedService {
+	if lifetime.rollingBack() {
+		return dependencySteps(rollbackSteps(running))
+	}
+	steps := dependencySteps(cascadeSteps(classSteps(running)))
+	if lifetime.reverseStopOrder {
+		return reverseSteps(steps)
+	}
+	if lifetime.deterministic == nil {
+		return limitSteps(steps, lifetime.stopConcurrency)
+	}
+	ordered := make([][]*managedService, 0, len(running))
+	for _, step := range steps {
+		if shuffle {
+			step = lifetime.deterministic.shuffleServices(step)
+		} else {
+			step = append([]*managedService(nil), step...)
+			sortBySeq(step)
+		}
+		for _, managed := range step {
+			ordered = append(ordered, []*managedService{managed})
+		}
+	}
+	return ordered
+}
+
+// limitSteps splits each of the given steps into steps that contain no more than limit services.
+func limitSteps(steps [][]*managedService, limit int) [][]*managedService {
+	if limit <= 0 {
+		return steps
+	}
+	limited := make([][]*managedService, 0, len(steps))
+	for _, step := range steps {
+		for len(step) > limit {
+			limited = append(limited, step[:limit])
+			step = step[limit:]
+		}
+		if len(step) > 0 {
+			limited = append(limited, step)
+		}
+	}
+	return limited
+}
+
+// remove removes the given service from the set of running services.
+func (lifetime *Lifetime) remove(managed *managedService) {
+	lifetime.servicesMu.Lock()
+	delete(lifetime.services, managed)
+	if managed.comparable && lifetime.byService[managed.svc] == managed {
+		delete(lifetime.byService, managed.svc)
+	}
+	lifetime.servicesMu.Unlock()
 }
 
 // handleShutdownSignals runs a go routine that listens for shutdown signals from the os
 // and sends an ErrShutdownSignalReceived to the error chan when the application is told to shutdown.
-func (lifetime *Lifetime) handleShutdownSignals() {
+func (lifetime *Lifetime) handleShutdownSignals(sigs []os.Signal) {
 	signals := make(chan os.Signal, 1)
 
-	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL)
+	lifetime.signalSource.Notify(signals, sigs...)
 
 	go func() {
 		count := 0
 		for {
 			sig := <-signals
 			count++
-			if count > 1 || sig == syscall.SIGKILL {
-				lifetime.errCh <- ErrImmediateShutdownSignalReceived
+			escalate := lifetime.signalEscalation > 0 && count >= lifetime.signalEscalation
+			if escalate || sig == syscall.SIGKILL {
+				lifetime.errors.pushAlways(&SignalError{Signal: sig, Err: ErrImmediateShutdownSignalReceived})
 				continue
 			}
-			lifetime.errCh <- ErrShutdownSignalReceived
+			lifetime.errors.pushAlways(&SignalError{Signal: sig, Err: ErrShutdownSignalReceived})
 		}
 	}()
 }
 
-// handleErrors starts a go routine that listens on the error channel and logs errors.
-func (lifetime *Lifetime) handleErrors() {
+// handleErrors starts a go routine that listens for reported errors and passes them to the given handler.
+func (lifetime *Lifetime) handleErrors(handler ErrorHandler) {
 	go func() {
-		for {
-			err, ok := <-lifetime.errCh
-			if !ok {
-				lifetime.cancelFunc()
-				return
+		for range lifetime.errors.notify {
+			errs, dropped := lifetime.errors.popAll()
+			if dropped > 0 {
+				lifetime.droppedErrorsMu.Lock()
+				lifetime.droppedErrors += dropped
+				lifetime.droppedErrorsMu.Unlock()
+				lifetime.log(LevelWarn, "lifetime error queue full, errors dropped", Field{Key: "dropped", Value: dropped})
 			}
-
-			if err == ErrImmediateShutdownSignalReceived {
-				os.Exit(1)
+			if lifetime.deterministic != nil {
+				lifetime.deterministic.sortErrors(errs)
 			}
-
-			log.Printf("lifetime error received: %s", err.Error())
-
-			lifetime.Shutdown()
+			for _, err := range errs {
+				handler(err)
+			}
+			lifetime.errors.handled(len(errs))
 		}
 	}()
 }
+
+// waitForErrors blocks until every reported error has been passed to the default error handler,
+// so that the shutdown cause is known by the time Wait returns.
+// It returns straight away if errors are not being handled by the default handler, as a custom handler
+// may block until after Wait has returned.
+func (lifetime *Lifetime) waitForErrors() {
+	lifetime.handlingErrorsMu.Lock()
+	handling := lifetime.handlingErrors
+	lifetime.handlingErrorsMu.Unlock()
+	if handling {
+		lifetime.errors.waitIdle()
+	}
+}
+
+// reportError queues the given error for the error handler.
+// It never blocks.
+func (lifetime *Lifetime) reportError(err error) {
+	lifetime.recordFailure(err)
+	lifetime.errors.push(err)
+}
+
+// DroppedErrors returns the number of errors that have been dropped because the error queue was full.
+func (lifetime *Lifetime) DroppedErrors() uint64 {
+	lifetime.droppedErrorsMu.Lock()
+	defer lifetime.droppedErrorsMu.Unlock()
+	return lifetime.droppedErrors
+}
+
+// immediateShutdown exits the application, or unblocks Wait if WithImmediateShutdownReturn is used.
+func (lifetime *Lifetime) immediateShutdown(err error) {
+	lifetime.writeDiagnostics(err)
+	if !lifetime.immediateReturn {
+		lifetime.exit(lifetime.immediateExit())
+		return
+	}
+	lifetime.log(LevelError, "lifetime immediate shutdown")
+	lifetime.immediateOnce.Do(func() {
+		close(lifetime.immediate)
+	})
+	lifetime.shutdownWith(CauseSignal, err)
+}
+
+// defaultErrorHandler logs the given error and triggers a graceful shutdown.
+// If the error is ErrImmediateShutdownSignalReceived the application exits immediately.
+func (lifetime *Lifetime) defaultErrorHandler(err error) {
+	if errors.Is(err, ErrImmediateShutdownSignalReceived) {
+		lifetime.immediateShutdown(err)
+		return
+	}
+
+	lifetime.log(LevelError, "lifetime error received", errorFields(err)...)
+
+	lifetime.shutdownWith(causeOf(err), err)
+}
@@ -0,0 +1,76 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"runtime"
+	"testing"
+)
+
+// noopService is a minimal service that blocks until it is stopped.
+type noopService struct {
+	stop chan struct{}
+}
+
+func (s *noopService) Start() error {
+	<-s.stop
+	return nil
+}
+
+func (s *noopService) Stop() {
+	close(s.stop)
+}
+
+// BenchmarkLifetime_Start measures the cost of starting and stopping a service.
+func BenchmarkLifetime_Start(b *testing.B) {
+	services := make([]*noopService, b.N)
+	for i := range services {
+		services[i] = &noopService{stop: make(chan struct{})}
+	}
+
+	lt := lifetime.New(context.Background())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for _, svc := range services {
+		lt.Start(svc)
+	}
+	lt.Shutdown()
+	lt.Wait()
+}
+
+// BenchmarkLifetime_StartThousands reports the number of go routines used per running service
+// when thousands of services are registered.
+func BenchmarkLifetime_StartThousands(b *testing.B) {
+	const count = 5000
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		lt := lifetime.New(context.Background())
+		before := runtime.NumGoroutine()
+
+		for i := 0; i < count; i++ {
+			lt.Start(&noopService{stop: make(chan struct{})})
+		}
+
+		b.ReportMetric(float64(runtime.NumGoroutine()-before)/count, "goroutines/service")
+
+		lt.Shutdown()
+		lt.Wait()
+	}
+}
+
+// BenchmarkLifetime_Go measures the cost of executing a short-lived job.
+func BenchmarkLifetime_Go(b *testing.B) {
+	lt := lifetime.New(context.Background())
+	job := func(ctx context.Context) error {
+		return nil
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		lt.Go(job)
+	}
+	lt.Wait()
+}
@@ -0,0 +1,26 @@
+module github.com/tomwright/lifetime/lifetimehttp3
+
+go 1.23
+
+require (
+	github.com/quic-go/quic-go v0.54.0
+	github.com/tomwright/lifetime v0.0.0
+)
+
+require (
+	github.com/golang/protobuf v1.5.0 // indirect
+	github.com/quic-go/qpack v0.5.1 // indirect
+	go.uber.org/mock v0.5.0 // indirect
+	golang.org/x/crypto v0.26.0 // indirect
+	golang.org/x/mod v0.18.0 // indirect
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.23.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
+	golang.org/x/tools v0.22.0 // indirect
+	google.golang.org/genproto v0.0.0-20200815001618-f69a88009b70 // indirect
+	google.golang.org/grpc v1.31.0 // indirect
+	google.golang.org/protobuf v1.33.0 // indirect
+)
+
+replace github.com/tomwright/lifetime => ../
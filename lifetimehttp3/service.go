@@ -0,0 +1,183 @@
+package lifetimehttp3
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/tomwright/lifetime"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Option is used to configure a service created by NewService or NewDualService.
+type Option func(service *service)
+
+// WithShutdownTimeout sets how long in-flight requests are given to complete when the service
+// is stopped, after which any remaining connections are closed.
+// Defaults to 30 seconds.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(service *service) {
+		service.shutdownTimeout = timeout
+	}
+}
+
+// NewService returns a service that serves HTTP/3 using the given server, listening on server.Addr over UDP.
+// When the service is stopped the server sends a GOAWAY to each client and in-flight requests are given
+// time to complete before the server is closed. See WithShutdownTimeout.
+func NewService(server *http3.Server, opts ...Option) lifetime.Service {
+	return newService(server, nil, opts)
+}
+
+// NewDualService returns a service that serves the given handler over HTTP/1.1 and HTTP/2 on TCP, and over
+// HTTP/3 on UDP, using the same address for both.
+// Responses sent over TCP include an Alt-Svc header so that clients know they can switch to HTTP/3.
+// Both servers are shutdown gracefully when the service is stopped.
+func NewDualService(addr string, tlsConfig *tls.Config, handler http.Handler, opts ...Option) lifetime.Service {
+	h3 := &http3.Server{
+		Addr:      addr,
+		TLSConfig: http3.ConfigureTLSConfig(tlsConfig),
+		Handler:   handler,
+	}
+	tcp := &http.Server{
+		Addr:      addr,
+		TLSConfig: tlsConfig.Clone(),
+		Handler: http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			// An error is only returned if the HTTP/3 server is not listening, in which case
+			// it shouldn't be advertised.
+			_ = h3.SetQUICHeaders(writer.Header())
+			handler.ServeHTTP(writer, request)
+		}),
+		ReadHeaderTimeout: time.Second * 10,
+	}
+	return newService(h3, tcp, opts)
+}
+
+// newService returns a service that serves the given servers, applying the given options.
+func newService(h3 *http3.Server, tcp *http.Server, opts []Option) *service {
+	service := &service{
+		h3:              h3,
+		tcp:             tcp,
+		shutdownTimeout: time.Second * 30,
+		ready:           make(chan struct{}),
+		shutdown:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service
+}
+
+// service is an implementation of Service that serves a http3.Server, and optionally a
+// http.Server alongside it.
+type service struct {
+	h3              *http3.Server
+	tcp             *http.Server
+	shutdownTimeout time.Duration
+
+	ready     chan struct{}
+	readyOnce sync.Once
+	// shutdown is closed once a graceful shutdown of the servers has completed.
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+// Start will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal.
+func (service *service) Start() error {
+	addr := service.h3.Addr
+	if addr == "" {
+		addr = ":https"
+	}
+	udp, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return fmt.Errorf("could not listen on udp address: %w", err)
+	}
+	// The HTTP/3 server does not close connections it is given.
+	defer udp.Close()
+
+	var tcp net.Listener
+	if service.tcp != nil {
+		tcp, err = net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("could not listen on tcp address: %w", err)
+		}
+	}
+
+	servers := 1
+	errs := make(chan error, 2)
+	go func() {
+		errs <- service.h3.Serve(udp)
+	}()
+	if tcp != nil {
+		servers++
+		go func() {
+			errs <- service.tcp.ServeTLS(tcp, "", "")
+		}()
+	}
+	service.readyOnce.Do(func() {
+		close(service.ready)
+	})
+
+	var failure error
+	for i := 0; i < servers; i++ {
+		err := <-errs
+		if err == http.ErrServerClosed || failure != nil {
+			continue
+		}
+		// One of the servers failed so close the other rather than leaving it running on its own.
+		failure = err
+		service.close()
+	}
+	if failure != nil {
+		return failure
+	}
+	// Serve returns as soon as a shutdown begins so wait for in-flight requests to complete.
+	<-service.shutdown
+	return nil
+}
+
+// Stop will stop the service.
+// Stop is not called if Start returned an error.
+func (service *service) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), service.shutdownTimeout)
+	defer cancel()
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// The HTTP/3 server closes itself if the context is done before the shutdown completes.
+		_ = service.h3.Shutdown(ctx)
+	}()
+	if service.tcp != nil {
+		if err := service.tcp.Shutdown(ctx); err != nil {
+			_ = service.tcp.Close()
+		}
+	}
+	wg.Wait()
+	service.shutdownOnce.Do(func() {
+		close(service.shutdown)
+	})
+}
+
+// Class returns lifetime.ClassStateless, so the service is stopped before any stateful services.
+func (service *service) Class() lifetime.ServiceClass {
+	return lifetime.ClassStateless
+}
+
+// close immediately closes the servers.
+func (service *service) close() {
+	_ = service.h3.Close()
+	if service.tcp != nil {
+		_ = service.tcp.Close()
+	}
+}
+
+// Ready returns a channel that is closed once the service is listening for connections.
+func (service *service) Ready() <-chan struct{} {
+	return service.ready
+}
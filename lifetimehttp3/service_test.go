@@ -0,0 +1,136 @@
+package lifetimehttp3_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/tomwright/lifetime"
+	"github.com/tomwright/lifetime/lifetimehttp3"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// selfSignedTLSConfig returns a tls.Config with a certificate for 127.0.0.1.
+func selfSignedTLSConfig(t *testing.T) *tls.Config {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create certificate: %s", err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}
+}
+
+// freePort returns a port that is currently free for TCP.
+func freePort(t *testing.T) int {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %s", err)
+	}
+	defer lis.Close()
+	return lis.Addr().(*net.TCPAddr).Port
+}
+
+func get(t *testing.T, client *http.Client, url string) *http.Response {
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("could not get %s: %s", url, err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "hello" {
+		t.Errorf("expected body hello, got %q", string(body))
+	}
+	return resp
+}
+
+func TestNewDualService(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	port := freePort(t)
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte("hello"))
+	})
+	svc := lifetimehttp3.NewDualService(addr, selfSignedTLSConfig(t), handler)
+	lt.Start(svc)
+	<-svc.(lifetime.Readier).Ready()
+
+	clientTLS := &tls.Config{InsecureSkipVerify: true}
+
+	tcpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS}}
+	resp := get(t, tcpClient, "https://"+addr)
+	if altSvc := resp.Header.Get("Alt-Svc"); !strings.Contains(altSvc, fmt.Sprintf(`h3=":%d"`, port)) {
+		t.Errorf("expected HTTP/3 to be advertised, got %q", altSvc)
+	}
+	tcpClient.CloseIdleConnections()
+
+	transport := &http3.Transport{TLSClientConfig: clientTLS}
+	defer transport.Close()
+	resp = get(t, &http.Client{Transport: transport}, "https://"+addr)
+	if resp.ProtoMajor != 3 {
+		t.Errorf("expected HTTP/3, got %s", resp.Proto)
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestNewService_ListenError(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	lt.Start(lifetimehttp3.NewService(&http3.Server{Addr: "invalid address"}, lifetimehttp3.WithShutdownTimeout(time.Second)))
+	_ = lt.Wait()
+
+	if cause, _ := lt.Cause(); cause != lifetime.CauseServiceError {
+		t.Errorf("expected a service error, got %s", cause)
+	}
+}
+
+func TestNewService_StopTwice(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte("hello"))
+	})
+	svc := lifetimehttp3.NewService(&http3.Server{
+		Addr:      fmt.Sprintf("127.0.0.1:%d", freePort(t)),
+		TLSConfig: http3.ConfigureTLSConfig(selfSignedTLSConfig(t)),
+		Handler:   handler,
+	}, lifetimehttp3.WithShutdownTimeout(time.Second))
+
+	startErr := make(chan error)
+	go func() {
+		startErr <- svc.Start()
+	}()
+	<-svc.(lifetime.Readier).Ready()
+
+	svc.Stop()
+	if err := <-startErr; err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	// A second stop, such as one after a restart, must not panic.
+	svc.Stop()
+}
@@ -0,0 +1,184 @@
+package lifetimeprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tomwright/lifetime"
+)
+
+// CollectorOption is used to configure a collector created by NewCollector.
+type CollectorOption func(collector *collector)
+
+// WithServiceLabels adds the service labels with the given keys to the per-service metrics as prometheus labels,
+// alongside the service name. See lifetime.WithLabels.
+// Services that don't have one of the labels export it with an empty value, as do the stop timings of the
+// previous run. Each key must be a valid prometheus label name other than "service".
+func WithServiceLabels(keys ...string) CollectorOption {
+	return func(collector *collector) {
+		collector.serviceLabels = append(collector.serviceLabels, keys...)
+	}
+}
+
+// NewCollector returns a prometheus.Collector that exposes the state of the given lifetime.
+// It should be created before any services are started so that no events are missed.
+// A shutdown usually completes too close to the exit of the application for its timings to be scraped,
+// so if lifetime.WithTimingsFile is used the shutdown timings of the previous run are observed when
+// the collector is created.
+func NewCollector(lt *lifetime.Lifetime, opts ...CollectorOption) prometheus.Collector {
+	collector := &collector{
+		lifetime: lt,
+		ready: prometheus.NewDesc(
+			"lifetime_ready",
+			"Whether the application is ready.",
+			nil, nil,
+		),
+		degraded: prometheus.NewDesc(
+			"lifetime_degraded",
+			"Whether the application is running without some of its services.",
+			nil, nil,
+		),
+		shuttingDown: prometheus.NewDesc(
+			"lifetime_shutting_down",
+			"Whether the application is shutting down.",
+			nil, nil,
+		),
+		servicesRunning: prometheus.NewDesc(
+			"lifetime_services_running",
+			"The number of running services.",
+			nil, nil,
+		),
+		droppedErrors: prometheus.NewDesc(
+			"lifetime_dropped_errors_total",
+			"The number of errors dropped because the error queue was full.",
+			nil, nil,
+		),
+	}
+	for _, opt := range opts {
+		opt(collector)
+	}
+	serviceLabelNames := append([]string{"service"}, collector.serviceLabels...)
+	collector.serviceStarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "lifetime_service_starts_total",
+		Help: "The number of times each service has been started.",
+	}, serviceLabelNames)
+	collector.serviceFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "lifetime_service_failures_total",
+		Help: "The number of times each service has failed.",
+	}, serviceLabelNames)
+	collector.serviceStopDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "lifetime_service_stop_duration_seconds",
+		Help:    "The time taken for each service to stop.",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, serviceLabelNames)
+	collector.startupDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "lifetime_startup_duration_seconds",
+		Help:    "The time taken for the application to become ready.",
+		Buckets: []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	})
+	collector.shutdownDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "lifetime_shutdown_duration_seconds",
+		Help:    "The time taken for the application to shutdown, including the drain delay.",
+		Buckets: []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	})
+	collector.observePreviousRun()
+	lt.OnEvent(collector.handleEvent)
+	return collector
+}
+
+// collector is a prometheus.Collector that exposes the state of a lifetime.
+type collector struct {
+	lifetime *lifetime.Lifetime
+
+	ready           *prometheus.Desc
+	degraded        *prometheus.Desc
+	shuttingDown    *prometheus.Desc
+	servicesRunning *prometheus.Desc
+	droppedErrors   *prometheus.Desc
+
+	// serviceLabels are the keys of the service labels exported by the per-service metrics. See WithServiceLabels.
+	serviceLabels []string
+
+	serviceStarts       *prometheus.CounterVec
+	serviceFailures     *prometheus.CounterVec
+	serviceStopDuration *prometheus.HistogramVec
+	startupDuration     prometheus.Histogram
+	shutdownDuration    prometheus.Histogram
+}
+
+// observePreviousRun observes the shutdown timings of the previous run, if there was one.
+func (collector *collector) observePreviousRun() {
+	previous := collector.lifetime.PreviousTimings()
+	if len(previous) == 0 {
+		return
+	}
+	run := previous[len(previous)-1]
+	if run.Shutdown > 0 {
+		collector.shutdownDuration.Observe(run.Shutdown.Seconds())
+	}
+	for service, d := range run.ServiceStops {
+		collector.serviceStopDuration.WithLabelValues(collector.labelValues(service, nil)...).Observe(d.Seconds())
+	}
+}
+
+// handleEvent updates the metrics that are derived from lifetime events.
+func (collector *collector) handleEvent(event lifetime.Event) {
+	switch event.Type {
+	case lifetime.EventServiceStarted:
+		collector.serviceStarts.WithLabelValues(collector.labelValues(event.Service, event.Labels)...).Inc()
+	case lifetime.EventServiceFailed:
+		collector.serviceFailures.WithLabelValues(collector.labelValues(event.Service, event.Labels)...).Inc()
+	case lifetime.EventServiceStopped:
+		collector.serviceStopDuration.WithLabelValues(collector.labelValues(event.Service, event.Labels)...).
+			Observe(event.Duration.Seconds())
+	case lifetime.EventReady:
+		collector.startupDuration.Observe(event.Duration.Seconds())
+	case lifetime.EventShutdownComplete:
+		collector.shutdownDuration.Observe(event.Duration.Seconds())
+	}
+}
+
+// labelValues returns the values of the prometheus labels of the per-service metrics for the given service.
+func (collector *collector) labelValues(service string, labels lifetime.Labels) []string {
+	values := make([]string, 0, len(collector.serviceLabels)+1)
+	values = append(values, service)
+	for _, key := range collector.serviceLabels {
+		values = append(values, labels[key])
+	}
+	return values
+}
+
+// Describe sends the descriptors of the metrics to the given channel.
+func (collector *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.ready
+	ch <- collector.degraded
+	ch <- collector.shuttingDown
+	ch <- collector.servicesRunning
+	ch <- collector.droppedErrors
+	collector.serviceStarts.Describe(ch)
+	collector.serviceFailures.Describe(ch)
+	collector.serviceStopDuration.Describe(ch)
+	collector.startupDuration.Describe(ch)
+	collector.shutdownDuration.Describe(ch)
+}
+
+// Collect sends the current metrics to the given channel.
+func (collector *collector) Collect(ch chan<- prometheus.Metric) {
+	status := collector.lifetime.Status()
+	ch <- prometheus.MustNewConstMetric(collector.ready, prometheus.GaugeValue, boolToFloat(status.Ready))
+	ch <- prometheus.MustNewConstMetric(collector.degraded, prometheus.GaugeValue, boolToFloat(status.Health == lifetime.HealthDegraded))
+	ch <- prometheus.MustNewConstMetric(collector.shuttingDown, prometheus.GaugeValue, boolToFloat(status.ShuttingDown))
+	ch <- prometheus.MustNewConstMetric(collector.servicesRunning, prometheus.GaugeValue, float64(len(status.Services)))
+	ch <- prometheus.MustNewConstMetric(collector.droppedErrors, prometheus.CounterValue, float64(collector.lifetime.DroppedErrors()))
+	collector.serviceStarts.Collect(ch)
+	collector.serviceFailures.Collect(ch)
+	collector.serviceStopDuration.Collect(ch)
+	collector.startupDuration.Collect(ch)
+	collector.shutdownDuration.Collect(ch)
+}
+
+// boolToFloat returns 1 if the given bool is true, otherwise 0.
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
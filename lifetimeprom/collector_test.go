@@ -0,0 +1,137 @@
+package lifetimeprom_test
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tomwright/lifetime"
+	"github.com/tomwright/lifetime/lifetimeprom"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// noopService is a service that blocks until it is stopped.
+type noopService struct {
+	stop chan struct{}
+}
+
+func (s *noopService) Start() error {
+	<-s.stop
+	return nil
+}
+
+func (s *noopService) Stop() {
+	close(s.stop)
+}
+
+// gather returns the value of each metric in the registry, or the sample count of histograms.
+func gather(t *testing.T, registry *prometheus.Registry) map[string]float64 {
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			switch {
+			case metric.GetGauge() != nil:
+				values[family.GetName()] = metric.GetGauge().GetValue()
+			case metric.GetCounter() != nil:
+				values[family.GetName()] = metric.GetCounter().GetValue()
+			case metric.GetHistogram() != nil:
+				values[family.GetName()] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return values
+}
+
+func TestNewCollector(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(lifetimeprom.NewCollector(lt))
+
+	lt.Start(&noopService{stop: make(chan struct{})}, lifetime.WithName("worker"))
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	values := gather(t, registry)
+
+	exp := map[string]float64{
+		"lifetime_ready":                         0,
+		"lifetime_degraded":                      0,
+		"lifetime_shutting_down":                 1,
+		"lifetime_services_running":              0,
+		"lifetime_dropped_errors_total":          0,
+		"lifetime_service_starts_total":          1,
+		"lifetime_service_stop_duration_seconds": 1,
+		"lifetime_startup_duration_seconds":      0,
+		"lifetime_shutdown_duration_seconds":     1,
+	}
+	for name, value := range exp {
+		got, ok := values[name]
+		if !ok {
+			t.Errorf("expected metric %s to be gathered", name)
+			continue
+		}
+		if got != value {
+			t.Errorf("expected %s to be %v, got %v", name, value, got)
+		}
+	}
+}
+
+func TestNewCollector_PreviousRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lifetimeprom")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "timings.json")
+
+	previous := lifetime.New(context.Background(), lifetime.WithTimingsFile(path)).HandleErrors(nil)
+	previous.Start(&noopService{stop: make(chan struct{})}, lifetime.WithName("worker"))
+	previous.Shutdown()
+	_ = previous.Wait()
+
+	lt := lifetime.New(context.Background(), lifetime.WithTimingsFile(path)).HandleErrors(nil)
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(lifetimeprom.NewCollector(lt))
+
+	values := gather(t, registry)
+	if values["lifetime_shutdown_duration_seconds"] != 1 || values["lifetime_service_stop_duration_seconds"] != 1 {
+		t.Errorf("expected the previous shutdown to be observed, got %v", values)
+	}
+}
+
+func TestNewCollector_ServiceLabels(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(lifetimeprom.NewCollector(lt, lifetimeprom.WithServiceLabels("team")))
+
+	lt.Start(&noopService{stop: make(chan struct{})}, lifetime.WithName("worker"),
+		lifetime.WithLabels(lifetime.Labels{"team": "payments", "tier": "backend"}))
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != "lifetime_service_starts_total" {
+			continue
+		}
+		labels := map[string]string{}
+		for _, pair := range family.GetMetric()[0].GetLabel() {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+		if len(labels) != 2 || labels["service"] != "worker" || labels["team"] != "payments" {
+			t.Errorf("expected the service and team labels, got %v", labels)
+		}
+		return
+	}
+	t.Errorf("expected lifetime_service_starts_total to be gathered")
+}
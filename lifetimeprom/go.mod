@@ -0,0 +1,12 @@
+module github.com/tomwright/lifetime/lifetimeprom
+
+go 1.13
+
+require (
+	github.com/prometheus/client_golang v1.12.2
+	github.com/tomwright/lifetime v0.0.0
+	golang.org/x/sys v0.7.0 // indirect
+	google.golang.org/protobuf v1.27.1 // indirect
+)
+
+replace github.com/tomwright/lifetime => ../
@@ -0,0 +1,19 @@
+package lifetimeprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/tomwright/lifetime"
+	"net/http"
+)
+
+// NewMetricsService returns a service that exposes the metrics from the given gatherer on /metrics
+// at the given address.
+// The metrics server is rarely something the application can't run without, so the service is non-critical
+// and a failure, such as the address already being in use, doesn't trigger a shutdown. See lifetime.NonCritical.
+func NewMetricsService(addr string, gatherer prometheus.Gatherer, opts ...lifetime.HTTPOption) lifetime.Service {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+	opts = append([]lifetime.HTTPOption{lifetime.WithHTTPNonCritical()}, opts...)
+	return lifetime.NewHTTPHandlerService(addr, mux, opts...)
+}
@@ -0,0 +1,38 @@
+package lifetimeprom_test
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tomwright/lifetime"
+	"github.com/tomwright/lifetime/lifetimeprom"
+	"net"
+	"testing"
+)
+
+func TestNewMetricsService_NonCritical(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer listener.Close()
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	failed := make(chan struct{})
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceFailed {
+			close(failed)
+		}
+	})
+
+	// The address is already in use, so the service fails.
+	lt.Start(lifetimeprom.NewMetricsService(listener.Addr().String(), prometheus.NewRegistry()))
+	<-failed
+
+	lt.Shutdown()
+	if err := lt.WaitErr(); err != nil {
+		t.Errorf("expected the failure not to trigger a shutdown, got %v", err)
+	}
+	if cause, _ := lt.Cause(); cause != lifetime.CauseManual {
+		t.Errorf("expected a manual shutdown, got %s", cause)
+	}
+}
@@ -0,0 +1,93 @@
+package lifetimetest
+
+import (
+	"os"
+	"sync"
+)
+
+// Signals is a fake lifetime.SignalSource that relays the signals given to Send rather than those received
+// by the process. See lifetime.WithSignalSource.
+type Signals struct {
+	mu            sync.Mutex
+	subscriptions []subscription
+}
+
+// subscription is a channel that signals are relayed to.
+type subscription struct {
+	c    chan<- os.Signal
+	sigs []os.Signal
+}
+
+// NewSignals returns a fake signal source.
+func NewSignals() *Signals {
+	return &Signals{}
+}
+
+// Notify causes the given signals to be relayed to the given channel, or every signal if none are given.
+func (signals *Signals) Notify(c chan<- os.Signal, sigs ...os.Signal) {
+	signals.mu.Lock()
+	defer signals.mu.Unlock()
+	signals.subscriptions = append(signals.subscriptions, subscription{c: c, sigs: sigs})
+}
+
+// Send relays the given signal to every channel that it has been requested for, blocking until each
+// channel has accepted it.
+func (signals *Signals) Send(sig os.Signal) {
+	signals.mu.Lock()
+	subscriptions := append([]subscription(nil), signals.subscriptions...)
+	signals.mu.Unlock()
+
+	for _, s := range subscriptions {
+		if s.wants(sig) {
+			s.c <- sig
+		}
+	}
+}
+
+// wants returns true if the given signal should be relayed to the subscription.
+func (s subscription) wants(sig os.Signal) bool {
+	if len(s.sigs) == 0 {
+		return true
+	}
+	for _, want := range s.sigs {
+		if want == sig {
+			return true
+		}
+	}
+	return false
+}
+
+// ExitRecorder records exit codes rather than exiting. Use its Exit method with lifetime.WithExitFunc.
+type ExitRecorder struct {
+	mu     sync.Mutex
+	codes  []int
+	once   sync.Once
+	exited chan struct{}
+}
+
+// NewExitRecorder returns an exit recorder.
+func NewExitRecorder() *ExitRecorder {
+	return &ExitRecorder{exited: make(chan struct{})}
+}
+
+// Exit records the given exit code.
+func (recorder *ExitRecorder) Exit(code int) {
+	recorder.mu.Lock()
+	recorder.codes = append(recorder.codes, code)
+	recorder.mu.Unlock()
+	recorder.once.Do(func() {
+		close(recorder.exited)
+	})
+}
+
+// Codes returns the recorded exit codes, in the order Exit was called.
+func (recorder *ExitRecorder) Codes() []int {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return append([]int(nil), recorder.codes...)
+}
+
+// Exited returns a channel that is closed once Exit has been called.
+func (recorder *ExitRecorder) Exited() <-chan struct{} {
+	return recorder.exited
+}
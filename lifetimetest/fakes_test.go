@@ -0,0 +1,34 @@
+package lifetimetest_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"github.com/tomwright/lifetime/lifetimetest"
+	"syscall"
+	"testing"
+)
+
+func TestSignals(t *testing.T) {
+	signals := lifetimetest.NewSignals()
+	exits := lifetimetest.NewExitRecorder()
+	lt := lifetime.New(context.Background(),
+		lifetime.WithSignalSource(signals),
+		lifetime.WithExitFunc(exits.Exit),
+		lifetime.WithImmediateShutdownExitCode(2),
+	).Init()
+	lt.Start(newBlockingService())
+
+	signals.Send(syscall.SIGTERM)
+	signals.Send(syscall.SIGTERM)
+	<-exits.Exited()
+
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if codes := exits.Codes(); len(codes) != 1 || codes[0] != 2 {
+		t.Errorf("expected a single exit with code 2, got %v", codes)
+	}
+	if cause, _ := lt.Cause(); cause != lifetime.CauseSignal {
+		t.Errorf("expected the shutdown to be caused by a signal, got %s", cause)
+	}
+}
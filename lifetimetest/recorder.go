@@ -0,0 +1,141 @@
+// Package lifetimetest provides helpers for testing applications that use lifetime, such as recording the order
+// services are started and stopped in so that tests can assert ordering guarantees without relying on sleeps.
+package lifetimetest
+
+import (
+	"errors"
+	"fmt"
+	"github.com/tomwright/lifetime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Recorder records the order in which the services of a lifetime are started and stopped.
+// It is safe to use from multiple go routines.
+type Recorder struct {
+	mu sync.Mutex
+	// entries are the recorded events, in the order they were emitted.
+	entries []entry
+}
+
+// entry is a single recorded event.
+type entry struct {
+	eventType lifetime.EventType
+	service   string
+}
+
+// NewRecorder returns a Recorder that records the services of the given lifetime.
+// It must be created before the services are started.
+func NewRecorder(lt *lifetime.Lifetime) *Recorder {
+	recorder := &Recorder{}
+	lt.OnEvent(recorder.record)
+	return recorder
+}
+
+// record records the given event if it relates to a service starting or stopping.
+func (recorder *Recorder) record(event lifetime.Event) {
+	switch event.Type {
+	case lifetime.EventServiceStarted, lifetime.EventServiceStopping, lifetime.EventServiceStopped:
+	default:
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.entries = append(recorder.entries, entry{eventType: event.Type, service: event.Service})
+}
+
+// Started returns the names of the services in the order they were started.
+// A service that was started more than once appears once for each time it was started.
+func (recorder *Recorder) Started() []string {
+	return recorder.services(lifetime.EventServiceStarted)
+}
+
+// Stopped returns the names of the services in the order they finished stopping.
+// A service that was stopped more than once appears once for each time it was stopped.
+func (recorder *Recorder) Stopped() []string {
+	return recorder.services(lifetime.EventServiceStopped)
+}
+
+// services returns the names of the services of the recorded events of the given type.
+func (recorder *Recorder) services(eventType lifetime.EventType) []string {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	services := make([]string, 0)
+	for _, e := range recorder.entries {
+		if e.eventType == eventType {
+			services = append(services, e.service)
+		}
+	}
+	return services
+}
+
+// index returns the position of the first recorded event of the given type for the given service, or -1.
+func (recorder *Recorder) index(eventType lifetime.EventType, service string) int {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	for i, e := range recorder.entries {
+		if e.eventType == eventType && e.service == service {
+			return i
+		}
+	}
+	return -1
+}
+
+// Expectation checks the order recorded by a Recorder, returning an error describing the problem if it
+// doesn't match.
+type Expectation func(recorder *Recorder) error
+
+// StartedBefore expects the first service to have been started before the second service.
+func StartedBefore(first string, second string) Expectation {
+	return func(recorder *Recorder) error {
+		return recorder.before(first, lifetime.EventServiceStarted, second, lifetime.EventServiceStarted, "started")
+	}
+}
+
+// StoppedBefore expects the first service to have finished stopping before the second service was told to stop.
+func StoppedBefore(first string, second string) Expectation {
+	return func(recorder *Recorder) error {
+		return recorder.before(first, lifetime.EventServiceStopped, second, lifetime.EventServiceStopping, "stopped")
+	}
+}
+
+// before returns an error if the first event was not recorded before the second event.
+func (recorder *Recorder) before(first string, firstType lifetime.EventType, second string, secondType lifetime.EventType, verb string) error {
+	i := recorder.index(firstType, first)
+	if i < 0 {
+		return fmt.Errorf("expected %s to be %s before %s, but %s was not %s", first, verb, second, first, verb)
+	}
+	j := recorder.index(secondType, second)
+	if j < 0 {
+		return fmt.Errorf("expected %s to be %s before %s, but %s was not %s", first, verb, second, second, verb)
+	}
+	if i > j {
+		return fmt.Errorf("expected %s to be %s before %s", first, verb, second)
+	}
+	return nil
+}
+
+// Check returns an error describing every expectation that isn't met, or nil if they are all met.
+func (recorder *Recorder) Check(expectations ...Expectation) error {
+	problems := make([]string, 0)
+	for _, expectation := range expectations {
+		if err := expectation(recorder); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(problems, "; "))
+}
+
+// Assert reports every expectation that isn't met as an error on the given test.
+func (recorder *Recorder) Assert(t testing.TB, expectations ...Expectation) {
+	t.Helper()
+	for _, expectation := range expectations {
+		if err := expectation(recorder); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package lifetimetest_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"github.com/tomwright/lifetime/lifetimetest"
+	"testing"
+)
+
+// blockingService is a service that blocks until it is stopped.
+type blockingService struct {
+	stop chan struct{}
+}
+
+func newBlockingService() *blockingService {
+	return &blockingService{stop: make(chan struct{})}
+}
+
+func (s *blockingService) Start() error {
+	<-s.stop
+	return nil
+}
+
+func (s *blockingService) Stop() {
+	close(s.stop)
+}
+
+func TestRecorder(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithReverseStopOrder())
+	recorder := lifetimetest.NewRecorder(lt)
+
+	started := make(chan struct{})
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceStarted && event.Service == "api" {
+			close(started)
+		}
+	})
+
+	db := lt.Start(newBlockingService(), lifetime.WithName("db"))
+	lt.Start(newBlockingService(), lifetime.WithName("api"), lifetime.After(db))
+
+	<-started
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	recorder.Assert(t,
+		lifetimetest.StartedBefore("db", "api"),
+		lifetimetest.StoppedBefore("api", "db"),
+	)
+	if err := recorder.Check(lifetimetest.StoppedBefore("db", "api")); err == nil {
+		t.Errorf("expected an error")
+	}
+	if err := recorder.Check(lifetimetest.StoppedBefore("api", "cache")); err == nil {
+		t.Errorf("expected an error for a service that was not stopped")
+	}
+	if got := recorder.Stopped(); len(got) != 2 || got[0] != "api" || got[1] != "db" {
+		t.Errorf("expected [api db], got %v", got)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/tomwright/lifetime/lifetimezap
+
+go 1.13
+
+require (
+	github.com/tomwright/lifetime v0.0.0
+	go.uber.org/zap v1.19.1
+)
+
+replace github.com/tomwright/lifetime => ../
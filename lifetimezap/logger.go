@@ -0,0 +1,46 @@
+// Package lifetimezap provides a lifetime.Logger implementation backed by zap.
+package lifetimezap
+
+import (
+	"github.com/tomwright/lifetime"
+	"go.uber.org/zap"
+)
+
+// New returns a lifetime.Logger that writes to the given zap logger.
+func New(logger *zap.Logger) lifetime.Logger {
+	return &zapLogger{logger: logger}
+}
+
+// zapLogger is an implementation of lifetime.Logger that writes to a zap logger.
+type zapLogger struct {
+	logger *zap.Logger
+}
+
+// Debug logs a message useful when debugging.
+func (logger *zapLogger) Debug(msg string, fields ...lifetime.Field) {
+	logger.logger.Debug(msg, zapFields(fields)...)
+}
+
+// Info logs an informational message.
+func (logger *zapLogger) Info(msg string, fields ...lifetime.Field) {
+	logger.logger.Info(msg, zapFields(fields)...)
+}
+
+// Warn logs a message about something that may need attention.
+func (logger *zapLogger) Warn(msg string, fields ...lifetime.Field) {
+	logger.logger.Warn(msg, zapFields(fields)...)
+}
+
+// Error logs a message about a failure.
+func (logger *zapLogger) Error(msg string, fields ...lifetime.Field) {
+	logger.logger.Error(msg, zapFields(fields)...)
+}
+
+// zapFields converts lifetime fields to zap fields.
+func zapFields(fields []lifetime.Field) []zap.Field {
+	res := make([]zap.Field, len(fields))
+	for i, field := range fields {
+		res[i] = zap.Any(field.Key, field.Value)
+	}
+	return res
+}
@@ -0,0 +1,53 @@
+package lifetimezap_test
+
+import (
+	"errors"
+	"github.com/tomwright/lifetime"
+	"github.com/tomwright/lifetime/lifetimezap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := lifetimezap.New(zap.New(core))
+
+	err := errors.New("boom")
+	logger.Debug("debug", lifetime.Field{Key: "service", Value: "api"})
+	logger.Info("info", lifetime.Field{Key: "count", Value: 3})
+	logger.Warn("warn")
+	logger.Error("error", lifetime.Field{Key: "error", Value: err})
+
+	entries := logs.AllUntimed()
+	exp := []struct {
+		level  zapcore.Level
+		msg    string
+		fields map[string]interface{}
+	}{
+		{level: zapcore.DebugLevel, msg: "debug", fields: map[string]interface{}{"service": "api"}},
+		{level: zapcore.InfoLevel, msg: "info", fields: map[string]interface{}{"count": int64(3)}},
+		{level: zapcore.WarnLevel, msg: "warn", fields: map[string]interface{}{}},
+		{level: zapcore.ErrorLevel, msg: "error", fields: map[string]interface{}{"error": "boom"}},
+	}
+	if len(entries) != len(exp) {
+		t.Fatalf("expected %d entries, got %d", len(exp), len(entries))
+	}
+	for i, e := range exp {
+		entry := entries[i]
+		if entry.Level != e.level || entry.Message != e.msg {
+			t.Errorf("expected %s %q, got %s %q", e.level, e.msg, entry.Level, entry.Message)
+		}
+		fields := entry.ContextMap()
+		if len(fields) != len(e.fields) {
+			t.Errorf("expected fields %v, got %v", e.fields, fields)
+			continue
+		}
+		for key, value := range e.fields {
+			if fields[key] != value {
+				t.Errorf("expected field %s to be %v (%T), got %v (%T)", key, value, value, fields[key], fields[key])
+			}
+		}
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/tomwright/lifetime/lifetimezerolog
+
+go 1.13
+
+require (
+	github.com/rs/zerolog v1.20.0
+	github.com/tomwright/lifetime v0.0.0
+)
+
+replace github.com/tomwright/lifetime => ../
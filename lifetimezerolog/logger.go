@@ -0,0 +1,50 @@
+// Package lifetimezerolog provides a lifetime.Logger implementation backed by zerolog.
+package lifetimezerolog
+
+import (
+	"github.com/rs/zerolog"
+	"github.com/tomwright/lifetime"
+)
+
+// New returns a lifetime.Logger that writes to the given zerolog logger.
+func New(logger zerolog.Logger) lifetime.Logger {
+	return &zerologLogger{logger: logger}
+}
+
+// zerologLogger is an implementation of lifetime.Logger that writes to a zerolog logger.
+type zerologLogger struct {
+	logger zerolog.Logger
+}
+
+// Debug logs a message useful when debugging.
+func (logger *zerologLogger) Debug(msg string, fields ...lifetime.Field) {
+	write(logger.logger.Debug(), msg, fields)
+}
+
+// Info logs an informational message.
+func (logger *zerologLogger) Info(msg string, fields ...lifetime.Field) {
+	write(logger.logger.Info(), msg, fields)
+}
+
+// Warn logs a message about something that may need attention.
+func (logger *zerologLogger) Warn(msg string, fields ...lifetime.Field) {
+	write(logger.logger.Warn(), msg, fields)
+}
+
+// Error logs a message about a failure.
+func (logger *zerologLogger) Error(msg string, fields ...lifetime.Field) {
+	write(logger.logger.Error(), msg, fields)
+}
+
+// write adds the given fields to the event and sends it.
+// Errors are written as their message, since most errors have no exported fields to marshal.
+func write(event *zerolog.Event, msg string, fields []lifetime.Field) {
+	for _, field := range fields {
+		if err, ok := field.Value.(error); ok {
+			event = event.AnErr(field.Key, err)
+			continue
+		}
+		event = event.Interface(field.Key, field.Value)
+	}
+	event.Msg(msg)
+}
@@ -0,0 +1,48 @@
+package lifetimezerolog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/rs/zerolog"
+	"github.com/tomwright/lifetime"
+	"github.com/tomwright/lifetime/lifetimezerolog"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lifetimezerolog.New(zerolog.New(buf).Level(zerolog.DebugLevel))
+
+	logger.Debug("debug", lifetime.Field{Key: "service", Value: "api"})
+	logger.Info("info", lifetime.Field{Key: "count", Value: 3})
+	logger.Warn("warn")
+	logger.Error("error", lifetime.Field{Key: "error", Value: errors.New("boom")})
+
+	exp := []map[string]interface{}{
+		{"level": "debug", "message": "debug", "service": "api"},
+		{"level": "info", "message": "info", "count": float64(3)},
+		{"level": "warn", "message": "warn"},
+		{"level": "error", "message": "error", "error": "boom"},
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(exp) {
+		t.Fatalf("expected %d lines, got %d: %s", len(exp), len(lines), buf.String())
+	}
+	for i, line := range lines {
+		got := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != len(exp[i]) {
+			t.Errorf("expected %v, got %v", exp[i], got)
+			continue
+		}
+		for key, value := range exp[i] {
+			if got[key] != value {
+				t.Errorf("expected %s to be %v, got %v", key, value, got[key])
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package lifetime
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Level is the severity of a log message.
+type Level int32
+
+const (
+	// LevelDebug is used for detailed messages such as individual service transitions.
+	LevelDebug Level = iota
+	// LevelInfo is used for lifetime phase changes such as a shutdown starting.
+	LevelInfo
+	// LevelWarn is used for things that may need attention.
+	LevelWarn
+	// LevelError is used for failures.
+	LevelError
+)
+
+// String returns the name of the level.
+func (level Level) String() string {
+	switch level {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseLevel returns the level with the given name, as returned by Level.String.
+// It is not case sensitive.
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level: %s", name)
+	}
+}
+
+// WithLogLevel sets the minimum level of messages that are written to the logger.
+// Defaults to LevelInfo.
+func WithLogLevel(level Level) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.logLevel = int32(level)
+	}
+}
+
+// SetLogLevel changes the minimum level of messages that are written to the logger while the
+// application is running.
+func (lifetime *Lifetime) SetLogLevel(level Level) {
+	atomic.StoreInt32(&lifetime.logLevel, int32(level))
+	lifetime.log(LevelInfo, "lifetime log level changed", Field{Key: "level", Value: level.String()})
+}
+
+// enabled returns true if messages of the given level should be logged.
+func (lifetime *Lifetime) enabled(level Level) bool {
+	return int32(level) >= atomic.LoadInt32(&lifetime.logLevel)
+}
+
+// log writes the message to the logger if the level is enabled.
+func (lifetime *Lifetime) log(level Level, msg string, fields ...Field) {
+	if !lifetime.enabled(level) {
+		return
+	}
+	if id := lifetime.ShutdownID(); id != "" {
+		fields = append(fields, Field{Key: "shutdown_id", Value: id})
+	}
+	switch level {
+	case LevelDebug:
+		lifetime.logger.Debug(msg, fields...)
+	case LevelInfo:
+		lifetime.logger.Info(msg, fields...)
+	case LevelWarn:
+		lifetime.logger.Warn(msg, fields...)
+	default:
+		lifetime.logger.Error(msg, fields...)
+	}
+}
@@ -0,0 +1,94 @@
+package lifetime
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// logReopener is a func registered with OnLogReopen.
+type logReopener struct {
+	name string
+	fn   func() error
+}
+
+// OnLogReopen registers a func that reopens a log file, such as after it has been rotated by logrotate.
+// Every registered func is executed by ReopenLogs, and when a SIGUSR1 is received once HandleLogReopen
+// has been called.
+func (lifetime *Lifetime) OnLogReopen(name string, fn func() error) {
+	lifetime.logReopenersMu.Lock()
+	defer lifetime.logReopenersMu.Unlock()
+	lifetime.logReopeners = append(lifetime.logReopeners, logReopener{name: name, fn: fn})
+}
+
+// ReopenLogs executes every func registered with OnLogReopen.
+// Every func is executed even if one fails, and the first error is returned.
+func (lifetime *Lifetime) ReopenLogs() error {
+	lifetime.logReopenersMu.Lock()
+	reopeners := make([]logReopener, len(lifetime.logReopeners))
+	copy(reopeners, lifetime.logReopeners)
+	lifetime.logReopenersMu.Unlock()
+
+	lifetime.log(LevelInfo, "lifetime reopening logs", Field{Key: "reopeners", Value: len(reopeners)})
+
+	var first error
+	for _, reopener := range reopeners {
+		if err := reopener.fn(); err != nil {
+			err = fmt.Errorf("could not reopen %s: %w", reopener.name, err)
+			lifetime.log(LevelError, "lifetime log reopen failed", Field{Key: "error", Value: err})
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// NewReopenableFile opens the given file for appending, creating it if required.
+// The returned file can be given to OnLogReopen using its Reopen func.
+func NewReopenableFile(path string) (*ReopenableFile, error) {
+	file := &ReopenableFile{path: path}
+	if err := file.Reopen(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// ReopenableFile is a log file that can be reopened once it has been moved by a log rotation,
+// so that writes go to a new file at the same path.
+// It is safe for concurrent use.
+type ReopenableFile struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Write writes to the file.
+func (file *ReopenableFile) Write(p []byte) (int, error) {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+	return file.file.Write(p)
+}
+
+// Reopen closes the file and opens the file at the same path again.
+func (file *ReopenableFile) Reopen() error {
+	f, err := os.OpenFile(file.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open log file: %w", err)
+	}
+	file.mu.Lock()
+	defer file.mu.Unlock()
+	if file.file != nil {
+		_ = file.file.Close()
+	}
+	file.file = f
+	return nil
+}
+
+// Close closes the file.
+func (file *ReopenableFile) Close() error {
+	file.mu.Lock()
+	defer file.mu.Unlock()
+	return file.file.Close()
+}
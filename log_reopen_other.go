@@ -0,0 +1,10 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package lifetime
+
+// HandleLogReopen does nothing on this platform, as there is no SIGUSR1.
+// ReopenLogs can still be called directly.
+func (lifetime *Lifetime) HandleLogReopen() *Lifetime {
+	return lifetime
+}
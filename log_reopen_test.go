@@ -0,0 +1,48 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLifetime_ReopenLogs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lifetime")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	file, err := lifetime.NewReopenableFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer file.Close()
+
+	lt := lifetime.New(context.Background())
+	lt.OnLogReopen("app.log", file.Reopen)
+	reopenErr := errors.New("could not reopen")
+	lt.OnLogReopen("broken", func() error {
+		return reopenErr
+	})
+
+	_, _ = file.Write([]byte("before\n"))
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("could not rotate log: %v", err)
+	}
+	if err := lt.ReopenLogs(); !errors.Is(err, reopenErr) {
+		t.Errorf("expected reopen error, got %v", err)
+	}
+	_, _ = file.Write([]byte("after\n"))
+
+	rotated, _ := ioutil.ReadFile(path + ".1")
+	current, _ := ioutil.ReadFile(path)
+	if string(rotated) != "before\n" || string(current) != "after\n" {
+		t.Errorf("expected writes to move to the new file, got %q and %q", string(rotated), string(current))
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package lifetime
+
+import (
+	"os"
+	"syscall"
+)
+
+// HandleLogReopen starts a go routine that executes ReopenLogs whenever a SIGUSR1 is received,
+// which is the signal that logrotate is usually configured to send.
+func (lifetime *Lifetime) HandleLogReopen() *Lifetime {
+	signals := make(chan os.Signal, 1)
+	lifetime.signalSource.Notify(signals, syscall.SIGUSR1)
+
+	go func() {
+		for range signals {
+			_ = lifetime.ReopenLogs()
+		}
+	}()
+	return lifetime
+}
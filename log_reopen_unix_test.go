@@ -0,0 +1,32 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLifetime_HandleLogReopen(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleLogReopen()
+
+	reopened := make(chan struct{}, 1)
+	lt.OnLogReopen("test", func() error {
+		reopened <- struct{}{}
+		return nil
+	})
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("could not send signal: %v", err)
+	}
+
+	select {
+	case <-reopened:
+	case <-time.After(time.Second):
+		t.Errorf("expected logs to be reopened")
+	}
+}
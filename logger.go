@@ -0,0 +1,107 @@
+package lifetime
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Field is a key/value pair that is attached to a log message.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger is used by the lifetime to write structured lifecycle log messages.
+// Adapters for common logging libraries are available in the lifetimezap and lifetimezerolog modules.
+type Logger interface {
+	// Debug logs a message useful when debugging.
+	Debug(msg string, fields ...Field)
+	// Info logs an informational message.
+	Info(msg string, fields ...Field)
+	// Warn logs a message about something that may need attention.
+	Warn(msg string, fields ...Field)
+	// Error logs a message about a failure.
+	Error(msg string, fields ...Field)
+}
+
+// WithLogger sets the logger used by the lifetime.
+// Defaults to a logger that writes to the standard library log package.
+func WithLogger(logger Logger) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.logger = logger
+	}
+}
+
+// NewStdLogger returns a Logger that writes to the given standard library logger.
+// If logger is nil the standard library log package functions are used.
+func NewStdLogger(logger *log.Logger) Logger {
+	return &stdLogger{logger: logger}
+}
+
+// stdLogger is an implementation of Logger that writes to a standard library logger.
+type stdLogger struct {
+	logger *log.Logger
+}
+
+// Debug logs a message useful when debugging.
+func (logger *stdLogger) Debug(msg string, fields ...Field) {
+	logger.print("debug", msg, fields)
+}
+
+// Info logs an informational message.
+func (logger *stdLogger) Info(msg string, fields ...Field) {
+	logger.print("info", msg, fields)
+}
+
+// Warn logs a message about something that may need attention.
+func (logger *stdLogger) Warn(msg string, fields ...Field) {
+	logger.print("warn", msg, fields)
+}
+
+// Error logs a message about a failure.
+func (logger *stdLogger) Error(msg string, fields ...Field) {
+	logger.print("error", msg, fields)
+}
+
+// print writes the message in the format `level: msg key=value key=value`.
+func (logger *stdLogger) print(level string, msg string, fields []Field) {
+	b := &strings.Builder{}
+	b.WriteString(level)
+	b.WriteString(": ")
+	b.WriteString(msg)
+	for _, field := range fields {
+		b.WriteString(" ")
+		b.WriteString(field.Key)
+		b.WriteString("=")
+		b.WriteString(fmt.Sprint(field.Value))
+	}
+
+	if logger.logger == nil {
+		log.Print(b.String())
+		return
+	}
+	logger.logger.Print(b.String())
+}
+
+// errorFields returns the log fields that describe the given error.
+// Service details are logged as separate fields rather than as part of the error message.
+func errorFields(err error) []Field {
+	serviceErr, ok := err.(*ServiceError)
+	if !ok {
+		return []Field{{Key: "error", Value: err.Error()}}
+	}
+	return append(
+		[]Field{{Key: "error", Value: serviceErr.Err.Error()}},
+		serviceFields(serviceErr.Service, serviceErr.Labels, serviceErr.Metadata)...,
+	)
+}
+
+// serviceFields returns the log fields that describe a service.
+func serviceFields(name string, labels Labels, metadata *ServiceMetadata) []Field {
+	fields := []Field{{Key: "service", Value: name}}
+	if len(labels) > 0 {
+		fields = append(fields, Field{Key: "labels", Value: labels.String()})
+	}
+	return append(fields, metadata.fields()...)
+}
@@ -0,0 +1,82 @@
+package lifetime
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// NewJSONLogger returns a Logger that writes each message to the given writer as a single line of JSON.
+// Each line contains the time, severity and message along with any fields, which is the format expected
+// by most log collectors including Google Cloud Logging.
+func NewJSONLogger(w io.Writer) Logger {
+	return &jsonLogger{w: w}
+}
+
+// jsonLogger is an implementation of Logger that writes JSON lines.
+type jsonLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Debug logs a message useful when debugging.
+func (logger *jsonLogger) Debug(msg string, fields ...Field) {
+	logger.write("DEBUG", msg, fields)
+}
+
+// Info logs an informational message.
+func (logger *jsonLogger) Info(msg string, fields ...Field) {
+	logger.write("INFO", msg, fields)
+}
+
+// Warn logs a message about something that may need attention.
+func (logger *jsonLogger) Warn(msg string, fields ...Field) {
+	logger.write("WARNING", msg, fields)
+}
+
+// Error logs a message about a failure.
+func (logger *jsonLogger) Error(msg string, fields ...Field) {
+	logger.write("ERROR", msg, fields)
+}
+
+// write writes the message as a single line of JSON.
+func (logger *jsonLogger) write(severity string, msg string, fields []Field) {
+	entry := make(map[string]interface{}, len(fields)+3)
+	for _, field := range fields {
+		entry[field.Key] = jsonValue(field.Value)
+	}
+	entry["time"] = time.Now().Format(time.RFC3339Nano)
+	entry["severity"] = severity
+	entry["message"] = msg
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		b = []byte(fmt.Sprintf(`{"severity":"ERROR","message":"could not marshal log entry: %s"}`, err))
+	}
+	b = append(b, '\n')
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	_, _ = logger.w.Write(b)
+}
+
+// jsonValue returns a representation of the given field value that can be marshalled to JSON.
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	case string, bool, int, int32, int64, uint, uint32, uint64, float32, float64, nil:
+		return v
+	default:
+		if _, err := json.Marshal(v); err != nil {
+			return fmt.Sprint(v)
+		}
+		return v
+	}
+}
@@ -0,0 +1,179 @@
+package lifetime
+
+import (
+	"sync"
+	"time"
+)
+
+// MaintenanceAction decides what happens to the services selected by a maintenance window.
+type MaintenanceAction int
+
+const (
+	// MaintenancePause pauses the selected services that implement Pauser and resumes them once the window ends.
+	MaintenancePause MaintenanceAction = iota
+	// MaintenanceStop stops the selected services and starts them again once the window ends.
+	// Note that some services, such as those wrapping an http.Server, cannot be started again once stopped.
+	MaintenanceStop
+)
+
+// String returns the name of the action.
+func (action MaintenanceAction) String() string {
+	switch action {
+	case MaintenancePause:
+		return "pause"
+	case MaintenanceStop:
+		return "stop"
+	default:
+		return "unknown"
+	}
+}
+
+// MaintenanceWindow describes a period of time during which selected services are paused or stopped.
+type MaintenanceWindow struct {
+	// Name is the name of the window, which is included in the events that are emitted.
+	Name string
+	// Start is when the window starts. A window that has already started starts straight away, and
+	// occurrences of a repeating window that have already ended are skipped.
+	Start time.Time
+	// Duration is how long the window lasts.
+	Duration time.Duration
+	// Every repeats the window at the given interval, such as every 24 hours for a nightly window.
+	// The window only happens once if it is 0.
+	Every time.Duration
+	// Selector selects the services with matching labels. See Labels.Matches.
+	Selector Labels
+	// Group, if set, only selects the services that were started through the group with the given name.
+	Group string
+	// Action is what happens to the selected services. Defaults to MaintenancePause.
+	Action MaintenanceAction
+}
+
+// ScheduleMaintenance schedules a maintenance window. The services that are running and selected by the window
+// when it starts are paused or stopped, and resumed or started again once it ends.
+// An EventMaintenanceStarted is emitted once the services have been paused or stopped, and an
+// EventMaintenanceEnded once they have been resumed or started again.
+// Services are not started again if the application is shutting down.
+// The returned func cancels the window, ending it straight away if it has already started.
+func (lifetime *Lifetime) ScheduleMaintenance(window MaintenanceWindow) func() {
+	cancelled := make(chan struct{})
+	go lifetime.runMaintenance(window, cancelled)
+	once := &sync.Once{}
+	return func() {
+		once.Do(func() {
+			close(cancelled)
+		})
+	}
+}
+
+// runMaintenance executes every occurrence of the given window until it is cancelled or the lifetime is done.
+func (lifetime *Lifetime) runMaintenance(window MaintenanceWindow, cancelled <-chan struct{}) {
+	start := window.Start
+	for window.Every > 0 && !start.Add(window.Duration).After(time.Now()) {
+		// Occurrences that have already ended are skipped.
+		start = start.Add(window.Every)
+	}
+	for {
+		timer := time.NewTimer(time.Until(start))
+		select {
+		case <-timer.C:
+		case <-cancelled:
+			timer.Stop()
+			return
+		case <-lifetime.ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		lifetime.maintain(window, start.Add(window.Duration), cancelled)
+		if window.Every <= 0 {
+			return
+		}
+		for !start.After(time.Now()) {
+			start = start.Add(window.Every)
+		}
+	}
+}
+
+// maintain pauses or stops the services selected by the window until the given time, after which they are
+// resumed or started again.
+func (lifetime *Lifetime) maintain(window MaintenanceWindow, end time.Time, cancelled <-chan struct{}) {
+	selected := lifetime.maintenanceServices(window)
+	var paused []*managedService
+	var stopped []*ServiceHandle
+	switch window.Action {
+	case MaintenanceStop:
+		// Wait must not return just because the selected services have been stopped.
+		done := lifetime.TrackTask()
+		defer done()
+		for _, managed := range selected {
+			go managed.stop()
+		}
+		for _, managed := range selected {
+			<-managed.exited
+			stopped = append(stopped, managed.handle)
+		}
+	default:
+		for _, managed := range selected {
+			if _, ok := managed.svc.(Pauser); !ok || managed.isPaused() {
+				continue
+			}
+			if err := managed.setPaused(true); err != nil {
+				managed.log(LevelWarn, "lifetime could not pause service", err)
+				continue
+			}
+			paused = append(paused, managed)
+		}
+	}
+
+	lifetime.emit(Event{Type: EventMaintenanceStarted, Maintenance: window.Name})
+	lifetime.log(LevelInfo, "lifetime "+string(EventMaintenanceStarted),
+		Field{Key: "maintenance", Value: window.Name},
+		Field{Key: "action", Value: window.Action.String()},
+		Field{Key: "services", Value: len(selected)},
+	)
+
+	timer := time.NewTimer(time.Until(end))
+	select {
+	case <-timer.C:
+	case <-cancelled:
+		timer.Stop()
+	case <-lifetime.ctx.Done():
+		timer.Stop()
+	}
+
+	for _, managed := range paused {
+		select {
+		case <-managed.exited:
+			continue
+		default:
+		}
+		if err := managed.setPaused(false); err != nil {
+			managed.log(LevelWarn, "lifetime could not resume service", err)
+		}
+	}
+	for _, handle := range stopped {
+		if lifetime.ctx.Err() != nil {
+			break
+		}
+		lifetime.start(handle)
+	}
+
+	lifetime.emit(Event{Type: EventMaintenanceEnded, Maintenance: window.Name})
+	lifetime.log(LevelInfo, "lifetime "+string(EventMaintenanceEnded), Field{Key: "maintenance", Value: window.Name})
+}
+
+// maintenanceServices returns the running services that are selected by the window.
+func (lifetime *Lifetime) maintenanceServices(window MaintenanceWindow) []*managedService {
+	lifetime.servicesMu.Lock()
+	defer lifetime.servicesMu.Unlock()
+	selected := make([]*managedService, 0)
+	for managed := range lifetime.services {
+		if window.Group != "" && (managed.group == nil || managed.group.name != window.Group) {
+			continue
+		}
+		if managed.labels.Matches(window.Selector) {
+			selected = append(selected, managed)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,100 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+func TestLifetime_ScheduleMaintenance(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	started := make(chan struct{}, 1)
+	ended := make(chan struct{}, 1)
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Maintenance != "nightly" {
+			return
+		}
+		switch event.Type {
+		case lifetime.EventMaintenanceStarted:
+			started <- struct{}{}
+		case lifetime.EventMaintenanceEnded:
+			ended <- struct{}{}
+		}
+	})
+
+	consumer := lt.Start(&pausableService{blockingService: newBlockingService()}, lifetime.WithLabels(lifetime.Labels{"role": "consumer"}))
+	api := lt.Start(&pausableService{blockingService: newBlockingService()}, lifetime.WithLabels(lifetime.Labels{"role": "api"}))
+
+	lt.ScheduleMaintenance(lifetime.MaintenanceWindow{
+		Name:     "nightly",
+		Start:    time.Now(),
+		Duration: time.Millisecond * 50,
+		Selector: lifetime.Labels{"role": "consumer"},
+	})
+
+	<-started
+	if !consumer.Paused() {
+		t.Errorf("expected the selected service to be paused")
+	}
+	if api.Paused() {
+		t.Errorf("expected other services not to be paused")
+	}
+	<-ended
+	if consumer.Paused() {
+		t.Errorf("expected the selected service to be resumed")
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLifetime_ScheduleMaintenance_Stop(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	started := make(chan struct{}, 1)
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventMaintenanceStarted {
+			started <- struct{}{}
+		}
+	})
+
+	svc := &restartableService{}
+	handle := lt.Group("ingest").Start(svc)
+	lt.Start(newBlockingService())
+
+	cancel := lt.ScheduleMaintenance(lifetime.MaintenanceWindow{
+		Name:     "reindex",
+		Start:    time.Now(),
+		Duration: time.Hour,
+		Group:    "ingest",
+		Action:   lifetime.MaintenanceStop,
+	})
+
+	<-started
+	select {
+	case <-handle.Done():
+	default:
+		t.Errorf("expected the service to be stopped")
+	}
+
+	ended := make(chan struct{})
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventMaintenanceEnded {
+			close(ended)
+		}
+	})
+	cancel()
+	<-ended
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if starts, _ := svc.counts(); starts != 2 {
+		t.Errorf("expected the service to be started again, got %d starts", starts)
+	}
+}
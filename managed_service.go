@@ -0,0 +1,242 @@
+package lifetime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// managedService holds the state of a single service that has been started by the lifetime.
+// Each managed service uses a single go routine while running. Stop bookkeeping is only done
+// once a stop has actually been requested.
+type managedService struct {
+	lifetime *Lifetime
+	handle   *ServiceHandle
+	svc      Service
+	name     string
+	labels   Labels
+	group    *Group
+	// seq is the order in which the service was started, relative to other services.
+	seq uint64
+	// ready is closed once the service has reported that it is ready.
+	ready chan struct{}
+	// started is closed just before the Start func of the service is executed.
+	// It is only used in deterministic mode.
+	started chan struct{}
+
+	// ctx is the context of the service. It is done once the service is stopped.
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+	// values are added to the context of the service. See WithContextValue.
+	values []contextValue
+
+	// stopWeight is the weight of the service when a stop budget is divided.
+	stopWeight float64
+	// nonCritical is true if the service can fail without triggering a shutdown.
+	nonCritical bool
+	// class is the class of the service, either given by WithClass or declared by the service.
+	class ServiceClass
+	// profiles are the profiles the service belongs to, if given with InProfiles. See RunProfile.
+	profiles []string
+	// metadata describes the service, if it has any. See WithMetadata.
+	metadata *ServiceMetadata
+	// idleTimeout is how long the service can go unused before it is stopped. See WithIdleStop.
+	idleTimeout time.Duration
+	// cascade is when the service is stopped relative to the other services. See WithCascadeOrder.
+	cascade CascadeOrder
+	// panicPolicy overrides the panic policy of the lifetime, if set. See WithServicePanicPolicy.
+	panicPolicy PanicPolicy
+
+	// dependencies are the services that must be ready before the service is executed. See After.
+	dependencies []*ServiceHandle
+	// priority decides which services are paused first when load is shed. See WithPriority.
+	priority int
+	// pause tracks whether the service has been paused. See Pauser.
+	pause pauseState
+
+	// comparable is true if the service is tracked in the lifetime's byService map.
+	comparable bool
+	// replacing is true while the service is replacing the current instance of its handle, which is only
+	// updated once the service is ready. It is guarded by mu once the service has been launched. See Swap.
+	replacing bool
+
+	// done is closed once the Start func of the service has returned.
+	done chan struct{}
+	// exited is closed once the service has finished execution and has been removed from the lifetime.
+	exited chan struct{}
+
+	mu sync.Mutex
+	// stopping is true once Stop has been called on the service.
+	stopping bool
+	// failed is true if Start returned an error before the service was stopped.
+	failed bool
+	// disabled is true if the service was never executed because it has been disabled.
+	disabled bool
+	// waiting is true while the service is waiting for its dependencies, during which Stop must not be called.
+	waiting bool
+	// swapErr is the error the service failed with while it was replacing another instance. See Swap.
+	swapErr error
+}
+
+// run executes the Start func of the service.
+// If Start returns an error before the service has been stopped, the error policies decide
+// what happens and the service exits without Stop being called. By default the error is reported
+// to the lifetime, other than errors from non-critical services which are logged.
+// If Start returns an error after the service has been stopped, the error is emitted as
+// an EventServiceLateError.
+func (managed *managedService) run() {
+	if managed.waiting && !managed.awaitDependencies() {
+		if managed.started != nil {
+			close(managed.started)
+		}
+		return
+	}
+	managed.emit(EventServiceStarted, nil)
+	go managed.waitForReady()
+
+	if managed.started != nil {
+		close(managed.started)
+	}
+
+	err := managed.execute()
+	if err == nil {
+		// The service will exit once it has been stopped.
+		close(managed.done)
+		return
+	}
+
+	err = managed.wrapError(err)
+
+	managed.mu.Lock()
+	stopping := managed.stopping
+	if !stopping {
+		managed.failed = true
+	}
+	managed.mu.Unlock()
+
+	if stopping {
+		// The stop func will handle the exit once done is closed.
+		managed.lifetime.recordFailure(err)
+		managed.emit(EventServiceLateError, err)
+		close(managed.done)
+		return
+	}
+	close(managed.done)
+
+	managed.emit(EventServiceFailed, err)
+	managed.handleFailure(err)
+	managed.exit()
+}
+
+// waitForReady closes the ready channel once the service reports that it is ready.
+// Services that do not implement Readier are ready as soon as they have been started.
+func (managed *managedService) waitForReady() {
+	readier, ok := managed.svc.(Readier)
+	if !ok {
+		close(managed.ready)
+		managed.lifetime.recovered(managed.name)
+		return
+	}
+	select {
+	case <-readier.Ready():
+		close(managed.ready)
+		managed.lifetime.recovered(managed.name)
+		managed.emit(EventServiceReady, nil)
+	case <-managed.done:
+	}
+}
+
+// stop executes the Stop func of the service and waits for the Start func to return.
+// It is safe to call stop multiple times, with subsequent calls waiting for the service to exit.
+func (managed *managedService) stop() {
+	managed.stopContext(context.Background())
+}
+
+// stopContext is the same as stop, but services that implement ContextStopper receive the given context.
+func (managed *managedService) stopContext(ctx context.Context) {
+	managed.mu.Lock()
+	if managed.stopping || managed.failed || managed.disabled {
+		managed.mu.Unlock()
+		<-managed.exited
+		return
+	}
+	managed.stopping = true
+	waiting := managed.waiting
+	managed.mu.Unlock()
+
+	managed.cancelFunc()
+	managed.emit(EventServiceStopping, nil)
+	start := time.Now()
+	cancelWatch := managed.lifetime.watchStop(managed)
+	if managed.lifetime.chaos != nil {
+		managed.lifetime.chaos.beforeStop(managed)
+	}
+	switch stopper, ok := managed.svc.(ContextStopper); {
+	case waiting:
+		// The service was never executed, so it is only waiting for its dependencies.
+	case ok:
+		stopper.StopContext(ctx)
+	default:
+		managed.svc.Stop()
+	}
+	<-managed.done
+	cancelWatch()
+	managed.lifetime.emit(Event{
+		Type:     EventServiceStopped,
+		Service:  managed.name,
+		Labels:   managed.labels,
+		Metadata: managed.metadata,
+		Duration: time.Since(start),
+	})
+	managed.log(LevelDebug, "lifetime "+string(EventServiceStopped), nil)
+	managed.exit()
+}
+
+// exit removes the service from the lifetime and marks it as finished.
+// It must be called exactly once per service.
+func (managed *managedService) exit() {
+	managed.cancelFunc()
+	managed.lifetime.remove(managed)
+	close(managed.exited)
+	managed.lifetime.serviceWg.Done()
+}
+
+// emit emits an event of the given type for the service and logs the transition.
+func (managed *managedService) emit(eventType EventType, err error) {
+	managed.lifetime.emit(Event{
+		Type:     eventType,
+		Service:  managed.name,
+		Labels:   managed.labels,
+		Metadata: managed.metadata,
+		Err:      err,
+	})
+
+	switch eventType {
+	case EventServiceLateError, EventServiceKilled:
+		managed.log(LevelWarn, "lifetime "+string(eventType), err)
+	case EventServiceDisabled:
+		managed.log(LevelInfo, "lifetime "+string(eventType), err)
+	case EventServiceFailed, EventServicePanicked:
+		// Failures and panics are logged by the error handler.
+	default:
+		managed.log(LevelDebug, "lifetime "+string(eventType), err)
+	}
+}
+
+// log writes a message about the service to the lifetime logger.
+func (managed *managedService) log(level Level, msg string, err error, fields ...Field) {
+	if !managed.lifetime.enabled(level) {
+		return
+	}
+	if err != nil {
+		fields = append(errorFields(err), fields...)
+	} else {
+		fields = append(serviceFields(managed.name, managed.labels, managed.metadata), fields...)
+	}
+	managed.lifetime.log(level, msg, fields...)
+}
+
+// wrapError wraps the given error in a ServiceError describing the service.
+func (managed *managedService) wrapError(err error) error {
+	return &ServiceError{Service: managed.name, Labels: managed.labels, Metadata: managed.metadata, Err: err}
+}
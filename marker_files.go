@@ -0,0 +1,94 @@
+package lifetime
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// WithReadyFile creates the file at the given path once the lifetime is ready, and removes it as soon as
+// a shutdown begins, for environments that use file based probes.
+// Any file left over from a previous run is removed when the lifetime is created.
+func WithReadyFile(path string) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.markerFiles().ready = path
+	}
+}
+
+// WithTerminatingFile creates the file at the given path as soon as a shutdown begins, so that sidecars
+// can find out that the application is terminating.
+// Any file left over from a previous run is removed when the lifetime is created.
+func WithTerminatingFile(path string) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.markerFiles().terminating = path
+	}
+}
+
+// markerFiles returns the marker files of the lifetime, creating them if required.
+func (lifetime *Lifetime) markerFiles() *markerFiles {
+	if lifetime.markers == nil {
+		lifetime.markers = &markerFiles{lifetime: lifetime}
+	}
+	return lifetime.markers
+}
+
+// markerFiles maintains the files created by WithReadyFile and WithTerminatingFile.
+type markerFiles struct {
+	lifetime    *Lifetime
+	ready       string
+	terminating string
+
+	mu sync.Mutex
+	// shuttingDown is true once a shutdown has begun, after which the ready file must not be created.
+	shuttingDown bool
+}
+
+// start removes any files left over from a previous run and starts maintaining the files.
+func (markers *markerFiles) start() {
+	markers.remove(markers.ready)
+	markers.remove(markers.terminating)
+	markers.lifetime.OnEvent(markers.handle)
+}
+
+// handle creates and removes files as the lifetime becomes ready and begins shutting down.
+func (markers *markerFiles) handle(event Event) {
+	markers.mu.Lock()
+	defer markers.mu.Unlock()
+
+	switch event.Type {
+	case EventReady:
+		if !markers.shuttingDown {
+			markers.create(markers.ready)
+		}
+	case EventShutdownStarted:
+		markers.shuttingDown = true
+		markers.remove(markers.ready)
+		markers.create(markers.terminating)
+	}
+}
+
+// create creates the file at the given path, if there is one.
+func (markers *markerFiles) create(path string) {
+	if path == "" {
+		return
+	}
+	if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+		markers.lifetime.log(LevelWarn, "lifetime could not create marker file",
+			Field{Key: "path", Value: path},
+			Field{Key: "error", Value: err},
+		)
+	}
+}
+
+// remove removes the file at the given path, if there is one.
+func (markers *markerFiles) remove(path string) {
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		markers.lifetime.log(LevelWarn, "lifetime could not remove marker file",
+			Field{Key: "path", Value: path},
+			Field{Key: "error", Value: err},
+		)
+	}
+}
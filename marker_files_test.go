@@ -0,0 +1,56 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestWithReadyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lifetime")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	ready, terminating := filepath.Join(dir, "ready"), filepath.Join(dir, "terminating")
+
+	// Leave files behind as if from a previous run.
+	_ = ioutil.WriteFile(ready, nil, 0644)
+	_ = ioutil.WriteFile(terminating, nil, 0644)
+
+	lt := lifetime.New(context.Background(),
+		lifetime.WithReadyFile(ready),
+		lifetime.WithTerminatingFile(terminating),
+	).HandleErrors(nil)
+	if exists(ready) || exists(terminating) {
+		t.Errorf("expected files from a previous run to be removed")
+	}
+
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type != lifetime.EventReady {
+			return
+		}
+		if !exists(ready) {
+			t.Errorf("expected ready file to exist once ready")
+		}
+		go lt.Shutdown()
+	})
+
+	lt.Start(newBlockingService())
+	_ = lt.Wait()
+
+	if exists(ready) {
+		t.Errorf("expected ready file to be removed once shutting down")
+	}
+	if !exists(terminating) {
+		t.Errorf("expected terminating file to exist once shutting down")
+	}
+}
@@ -0,0 +1,92 @@
+package lifetime
+
+import (
+	"strings"
+)
+
+// ServiceMetadata describes who owns a service and where to find out more about it.
+// It is included in the status, events and errors of the service so that on-call engineers
+// immediately know who to contact when it fails.
+type ServiceMetadata struct {
+	// Version is the version of the component the service runs.
+	Version string `json:"version,omitempty"`
+	// Owner is the team or person that owns the service.
+	Owner string `json:"owner,omitempty"`
+	// DocsURL is a link to the documentation or runbook of the service.
+	DocsURL string `json:"docsUrl,omitempty"`
+}
+
+// String returns the metadata in the format `owner=x,version=y,docs=z`, omitting empty values.
+func (metadata ServiceMetadata) String() string {
+	pairs := make([]string, 0, 3)
+	if metadata.Owner != "" {
+		pairs = append(pairs, "owner="+metadata.Owner)
+	}
+	if metadata.Version != "" {
+		pairs = append(pairs, "version="+metadata.Version)
+	}
+	if metadata.DocsURL != "" {
+		pairs = append(pairs, "docs="+metadata.DocsURL)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// fields returns the log fields that describe the metadata.
+func (metadata *ServiceMetadata) fields() []Field {
+	if metadata == nil {
+		return nil
+	}
+	fields := make([]Field, 0, 3)
+	if metadata.Owner != "" {
+		fields = append(fields, Field{Key: "owner", Value: metadata.Owner})
+	}
+	if metadata.Version != "" {
+		fields = append(fields, Field{Key: "version", Value: metadata.Version})
+	}
+	if metadata.DocsURL != "" {
+		fields = append(fields, Field{Key: "docs", Value: metadata.DocsURL})
+	}
+	return fields
+}
+
+// Describer can be implemented by a service to declare its metadata.
+type Describer interface {
+	// Metadata returns the metadata of the service.
+	Metadata() ServiceMetadata
+}
+
+// WithMetadata attaches the given metadata to the service.
+// Values that are set override those the service declares itself, see Describer.
+func WithMetadata(metadata ServiceMetadata) StartOption {
+	return func(managed *managedService) {
+		copied := metadata
+		managed.metadata = &copied
+	}
+}
+
+// resolveMetadata merges the metadata declared by the service into the metadata given with WithMetadata.
+func (managed *managedService) resolveMetadata() {
+	describer, ok := managed.svc.(Describer)
+	if !ok {
+		return
+	}
+	declared := describer.Metadata()
+	if managed.metadata == nil {
+		managed.metadata = &declared
+		return
+	}
+	if managed.metadata.Version == "" {
+		managed.metadata.Version = declared.Version
+	}
+	if managed.metadata.Owner == "" {
+		managed.metadata.Owner = declared.Owner
+	}
+	if managed.metadata.DocsURL == "" {
+		managed.metadata.DocsURL = declared.DocsURL
+	}
+}
+
+// Metadata returns the metadata of the service, or nil if it has none.
+func (handle *ServiceHandle) Metadata() *ServiceMetadata {
+	return handle.describe().metadata
+}
@@ -0,0 +1,58 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+)
+
+// describedService is a blocking service that declares its own metadata.
+type describedService struct {
+	*blockingService
+}
+
+func (s describedService) Metadata() lifetime.ServiceMetadata {
+	return lifetime.ServiceMetadata{Version: "1.2.0", Owner: "search", DocsURL: "https://example.com/search"}
+}
+
+func TestLifetime_Metadata(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	handle := lt.Start(describedService{newBlockingService()}, lifetime.WithName("search"),
+		lifetime.WithMetadata(lifetime.ServiceMetadata{Owner: "platform"}))
+
+	exp := lifetime.ServiceMetadata{Version: "1.2.0", Owner: "platform", DocsURL: "https://example.com/search"}
+	if got := handle.Metadata(); got == nil || *got != exp {
+		t.Errorf("expected metadata %+v, got %+v", exp, got)
+	}
+	status := lt.Status()
+	if len(status.Services) != 1 || status.Services[0].Metadata == nil || *status.Services[0].Metadata != exp {
+		t.Errorf("expected the status to include metadata %+v, got %+v", exp, status.Services)
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+}
+
+func TestServiceError_Metadata(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	var failed lifetime.Event
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceFailed {
+			failed = event
+		}
+	})
+	lt.Start(&failingService{err: errors.New("boom")}, lifetime.WithName("indexer"),
+		lifetime.WithMetadata(lifetime.ServiceMetadata{Owner: "data", DocsURL: "https://example.com/indexer"}))
+	_ = lt.Wait()
+
+	if failed.Metadata == nil || failed.Metadata.Owner != "data" {
+		t.Errorf("expected the event to include metadata, got %+v", failed.Metadata)
+	}
+	exp := "service indexer: boom (owner=data,docs=https://example.com/indexer)"
+	if failed.Err == nil || failed.Err.Error() != exp {
+		t.Errorf("expected error %q, got %v", exp, failed.Err)
+	}
+}
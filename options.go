@@ -0,0 +1,60 @@
+package lifetime
+
+import (
+	"os"
+)
+
+// Option is used to configure a Lifetime when it is created.
+type Option func(lifetime *Lifetime)
+
+// WithErrorQueueSize sets the number of errors that can be queued for the error handler.
+// Reporting an error never blocks. If the queue is full the error is dropped and counted,
+// and the number of dropped errors can be retrieved with DroppedErrors.
+// Defaults to 64.
+func WithErrorQueueSize(size int) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.errors = newErrorQueue(size)
+	}
+}
+
+// WithImmediateShutdownReturn changes the behaviour of an immediate shutdown so that rather than calling
+// os.Exit, Wait returns ErrImmediateShutdownSignalReceived straight away.
+// This leaves the final exit decision, and any deferred cleanup, to the caller.
+func WithImmediateShutdownReturn() Option {
+	return func(lifetime *Lifetime) {
+		lifetime.immediateReturn = true
+	}
+}
+
+// WithSignals sets the signals that are listened for when HandleSignals is called without any signals,
+// including when it is called by Init.
+// Defaults to SIGINT, SIGTERM and SIGKILL.
+func WithSignals(sigs ...os.Signal) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.signals = sigs
+	}
+}
+
+// defaultSignalEscalation is the number of shutdown signals that cause an immediate shutdown by default.
+const defaultSignalEscalation = 2
+
+// WithSignalEscalation sets the number of shutdown signals that must be received before an
+// ErrImmediateShutdownSignalReceived is sent rather than an ErrShutdownSignalReceived.
+// A count of 0 disables the escalation, so repeated signals are ignored. A SIGKILL always
+// causes an immediate shutdown.
+// Defaults to 2.
+func WithSignalEscalation(count int) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.signalEscalation = count
+	}
+}
+
+// WithStopConcurrency limits how many services are stopped at the same time during a shutdown, so that
+// stopping many services at once does not overwhelm a downstream system they all flush to.
+// Services are stopped in batches of at most the given size, and each batch starts once the previous one
+// has completed. Defaults to 0, which stops every service at once.
+func WithStopConcurrency(concurrency int) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.stopConcurrency = concurrency
+	}
+}
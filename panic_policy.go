@@ -0,0 +1,121 @@
+package lifetime
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicPolicy is what the lifetime does when the Start func of a service panics.
+type PanicPolicy int
+
+const (
+	// PanicCrash lets the panic continue, which crashes the application.
+	PanicCrash PanicPolicy = iota + 1
+	// PanicShutdown recovers the panic and reports it to the error handler as a PanicError,
+	// which triggers a graceful shutdown by default. Error policies are not applied.
+	PanicShutdown
+	// PanicRestart recovers the panic and starts the service again after the retry delay.
+	// See WithErrorRetryDelay.
+	PanicRestart
+)
+
+// String returns the name of the policy.
+func (policy PanicPolicy) String() string {
+	switch policy {
+	case PanicShutdown:
+		return "shutdown"
+	case PanicRestart:
+		return "restart"
+	default:
+		return "crash"
+	}
+}
+
+// WithPanicPolicy sets what happens when the Start func of a service panics.
+// Defaults to PanicCrash. Use WithServicePanicPolicy to override it for a single service.
+// Regardless of the policy, an EventServicePanicked containing a PanicError is emitted first.
+func WithPanicPolicy(policy PanicPolicy) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.panicPolicy = policy
+	}
+}
+
+// WithServicePanicPolicy sets what happens when the Start func of the service panics. See WithPanicPolicy.
+func WithServicePanicPolicy(policy PanicPolicy) StartOption {
+	return func(managed *managedService) {
+		managed.panicPolicy = policy
+	}
+}
+
+// PanicError is the error used when the Start func of a service panics.
+type PanicError struct {
+	// Value is the value the service panicked with.
+	Value interface{}
+	// Stack is the stack trace of the go routine that panicked.
+	Stack []byte
+}
+
+// Error returns the panic value.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// policy returns the panic policy of the service.
+func (managed *managedService) policy() PanicPolicy {
+	if managed.panicPolicy != 0 {
+		return managed.panicPolicy
+	}
+	if managed.lifetime.panicPolicy != 0 {
+		return managed.lifetime.panicPolicy
+	}
+	return PanicCrash
+}
+
+// execute executes the Start func of the service.
+// If it panics, an EventServicePanicked is emitted and the panic is either continued or returned as
+// a PanicError, depending on the panic policy of the service.
+func (managed *managedService) execute() (err error) {
+	defer func() {
+		value := recover()
+		if value == nil {
+			return
+		}
+		panicErr := &PanicError{Value: value, Stack: debug.Stack()}
+		managed.log(LevelError, "lifetime service panicked", managed.wrapError(panicErr),
+			Field{Key: "policy", Value: managed.policy().String()},
+			Field{Key: "stack", Value: string(panicErr.Stack)},
+		)
+		managed.emit(EventServicePanicked, managed.wrapError(panicErr))
+		if managed.policy() == PanicCrash {
+			panic(value)
+		}
+		err = panicErr
+	}()
+
+	if starter, ok := managed.svc.(ContextStarter); ok {
+		return starter.StartContext(managed.ctx)
+	}
+	return managed.svc.Start()
+}
+
+// panicAction returns the action to take if the given error is the result of a recovered panic.
+// It returns false if the error is not a PanicError.
+func (managed *managedService) panicAction(err error) (ErrorAction, bool) {
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		return ActionShutdown, false
+	}
+	if managed.policy() == PanicRestart {
+		return ActionRetry, true
+	}
+	return ActionShutdown, true
+}
@@ -0,0 +1,98 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync/atomic"
+	"testing"
+)
+
+// panickingService panics when started, until it has been started the given number of times.
+type panickingService struct {
+	*blockingService
+	panics int32
+	starts int32
+}
+
+// Start panics or blocks until the service is stopped.
+func (s *panickingService) Start() error {
+	if atomic.AddInt32(&s.starts, 1) <= s.panics {
+		panic(errors.New("nil map"))
+	}
+	return s.blockingService.Start()
+}
+
+func TestWithPanicPolicy_Shutdown(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithPanicPolicy(lifetime.PanicShutdown))
+	// Wait doesn't wait for a custom handler, so the handled error is passed back on a channel.
+	handled := make(chan error, 1)
+	lt.HandleErrors(func(e error) {
+		select {
+		case handled <- e:
+		default:
+		}
+		lt.Shutdown()
+	})
+
+	var panicked *lifetime.PanicError
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServicePanicked {
+			errors.As(event.Err, &panicked)
+		}
+	})
+	lt.Start(&panickingService{blockingService: newBlockingService(), panics: 1}, lifetime.WithName("worker"))
+
+	_ = lt.Wait()
+	err := <-handled
+	var panicErr *lifetime.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected a PanicError, got %v", err)
+	}
+	if panicErr.Unwrap() == nil || panicErr.Unwrap().Error() != "nil map" {
+		t.Errorf("expected the panic value to be unwrapped, got %v", panicErr.Unwrap())
+	}
+	if len(panicErr.Stack) == 0 {
+		t.Errorf("expected the stack to be recorded")
+	}
+	if panicked != panicErr {
+		t.Errorf("expected the panic to be emitted as an event")
+	}
+}
+
+func TestWithServicePanicPolicy_Restart(t *testing.T) {
+	lt := lifetime.New(context.Background(),
+		lifetime.WithPanicPolicy(lifetime.PanicShutdown),
+		lifetime.WithErrorRetryDelay(0),
+	)
+
+	var starts int32
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceStarted && atomic.AddInt32(&starts, 1) == 3 {
+			go lt.Shutdown()
+		}
+	})
+	lt.Start(&panickingService{blockingService: newBlockingService(), panics: 2},
+		lifetime.WithServicePanicPolicy(lifetime.PanicRestart),
+	)
+
+	if err := lt.Wait(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if got := atomic.LoadInt32(&starts); got != 3 {
+		t.Errorf("expected the service to be started 3 times, got %d", got)
+	}
+}
+
+func TestPanicPolicy_String(t *testing.T) {
+	tests := map[lifetime.PanicPolicy]string{
+		lifetime.PanicCrash:    "crash",
+		lifetime.PanicShutdown: "shutdown",
+		lifetime.PanicRestart:  "restart",
+	}
+	for policy, exp := range tests {
+		if got := policy.String(); got != exp {
+			t.Errorf("expected %q, got %q", exp, got)
+		}
+	}
+}
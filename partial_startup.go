@@ -0,0 +1,59 @@
+package lifetime
+
+// Health is the overall health of the application.
+type Health string
+
+const (
+	// HealthStarting is used while the startup phase has not completed.
+	HealthStarting Health = "starting"
+	// HealthOK is used when the application is ready and no services are degraded.
+	HealthOK Health = "ok"
+	// HealthDegraded is used when the application is ready but is running without some of its services.
+	HealthDegraded Health = "degraded"
+	// HealthShuttingDown is used once a shutdown has been triggered.
+	HealthShuttingDown Health = "shutting-down"
+)
+
+// WithPartialStartup lets the application run without the NonCritical services that fail, rather than just
+// logging their failure. Each failed service is skipped and recorded as degraded, so that the health of the
+// application is reported as HealthDegraded by Status, the probe handler and metrics. See Health.
+// A degraded service is no longer degraded once it has been started again and is ready.
+func WithPartialStartup() Option {
+	return func(lifetime *Lifetime) {
+		lifetime.partialStartup = true
+	}
+}
+
+// Health returns the overall health of the application.
+func (lifetime *Lifetime) Health() Health {
+	if lifetime.ctx.Err() != nil {
+		return HealthShuttingDown
+	}
+	if !lifetime.isStarted() {
+		return HealthStarting
+	}
+	if lifetime.IsDegraded() {
+		return HealthDegraded
+	}
+	return HealthOK
+}
+
+// IsDegraded returns true if any services have failed with an error classified as ActionDegrade, or have
+// been skipped because of WithPartialStartup.
+func (lifetime *Lifetime) IsDegraded() bool {
+	lifetime.degradedMu.Lock()
+	defer lifetime.degradedMu.Unlock()
+	return len(lifetime.degraded) > 0
+}
+
+// recovered removes the named service from the degraded services, if it was degraded.
+func (lifetime *Lifetime) recovered(name string) {
+	lifetime.degradedMu.Lock()
+	_, ok := lifetime.degraded[name]
+	delete(lifetime.degraded, name)
+	lifetime.degradedMu.Unlock()
+
+	if ok {
+		lifetime.log(LevelInfo, "lifetime service recovered", Field{Key: "service", Value: name})
+	}
+}
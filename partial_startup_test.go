@@ -0,0 +1,107 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// readyService is a blocking service that is ready as soon as it is started.
+type readyService struct {
+	*blockingService
+}
+
+// Ready returns a closed channel.
+func (s readyService) Ready() <-chan struct{} {
+	ready := make(chan struct{})
+	close(ready)
+	return ready
+}
+
+func TestWithPartialStartup(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithPartialStartup()).HandleErrors(nil)
+
+	degraded := make(chan struct{})
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceDegraded && event.Service == "search" {
+			close(degraded)
+		}
+	})
+	lt.Start(newBlockingService(), lifetime.WithName("api"))
+	lt.Start(&failingService{err: errors.New("index unavailable")}, lifetime.WithName("search"), lifetime.NonCritical())
+
+	waitErr := make(chan error)
+	go func() {
+		waitErr <- lt.Wait()
+	}()
+	for _, ch := range []<-chan struct{}{degraded, lt.Ready()} {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatalf("expected the lifetime to be ready and degraded")
+		}
+	}
+
+	if health := lt.Health(); health != lifetime.HealthDegraded {
+		t.Errorf("expected health to be %s, got %s", lifetime.HealthDegraded, health)
+	}
+	status := lt.Status()
+	if status.Health != lifetime.HealthDegraded || status.Degraded["search"] == "" {
+		t.Errorf("expected search to be reported as degraded, got %+v", status)
+	}
+
+	rec := httptest.NewRecorder()
+	lt.ProbeHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "degraded\n" {
+		t.Errorf("expected readyz to report degraded, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	lt.Shutdown()
+	if err := <-waitErr; err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if health := lt.Health(); health != lifetime.HealthShuttingDown {
+		t.Errorf("expected health to be %s, got %s", lifetime.HealthShuttingDown, health)
+	}
+}
+
+func TestWithPartialStartup_Recovered(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithPartialStartup()).HandleErrors(nil)
+
+	degraded := make(chan struct{})
+	lt.OnEvent(func(event lifetime.Event) {
+		switch event.Type {
+		case lifetime.EventServiceDegraded:
+			close(degraded)
+		case lifetime.EventServiceReady:
+			go lt.Shutdown()
+		}
+	})
+	lt.Start(&failingService{err: errors.New("index unavailable")}, lifetime.WithName("search"), lifetime.NonCritical())
+	<-degraded
+	if !lt.IsDegraded() {
+		t.Fatalf("expected the lifetime to be degraded")
+	}
+
+	lt.Start(readyService{newBlockingService()}, lifetime.WithName("search"))
+	if err := lt.Wait(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if lt.IsDegraded() {
+		t.Errorf("expected search to have recovered")
+	}
+}
+
+func TestNonCritical_NotDegraded(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(&failingService{err: errors.New("index unavailable")}, lifetime.NonCritical())
+	go lt.Shutdown()
+	_ = lt.Wait()
+	if lt.IsDegraded() {
+		t.Errorf("expected non-critical failures not to degrade the lifetime without WithPartialStartup")
+	}
+}
@@ -0,0 +1,105 @@
+package lifetime
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrNotPausable is returned when pausing or resuming a service that does not implement Pauser.
+	ErrNotPausable = errors.New("service cannot be paused")
+	// ErrServiceNotRunning is returned when pausing or resuming a service that is not running.
+	ErrServiceNotRunning = errors.New("service is not running")
+)
+
+// Pauser can be implemented by a Service that can temporarily stop taking on new work without being stopped,
+// such as a consumer that stops pulling messages. A paused service is still running and may be stopped
+// without being resumed first.
+type Pauser interface {
+	// Pause stops the service from taking on new work.
+	Pause() error
+	// Resume allows the service to take on new work again.
+	Resume() error
+}
+
+// pauseState tracks whether a service has been paused.
+type pauseState struct {
+	mu     sync.Mutex
+	paused bool
+}
+
+// Pause pauses the service. See Pauser.
+// Pausing a service that is already paused does nothing.
+func (handle *ServiceHandle) Pause() error {
+	managed, err := handle.pauser()
+	if err != nil {
+		return err
+	}
+	return managed.setPaused(true)
+}
+
+// Resume resumes a paused service. See Pauser.
+// Resuming a service that isn't paused does nothing.
+func (handle *ServiceHandle) Resume() error {
+	managed, err := handle.pauser()
+	if err != nil {
+		return err
+	}
+	return managed.setPaused(false)
+}
+
+// Paused returns true if the service is currently paused.
+func (handle *ServiceHandle) Paused() bool {
+	managed := handle.current()
+	return managed != nil && managed.isPaused()
+}
+
+// pauser returns the current instance of the service if it can be paused.
+func (handle *ServiceHandle) pauser() (*managedService, error) {
+	managed := handle.current()
+	if managed == nil {
+		return nil, ErrServiceNotRunning
+	}
+	select {
+	case <-managed.exited:
+		return nil, ErrServiceNotRunning
+	default:
+	}
+	if _, ok := managed.svc.(Pauser); !ok {
+		return nil, managed.wrapError(ErrNotPausable)
+	}
+	return managed, nil
+}
+
+// setPaused pauses or resumes the service, emitting an EventServicePaused or EventServiceResumed if
+// the state of the service changed.
+func (managed *managedService) setPaused(paused bool) error {
+	managed.pause.mu.Lock()
+	defer managed.pause.mu.Unlock()
+	if managed.pause.paused == paused {
+		return nil
+	}
+
+	pauser := managed.svc.(Pauser)
+	eventType := EventServicePaused
+	var err error
+	if paused {
+		err = pauser.Pause()
+	} else {
+		eventType = EventServiceResumed
+		err = pauser.Resume()
+	}
+	if err != nil {
+		return managed.wrapError(err)
+	}
+	managed.pause.paused = paused
+	managed.emit(eventType, nil)
+	return nil
+}
+
+// isPaused returns true if the service is currently paused.
+func (managed *managedService) isPaused() bool {
+	managed.pause.mu.Lock()
+	defer managed.pause.mu.Unlock()
+	return managed.pause.paused
+}
@@ -0,0 +1,106 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+)
+
+// pausableService is a service that records whether it is paused.
+type pausableService struct {
+	*blockingService
+	mu     sync.Mutex
+	paused bool
+	calls  int
+}
+
+func (s *pausableService) Pause() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused = true
+	s.calls++
+	return nil
+}
+
+func (s *pausableService) Resume() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused = false
+	s.calls++
+	return nil
+}
+
+func (s *pausableService) state() (bool, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paused, s.calls
+}
+
+func TestServiceHandle_Pause(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	var events []lifetime.EventType
+	var eventsMu sync.Mutex
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServicePaused || event.Type == lifetime.EventServiceResumed {
+			eventsMu.Lock()
+			events = append(events, event.Type)
+			eventsMu.Unlock()
+		}
+	})
+
+	svc := &pausableService{blockingService: newBlockingService()}
+	handle := lt.Start(svc, lifetime.WithName("consumer"))
+
+	for i := 0; i < 2; i++ {
+		if err := handle.Pause(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if paused, calls := svc.state(); !paused || calls != 1 {
+		t.Errorf("expected the service to be paused once, got paused %v after %d calls", paused, calls)
+	}
+	if !handle.Paused() {
+		t.Errorf("expected the handle to report the service as paused")
+	}
+	if status := lt.Status(); !status.Services[0].Paused {
+		t.Errorf("expected the status to report the service as paused")
+	}
+
+	if err := handle.Resume(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if paused, _ := svc.state(); paused || handle.Paused() {
+		t.Errorf("expected the service to be resumed")
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	exp := []lifetime.EventType{lifetime.EventServicePaused, lifetime.EventServiceResumed}
+	if len(events) != len(exp) || events[0] != exp[0] || events[1] != exp[1] {
+		t.Errorf("expected events %v, got %v", exp, events)
+	}
+	if err := handle.Pause(); !errors.Is(err, lifetime.ErrServiceNotRunning) {
+		t.Errorf("expected ErrServiceNotRunning, got %v", err)
+	}
+}
+
+func TestServiceHandle_Pause_NotPausable(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	handle := lt.Start(newBlockingService())
+	if err := handle.Pause(); !errors.Is(err, lifetime.ErrNotPausable) {
+		t.Errorf("expected ErrNotPausable, got %v", err)
+	}
+	if err := lt.Lazy(newBlockingService()).Resume(); !errors.Is(err, lifetime.ErrServiceNotRunning) {
+		t.Errorf("expected ErrServiceNotRunning, got %v", err)
+	}
+	lt.Shutdown()
+	_ = lt.Wait()
+}
@@ -0,0 +1,131 @@
+package lifetime
+
+import (
+	"time"
+)
+
+// ShutdownPlan describes what would happen if a shutdown was triggered now.
+type ShutdownPlan struct {
+	// DrainDelay is how long services keep running once the shutdown is triggered. See WithDrainDelay.
+	DrainDelay time.Duration `json:"drainDelay"`
+	// Timeout is how long the shutdown can take, or 0 if there is no timeout. See WithShutdownTimeout.
+	Timeout time.Duration `json:"timeout"`
+	// Steps contains the services in the order they would be stopped. The services in each step are
+	// stopped concurrently, and each step starts once the previous one has completed.
+	// In deterministic mode the order is chosen by the seed when the shutdown happens, so the steps
+	// are shown in the order the services were started.
+	Steps [][]PlannedService `json:"steps"`
+	// Stages contains the shutdown stages in the order they would be executed.
+	Stages []PlannedStage `json:"stages"`
+}
+
+// PlannedService describes how a single service would be stopped.
+type PlannedService struct {
+	// ID identifies the service within the plan, as the names of services are not unique.
+	ID uint64 `json:"id"`
+	// Name is the name of the service.
+	Name string `json:"name"`
+	// Labels are the labels attached to the service.
+	Labels Labels `json:"labels,omitempty"`
+	// Group is the name of the group the service belongs to, if any.
+	Group string `json:"group,omitempty"`
+	// Class is the name of the class of the service. See ServiceClass.
+	Class string `json:"class"`
+	// Weight is the weight of the service when a stop budget is divided. See WithStopWeight.
+	Weight float64 `json:"weight"`
+	// Priority is the priority of the service when load is shed. See WithPriority.
+	Priority int `json:"priority,omitempty"`
+	// After contains the IDs of the running services that the service depends on. See After.
+	After []uint64 `json:"after,omitempty"`
+	// Budget is the least time the service would be given to stop if WithStopBudget is used,
+	// assuming every earlier step uses all of its time.
+	Budget time.Duration `json:"budget,omitempty"`
+}
+
+// PlannedStage describes a single shutdown stage.
+type PlannedStage struct {
+	// Stage is the stage.
+	Stage Stage `json:"stage"`
+	// Funcs contains the names of the funcs registered against the stage.
+	Funcs []string `json:"funcs"`
+}
+
+// PlanShutdown returns what would happen if a shutdown was triggered now, without stopping anything.
+func (lifetime *Lifetime) PlanShutdown() ShutdownPlan {
+	plan := ShutdownPlan{
+		DrainDelay: lifetime.drainDelay,
+		Timeout:    lifetime.shutdownTimeout,
+		Steps:      make([][]PlannedService, 0),
+		Stages:     make([]PlannedStage, 0, len(shutdownStages)),
+	}
+
+	lifetime.servicesMu.Lock()
+	running := make([]*managedService, 0, len(lifetime.services))
+	for managed := range lifetime.services {
+		running = append(running, managed)
+	}
+	lifetime.servicesMu.Unlock()
+	sortBySeq(running)
+	isRunning := make(map[*managedService]bool, len(running))
+	for _, managed := range running {
+		isRunning[managed] = true
+	}
+
+	var budget *stopBudget
+	now := time.Now()
+	if lifetime.stopBudget && lifetime.shutdownTimeout > 0 {
+		budget = newStopBudget(now.Add(lifetime.shutdownTimeout), running)
+		// Services are not stopped until the drain delay has passed.
+		now = now.Add(lifetime.drainDelay)
+	}
+
+	for _, step := range lifetime.stopSteps(running, false) {
+		planned := make([]PlannedService, len(step))
+		var deadlines []time.Time
+		if budget != nil {
+			deadlines = budget.allocateAt(now, step)
+		}
+		for i, managed := range step {
+			planned[i] = PlannedService{
+				ID:       managed.seq,
+				Name:     managed.name,
+				Labels:   managed.labels,
+				Class:    managed.class.String(),
+				Weight:   managed.weight(),
+				Priority: managed.priority,
+			}
+			for _, dependency := range managed.dependencies {
+				if current := dependency.current(); current != nil && isRunning[current] {
+					planned[i].After = append(planned[i].After, current.seq)
+				}
+			}
+			if managed.group != nil {
+				planned[i].Group = managed.group.name
+			}
+			if deadlines != nil {
+				planned[i].Budget = deadlines[i].Sub(now)
+			}
+		}
+		if deadlines != nil {
+			// Assume the step uses all of its time.
+			for _, deadline := range deadlines {
+				if deadline.After(now) {
+					now = deadline
+				}
+			}
+		}
+		plan.Steps = append(plan.Steps, planned)
+	}
+
+	lifetime.stagesMu.Lock()
+	for _, stage := range shutdownStages {
+		planned := PlannedStage{Stage: stage, Funcs: make([]string, 0)}
+		for _, f := range lifetime.stages[stage] {
+			planned.Funcs = append(planned.Funcs, f.name)
+		}
+		plan.Stages = append(plan.Stages, planned)
+	}
+	lifetime.stagesMu.Unlock()
+
+	return plan
+}
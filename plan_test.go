@@ -0,0 +1,46 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+func TestLifetime_PlanShutdown(t *testing.T) {
+	lt := lifetime.New(context.Background(),
+		lifetime.WithShutdownTimeout(time.Second*10),
+		lifetime.WithStopBudget(),
+	).HandleErrors(nil)
+
+	lt.Start(newBlockingService(), lifetime.WithName("api"))
+	lt.Group("workers").Start(newBlockingService(), lifetime.WithName("worker"), lifetime.WithStopWeight(4))
+	lt.RegisterDrain("outbox", func(ctx context.Context) error {
+		return nil
+	})
+
+	plan := lt.PlanShutdown()
+
+	if plan.Timeout != time.Second*10 {
+		t.Errorf("expected timeout of 10s, got %s", plan.Timeout)
+	}
+	if len(plan.Steps) != 1 || len(plan.Steps[0]) != 2 {
+		t.Fatalf("expected a single step with 2 services, got %+v", plan.Steps)
+	}
+	api, worker := plan.Steps[0][0], plan.Steps[0][1]
+	if api.Name != "api" || worker.Name != "worker" || worker.Group != "workers" {
+		t.Errorf("unexpected services: %+v", plan.Steps[0])
+	}
+	if api.Budget != time.Second*2 || worker.Budget != time.Second*8 {
+		t.Errorf("expected budgets of 2s and 8s, got %s and %s", api.Budget, worker.Budget)
+	}
+	if len(plan.Stages) != 3 || plan.Stages[1].Stage != lifetime.StageDrain || plan.Stages[1].Funcs[0] != "outbox" {
+		t.Errorf("unexpected stages: %+v", plan.Stages)
+	}
+	if lt.ShuttingDown() {
+		t.Errorf("expected planning not to trigger a shutdown")
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+}
@@ -0,0 +1,77 @@
+package lifetime
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// Preset is a named bundle of options tuned for a particular platform.
+type Preset string
+
+const (
+	// PresetCloudRun is tuned for Cloud Run, App Runner and similar serverless container platforms,
+	// which send a single SIGTERM and allow 10 seconds before the container is killed.
+	//  - Only SIGTERM is listened for.
+	//  - The whole shutdown must complete within 10 seconds, and the time is divided among the services
+	//    being stopped so that no single slow service can use all of it. See WithStopBudget.
+	//  - Logs are written to stdout as JSON.
+	// As with every other configuration, readiness is reported as false as soon as the shutdown begins.
+	PresetCloudRun Preset = "cloud-run"
+	// PresetECS is tuned for ECS and Fargate, which send a SIGTERM and then a SIGKILL 30 seconds later
+	// unless the stop timeout of the task has been changed.
+	//  - Only SIGTERM is listened for.
+	//  - The whole shutdown must complete within 28 seconds, leaving time to flush logs before the SIGKILL.
+	PresetECS Preset = "ecs"
+	// PresetKubernetes is tuned for Kubernetes with the default terminationGracePeriodSeconds of 30.
+	// Endpoints are removed at the same time as the SIGTERM is sent, so traffic can continue to arrive
+	// for a few seconds while the removal propagates.
+	//  - Only SIGTERM is listened for.
+	//  - Services keep running for 5 seconds after readiness is reported as false. If your pods use a
+	//    preStop hook that sleeps, the delay overlaps with it and WithDrainDelay(0) can be used.
+	//  - The whole shutdown, including the drain delay, must complete within 25 seconds.
+	PresetKubernetes Preset = "kubernetes"
+)
+
+// WithPreset applies the options that make up the given preset.
+// Options given after WithPreset override those set by the preset.
+// Unknown presets are logged and ignored.
+func WithPreset(preset Preset) Option {
+	return func(lifetime *Lifetime) {
+		opts, ok := preset.options()
+		if !ok {
+			lifetime.log(LevelWarn, "lifetime unknown preset", Field{Key: "preset", Value: string(preset)})
+			return
+		}
+		for _, opt := range opts {
+			opt(lifetime)
+		}
+	}
+}
+
+// options returns the options that make up the preset.
+// The bool is false if the preset is unknown.
+func (preset Preset) options() ([]Option, bool) {
+	switch preset {
+	case PresetCloudRun:
+		return []Option{
+			WithSignals(syscall.SIGTERM),
+			WithShutdownTimeout(time.Second * 10),
+			WithStopBudget(),
+			WithLogger(NewJSONLogger(os.Stdout)),
+		}, true
+	case PresetECS:
+		return []Option{
+			WithSignals(syscall.SIGTERM),
+			WithShutdownTimeout(time.Second * 28),
+		}, true
+	case PresetKubernetes:
+		return []Option{
+			WithSignals(syscall.SIGTERM),
+			WithDrainDelay(time.Second * 5),
+			WithShutdownTimeout(time.Second * 25),
+		}, true
+	default:
+		return nil, false
+	}
+}
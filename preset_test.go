@@ -0,0 +1,136 @@
+package lifetime_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+// stuckService is a service that never stops.
+type stuckService struct{}
+
+func (s *stuckService) Start() error {
+	select {}
+}
+
+func (s *stuckService) Stop() {}
+
+func TestWithShutdownTimeout(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithShutdownTimeout(time.Millisecond*50)).HandleErrors(nil)
+	lt.Start(&stuckService{})
+	lt.Start(newBlockingService())
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != lifetime.ErrShutdownTimeout {
+		t.Errorf("expected ErrShutdownTimeout, got %v", err)
+	}
+}
+
+// killableService is a service that only stops once it has been killed.
+type killableService struct {
+	killed chan struct{}
+}
+
+func (s *killableService) Start() error {
+	<-s.killed
+	return nil
+}
+
+func (s *killableService) Stop() {
+	<-s.killed
+}
+
+func (s *killableService) Kill() {
+	close(s.killed)
+}
+
+func TestWithShutdownTimeout_Kill(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithShutdownTimeout(time.Millisecond*50)).HandleErrors(nil)
+	svc := &killableService{killed: make(chan struct{})}
+	lt.Start(svc, lifetime.WithName("legacy"))
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != lifetime.ErrShutdownTimeout {
+		t.Errorf("expected ErrShutdownTimeout, got %v", err)
+	}
+	select {
+	case <-svc.killed:
+	default:
+		t.Errorf("expected the service to be killed")
+	}
+	if code := lt.ExitCode(); code != lifetime.ExitCodeShutdownTimeout {
+		t.Errorf("expected exit code %d, got %d", lifetime.ExitCodeShutdownTimeout, code)
+	}
+}
+
+func TestNewJSONLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := lifetime.NewJSONLogger(buf)
+	logger.Warn("something happened", lifetime.Field{Key: "duration", Value: time.Second})
+
+	entry := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("could not unmarshal log entry: %v", err)
+	}
+	exp := map[string]interface{}{
+		"severity": "WARNING",
+		"message":  "something happened",
+		"duration": "1s",
+	}
+	for k, v := range exp {
+		if entry[k] != v {
+			t.Errorf("expected %s to be %v, got %v", k, v, entry[k])
+		}
+	}
+}
+
+func TestWithDrainDelay(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithDrainDelay(time.Millisecond*50)).HandleErrors(nil)
+
+	stopped := make(chan time.Time, 1)
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceStopping {
+			stopped <- time.Now()
+		}
+	})
+	lt.Start(newBlockingService())
+
+	start := time.Now()
+	lt.Shutdown()
+	if lt.IsReady() {
+		t.Errorf("expected readiness to be false as soon as the shutdown was triggered")
+	}
+	_ = lt.Wait()
+
+	if delay := (<-stopped).Sub(start); delay < time.Millisecond*50 {
+		t.Errorf("expected services to be stopped after the drain delay, stopped after %s", delay)
+	}
+}
+
+func TestWithPreset(t *testing.T) {
+	tests := []struct {
+		preset          lifetime.Preset
+		shutdownTimeout string
+		stopBudget      bool
+	}{
+		{preset: lifetime.PresetCloudRun, shutdownTimeout: "10s", stopBudget: true},
+		{preset: lifetime.PresetECS, shutdownTimeout: "28s"},
+		{preset: lifetime.PresetKubernetes, shutdownTimeout: "25s"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.preset), func(t *testing.T) {
+			lt := lifetime.New(context.Background(), lifetime.WithPreset(test.preset))
+			summary := lt.ConfigSummary()
+			if summary.ShutdownTimeout != test.shutdownTimeout {
+				t.Errorf("expected shutdown timeout %s, got %s", test.shutdownTimeout, summary.ShutdownTimeout)
+			}
+			if summary.StopBudget != test.stopBudget {
+				t.Errorf("expected stop budget %v, got %v", test.stopBudget, summary.StopBudget)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package lifetime
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProbeHandler returns a http.Handler that reports the state of the lifetime on the following paths:
+//   - /healthz responds with a 200 for as long as the application is running.
+//   - /readyz responds with a 200 if the application is ready, otherwise a 503. See IsReady.
+//     The body of the response is "degraded" if the application is ready but degraded. See Health.
+//   - /status responds with the JSON encoded Status.
+//   - /buildinfo responds with the JSON encoded BuildReport, which identifies the binary and its configuration.
+//
+// Use MountProbes to serve the probes from an existing mux instead.
+func (lifetime *Lifetime) ProbeHandler() http.Handler {
+	mux := http.NewServeMux()
+	lifetime.MountProbes(mux, WithProbeRoutes(probeRoutes...))
+	return mux
+}
+
+// ProbeService returns a service that serves the ProbeHandler on the given address.
+func (lifetime *Lifetime) ProbeService(addr string) Service {
+	return NewHTTPService(&http.Server{
+		Addr:    addr,
+		Handler: lifetime.ProbeHandler(),
+	})
+}
+
+// serveHealthz responds with a 200.
+func (lifetime *Lifetime) serveHealthz(rw http.ResponseWriter, r *http.Request) {
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write([]byte("ok\n"))
+}
+
+// serveReadyz responds with a 200 if the application is ready, otherwise a 503.
+func (lifetime *Lifetime) serveReadyz(rw http.ResponseWriter, r *http.Request) {
+	if !lifetime.IsReady() {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = rw.Write([]byte("not ready\n"))
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+	if lifetime.IsDegraded() {
+		_, _ = rw.Write([]byte("degraded\n"))
+		return
+	}
+	_, _ = rw.Write([]byte("ok\n"))
+}
+
+// serveStatus responds with the JSON encoded Status.
+func (lifetime *Lifetime) serveStatus(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(lifetime.Status())
+}
@@ -0,0 +1,170 @@
+package lifetime
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProbeRoute is a path served by the probe handler.
+type ProbeRoute string
+
+const (
+	// ProbeHealthz responds with a 200 for as long as the application is running.
+	ProbeHealthz ProbeRoute = "/healthz"
+	// ProbeReadyz responds with a 200 if the application is ready, otherwise a 503.
+	ProbeReadyz ProbeRoute = "/readyz"
+	// ProbeStatus responds with the JSON encoded Status.
+	ProbeStatus ProbeRoute = "/status"
+	// ProbeBuildInfo responds with the JSON encoded BuildReport.
+	ProbeBuildInfo ProbeRoute = "/buildinfo"
+)
+
+// probeRoutes are all of the probe routes, in the order they are mounted.
+var probeRoutes = []ProbeRoute{ProbeHealthz, ProbeReadyz, ProbeStatus, ProbeBuildInfo}
+
+// ProbeMux is a router that probe handlers can be mounted on, such as http.ServeMux.
+type ProbeMux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// ProbeOption is used to configure the probe handlers mounted by MountProbes.
+type ProbeOption func(mount *probeMount)
+
+// WithProbeRoutes sets which routes are mounted.
+// Defaults to ProbeHealthz and ProbeReadyz.
+func WithProbeRoutes(routes ...ProbeRoute) ProbeOption {
+	return func(mount *probeMount) {
+		mount.routes = routes
+	}
+}
+
+// WithProbePrefix sets a prefix that is added to the path of each route, such as /internal.
+func WithProbePrefix(prefix string) ProbeOption {
+	return func(mount *probeMount) {
+		mount.prefix = prefix
+	}
+}
+
+// WithProbeVerbose makes the given routes respond with a JSON encoded ProbeReport rather than plain text.
+// It applies to ProbeHealthz and ProbeReadyz, or both if no routes are given.
+func WithProbeVerbose(routes ...ProbeRoute) ProbeOption {
+	return func(mount *probeMount) {
+		for _, route := range routesOrAll(routes) {
+			mount.verbose[route] = true
+		}
+	}
+}
+
+// WithProbeAuth requires requests to the given routes to be authorized by the given func, or every route
+// if no routes are given. Requests that are not authorized receive a 401.
+func WithProbeAuth(authorize func(r *http.Request) bool, routes ...ProbeRoute) ProbeOption {
+	return func(mount *probeMount) {
+		for _, route := range routesOrAll(routes) {
+			mount.auth[route] = authorize
+		}
+	}
+}
+
+// ProbeReport is the verbose response of the ProbeHealthz and ProbeReadyz routes. See WithProbeVerbose.
+type ProbeReport struct {
+	// Ready is true if the application is ready. See IsReady.
+	Ready bool `json:"ready"`
+	// Health is the overall health of the application. See Health.
+	Health Health `json:"health"`
+	// NotReady contains the names of the services that have not reported that they are ready, sorted.
+	NotReady []string `json:"notReady"`
+	// FailingGates contains the names of the readiness gates whose last check failed.
+	FailingGates []string `json:"failingGates"`
+	// Degraded contains the error message of each degraded service, by the name of the service.
+	Degraded map[string]string `json:"degraded,omitempty"`
+}
+
+// MountProbes mounts the probe handlers onto an existing mux, such as the one that serves the application,
+// so that a separate probe server isn't needed. See ProbeHandler for the behaviour of each route.
+func (lifetime *Lifetime) MountProbes(mux ProbeMux, opts ...ProbeOption) {
+	mount := &probeMount{
+		routes:  []ProbeRoute{ProbeHealthz, ProbeReadyz},
+		verbose: map[ProbeRoute]bool{},
+		auth:    map[ProbeRoute]func(r *http.Request) bool{},
+	}
+	for _, opt := range opts {
+		opt(mount)
+	}
+	for _, route := range mount.routes {
+		mux.Handle(mount.prefix+string(route), mount.handler(lifetime, route))
+	}
+}
+
+// probeMount holds the options given to MountProbes.
+type probeMount struct {
+	routes  []ProbeRoute
+	prefix  string
+	verbose map[ProbeRoute]bool
+	auth    map[ProbeRoute]func(r *http.Request) bool
+}
+
+// handler returns the handler for the given route.
+func (mount *probeMount) handler(lifetime *Lifetime, route ProbeRoute) http.Handler {
+	var handler http.HandlerFunc
+	switch {
+	case mount.verbose[route] && (route == ProbeHealthz || route == ProbeReadyz):
+		handler = func(rw http.ResponseWriter, r *http.Request) {
+			lifetime.serveProbeReport(rw, route)
+		}
+	case route == ProbeHealthz:
+		handler = lifetime.serveHealthz
+	case route == ProbeReadyz:
+		handler = lifetime.serveReadyz
+	case route == ProbeStatus:
+		handler = lifetime.serveStatus
+	case route == ProbeBuildInfo:
+		handler = lifetime.serveBuildInfo
+	default:
+		handler = http.NotFound
+	}
+
+	authorize, ok := mount.auth[route]
+	if !ok {
+		return handler
+	}
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if !authorize(r) {
+			rw.WriteHeader(http.StatusUnauthorized)
+			_, _ = rw.Write([]byte("unauthorized\n"))
+			return
+		}
+		handler(rw, r)
+	})
+}
+
+// routesOrAll returns the given routes, or every route if none are given.
+func routesOrAll(routes []ProbeRoute) []ProbeRoute {
+	if len(routes) == 0 {
+		return probeRoutes
+	}
+	return routes
+}
+
+// serveProbeReport responds with the JSON encoded ProbeReport.
+// The status code is the same as the plain text response of the given route.
+func (lifetime *Lifetime) serveProbeReport(rw http.ResponseWriter, route ProbeRoute) {
+	status := lifetime.Status()
+	report := ProbeReport{
+		Ready:        status.Ready,
+		Health:       status.Health,
+		NotReady:     lifetime.notReady(),
+		FailingGates: make([]string, 0),
+		Degraded:     status.Degraded,
+	}
+	for _, gate := range status.Gates {
+		if !gate.Healthy {
+			report.FailingGates = append(report.FailingGates, gate.Name)
+		}
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if route == ProbeReadyz && !report.Ready {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(rw).Encode(report)
+}
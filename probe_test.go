@@ -0,0 +1,126 @@
+package lifetime_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/tomwright/lifetime"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLifetime_ProbeHandler(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(newBlockingService(), lifetime.WithName("worker"))
+	handler := lt.ProbeHandler()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	if code := get("/readyz").Code; code != http.StatusServiceUnavailable {
+		t.Errorf("expected readyz to return 503 before startup, got %d", code)
+	}
+
+	waitErr := make(chan error)
+	go func() {
+		waitErr <- lt.Wait()
+	}()
+	select {
+	case <-lt.Ready():
+	case <-time.After(time.Second):
+		t.Fatalf("expected lifetime to be ready")
+	}
+
+	if code := get("/healthz").Code; code != http.StatusOK {
+		t.Errorf("expected healthz to return 200, got %d", code)
+	}
+	if code := get("/readyz").Code; code != http.StatusOK {
+		t.Errorf("expected readyz to return 200, got %d", code)
+	}
+
+	var status lifetime.Status
+	if err := json.NewDecoder(get("/status").Body).Decode(&status); err != nil {
+		t.Fatalf("could not decode status: %v", err)
+	}
+	if !status.Ready || len(status.Services) != 1 || status.Services[0].Name != "worker" {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	lt.Shutdown()
+	if code := get("/readyz").Code; code != http.StatusServiceUnavailable {
+		t.Errorf("expected readyz to return 503 once shutting down, got %d", code)
+	}
+	<-waitErr
+}
+
+func TestLifetime_ProbeHandler_BuildInfo(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithShutdownTimeout(time.Second*5), lifetime.WithLogLevel(lifetime.LevelWarn))
+
+	rec := httptest.NewRecorder()
+	lt.ProbeHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/buildinfo", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected buildinfo to return 200, got %d", rec.Code)
+	}
+
+	var report lifetime.BuildReport
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("could not decode build report: %v", err)
+	}
+	if report.Build.GoVersion == "" {
+		t.Errorf("expected the go version to be set")
+	}
+	if report.Config.ShutdownTimeout != "5s" || report.Config.LogLevel != lifetime.LevelWarn.String() {
+		t.Errorf("unexpected config summary: %+v", report.Config)
+	}
+}
+
+func TestLifetime_MountProbes(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(unreadyService{newBlockingService()}, lifetime.WithName("worker"))
+
+	mux := http.NewServeMux()
+	lt.MountProbes(mux,
+		lifetime.WithProbePrefix("/internal"),
+		lifetime.WithProbeVerbose(lifetime.ProbeReadyz),
+		lifetime.WithProbeAuth(func(r *http.Request) bool {
+			return r.Header.Get("X-Probe-Token") == "secret"
+		}, lifetime.ProbeReadyz),
+	)
+
+	get := func(path string, token string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Probe-Token", token)
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get("/internal/healthz", ""); rec.Code != http.StatusOK || rec.Body.String() != "ok\n" {
+		t.Errorf("expected healthz to return 200 without auth, got %d %q", rec.Code, rec.Body.String())
+	}
+	if code := get("/internal/readyz", "wrong").Code; code != http.StatusUnauthorized {
+		t.Errorf("expected readyz to return 401 without a valid token, got %d", code)
+	}
+	if code := get("/internal/status", "secret").Code; code != http.StatusNotFound {
+		t.Errorf("expected status not to be mounted, got %d", code)
+	}
+
+	rec := get("/internal/readyz", "secret")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected readyz to return 503, got %d", rec.Code)
+	}
+	var report lifetime.ProbeReport
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("could not decode probe report: %v", err)
+	}
+	if report.Ready || len(report.NotReady) != 1 || report.NotReady[0] != "worker" {
+		t.Errorf("expected worker to be reported as not ready, got %+v", report)
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+}
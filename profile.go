@@ -0,0 +1,86 @@
+package lifetime
+
+// ProfileAll is the profile that runs every registered service. See RunProfile.
+const ProfileAll = "all"
+
+// ProfileMember can be implemented by a service to declare the profiles it belongs to.
+type ProfileMember interface {
+	// Profiles returns the names of the profiles the service belongs to.
+	Profiles() []string
+}
+
+// InProfiles declares that the service belongs to the given profiles, overriding any profiles
+// the service declares itself. See RunProfile.
+func InProfiles(profiles ...string) StartOption {
+	return func(managed *managedService) {
+		managed.profiles = profiles
+	}
+}
+
+// registration is a service that has been registered to be started by RunProfile.
+type registration struct {
+	svc  Service
+	opts []StartOption
+}
+
+// Register registers a service to be started by RunProfile, so that a single binary can be deployed
+// in different modes such as "api" and "worker".
+// The profiles a service belongs to are given with InProfiles, or declared by the service with ProfileMember.
+// Services that do not belong to any profile belong to every profile.
+func (lifetime *Lifetime) Register(svc Service, opts ...StartOption) {
+	lifetime.registrationsMu.Lock()
+	defer lifetime.registrationsMu.Unlock()
+	lifetime.registrations = append(lifetime.registrations, registration{svc: svc, opts: opts})
+}
+
+// RunProfile starts the registered services that belong to the given profile, in the order they were
+// registered, and returns their handles. Every registered service is started for ProfileAll.
+// Registered services are only started once, so RunProfile can be called again to run another profile
+// alongside the first.
+func (lifetime *Lifetime) RunProfile(profile string) []*ServiceHandle {
+	lifetime.registrationsMu.Lock()
+	matching := make([]registration, 0)
+	remaining := make([]registration, 0)
+	for _, reg := range lifetime.registrations {
+		if profile == ProfileAll || reg.inProfile(profile) {
+			matching = append(matching, reg)
+		} else {
+			remaining = append(remaining, reg)
+		}
+	}
+	lifetime.registrations = remaining
+	lifetime.registrationsMu.Unlock()
+
+	lifetime.log(LevelInfo, "lifetime running profile",
+		Field{Key: "profile", Value: profile},
+		Field{Key: "services", Value: len(matching)},
+	)
+	handles := make([]*ServiceHandle, len(matching))
+	for i, reg := range matching {
+		handles[i] = lifetime.Start(reg.svc, reg.opts...)
+	}
+	return handles
+}
+
+// inProfile returns true if the registered service belongs to the given profile.
+func (reg registration) inProfile(profile string) bool {
+	managed := &managedService{svc: reg.svc}
+	for _, opt := range reg.opts {
+		opt(managed)
+	}
+	profiles := managed.profiles
+	if profiles == nil {
+		if member, ok := reg.svc.(ProfileMember); ok {
+			profiles = member.Profiles()
+		}
+	}
+	if len(profiles) == 0 {
+		return true
+	}
+	for _, p := range profiles {
+		if p == profile {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"testing"
+)
+
+// workerService is a blocking service that declares the profiles it belongs to.
+type workerService struct {
+	*blockingService
+}
+
+func (s workerService) Profiles() []string {
+	return []string{"worker"}
+}
+
+func TestLifetime_RunProfile(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	lt.Register(newBlockingService(), lifetime.WithName("api"), lifetime.InProfiles("api"))
+	lt.Register(workerService{newBlockingService()}, lifetime.WithName("worker"))
+	lt.Register(newBlockingService(), lifetime.WithName("probes"))
+
+	handles := lt.RunProfile("worker")
+	if len(handles) != 2 || handles[0].Name() != "worker" || handles[1].Name() != "probes" {
+		t.Fatalf("expected the worker and shared services to be started, got %d handles", len(handles))
+	}
+
+	handles = lt.RunProfile(lifetime.ProfileAll)
+	if len(handles) != 1 || handles[0].Name() != "api" {
+		t.Errorf("expected only the remaining service to be started, got %d handles", len(handles))
+	}
+
+	if status := lt.Status(); len(status.Services) != 3 {
+		t.Errorf("expected 3 services to be running, got %d", len(status.Services))
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+}
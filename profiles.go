@@ -0,0 +1,65 @@
+package lifetime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+)
+
+// WithProfilesOnFailure writes a heap profile and a goroutine dump to the given directory when a shutdown
+// is caused by a service error, or when the shutdown timeout is exceeded, so that there is evidence to
+// investigate once the application has exited.
+// Files are named after the shutdown ID, see ShutdownID. The heap profile can be read with go tool pprof.
+func WithProfilesOnFailure(dir string) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.profileDir = dir
+	}
+}
+
+// writeProfiles writes profiles to the profile directory, if there is one.
+// The reason is included in the file names.
+func (lifetime *Lifetime) writeProfiles(reason string) {
+	if lifetime.profileDir == "" {
+		return
+	}
+	prefix := fmt.Sprintf("%s-%s", lifetime.ShutdownID(), reason)
+	files := make([]string, 0, 2)
+	for _, profile := range []struct {
+		name  string
+		file  string
+		debug int
+	}{
+		{name: "heap", file: prefix + "-heap.pprof", debug: 0},
+		{name: "goroutine", file: prefix + "-goroutine.txt", debug: 2},
+	} {
+		path := filepath.Join(lifetime.profileDir, profile.file)
+		if err := writeProfile(profile.name, path, profile.debug); err != nil {
+			lifetime.log(LevelWarn, "lifetime could not write profile",
+				Field{Key: "profile", Value: profile.name},
+				Field{Key: "error", Value: err},
+			)
+			continue
+		}
+		files = append(files, path)
+	}
+	if len(files) > 0 {
+		lifetime.log(LevelInfo, "lifetime wrote profiles", Field{Key: "reason", Value: reason}, Field{Key: "files", Value: files})
+	}
+}
+
+// writeProfile writes the named pprof profile to the given path.
+func writeProfile(name string, path string, debug int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("could not create profile directory: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create profile file: %w", err)
+	}
+	if err := pprof.Lookup(name).WriteTo(file, debug); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("could not write profile: %w", err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,60 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWithProfilesOnFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lifetime")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	expectProfiles := func(t *testing.T, lt *lifetime.Lifetime, reason string) {
+		prefix := filepath.Join(dir, lt.ShutdownID()+"-"+reason)
+		for _, path := range []string{prefix + "-heap.pprof", prefix + "-goroutine.txt"} {
+			info, err := os.Stat(path)
+			if err != nil || info.Size() == 0 {
+				t.Errorf("expected profile %s to be written: %v", path, err)
+			}
+		}
+	}
+
+	t.Run("service error", func(t *testing.T) {
+		lt := lifetime.New(context.Background(), lifetime.WithProfilesOnFailure(dir)).HandleErrors(nil)
+		lt.Start(&failingService{err: errors.New("failed")})
+		_ = lt.Wait()
+		expectProfiles(t, lt, "service-error")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		lt := lifetime.New(context.Background(),
+			lifetime.WithProfilesOnFailure(dir),
+			lifetime.WithShutdownTimeout(time.Millisecond*50),
+		).HandleErrors(nil)
+		lt.Start(&stuckService{})
+		lt.Shutdown()
+		_ = lt.Wait()
+		expectProfiles(t, lt, "timeout")
+	})
+
+	t.Run("clean", func(t *testing.T) {
+		lt := lifetime.New(context.Background(), lifetime.WithProfilesOnFailure(dir)).HandleErrors(nil)
+		lt.Start(newBlockingService())
+		lt.Shutdown()
+		_ = lt.Wait()
+
+		matches, _ := filepath.Glob(filepath.Join(dir, lt.ShutdownID()+"*"))
+		if len(matches) != 0 {
+			t.Errorf("expected no profiles for a clean shutdown, got %v", matches)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package lifetime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// ErrNotProvided is returned by Use when no dependency of the requested type has been provided.
+var ErrNotProvided = errors.New("dependency not provided")
+
+// provider builds a dependency the first time it is used. See Provide.
+type provider struct {
+	name  string
+	build func(ctx context.Context) (interface{}, error)
+	once  sync.Once
+	value interface{}
+	err   error
+	// used is true once the dependency has been asked for, after which the provider can't be replaced.
+	used bool
+}
+
+// provide registers a provider for dependencies of the given type, replacing any existing provider that
+// hasn't been used yet.
+func (lifetime *Lifetime) provide(key reflect.Type, build func(ctx context.Context) (interface{}, error)) {
+	lifetime.providersMu.Lock()
+	defer lifetime.providersMu.Unlock()
+	if existing, ok := lifetime.providers[key]; ok && existing.used {
+		return
+	}
+	lifetime.providers[key] = &provider{name: key.String(), build: build}
+}
+
+// use returns the dependency of the given type, building it the first time it is used.
+func (lifetime *Lifetime) use(key reflect.Type) (interface{}, error) {
+	lifetime.providersMu.Lock()
+	p, ok := lifetime.providers[key]
+	if ok {
+		p.used = true
+	}
+	lifetime.providersMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotProvided, key)
+	}
+
+	p.once.Do(func() {
+		p.value, p.err = p.build(lifetime.ctx)
+		if p.err != nil {
+			p.err = fmt.Errorf("could not build %s: %w", p.name, p.err)
+			return
+		}
+		lifetime.built(p)
+	})
+	return p.value, p.err
+}
+
+// built records that the given provider has built its dependency, so that it is closed during the shutdown.
+func (lifetime *Lifetime) built(p *provider) {
+	lifetime.providersMu.Lock()
+	defer lifetime.providersMu.Unlock()
+	if len(lifetime.provided) == 0 {
+		lifetime.OnStage(StageCloseStorage, "dependencies", lifetime.closeDependencies)
+	}
+	lifetime.provided = append(lifetime.provided, p)
+}
+
+// closeDependencies closes the dependencies that have been built, in the reverse of the order they were built.
+// Dependencies are closed if they implement io.Closer or have a Close method that returns nothing.
+func (lifetime *Lifetime) closeDependencies(ctx context.Context) error {
+	lifetime.providersMu.Lock()
+	provided := lifetime.provided
+	lifetime.providersMu.Unlock()
+
+	var firstErr error
+	for i := len(provided) - 1; i >= 0; i-- {
+		var err error
+		switch closer := provided[i].value.(type) {
+		case io.Closer:
+			err = closer.Close()
+		case interface{ Close() }:
+			closer.Close()
+		}
+		if err == nil {
+			continue
+		}
+		lifetime.log(LevelWarn, "lifetime could not close dependency",
+			Field{Key: "dependency", Value: provided[i].name},
+			Field{Key: "error", Value: err.Error()},
+		)
+		if firstErr == nil {
+			firstErr = fmt.Errorf("could not close %s: %w", provided[i].name, err)
+		}
+	}
+	return firstErr
+}
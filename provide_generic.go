@@ -0,0 +1,39 @@
+//go:build go1.18
+// +build go1.18
+
+package lifetime
+
+import (
+	"context"
+	"reflect"
+)
+
+// Provide registers a dependency of type T, such as a database connection pool, that services can get with Use.
+// The dependency is built with the given func the first time it is used, usually while services are being
+// constructed before they are started, and the context given to it is the lifetime context.
+// Once the services have stopped, dependencies that implement io.Closer or have a Close method are closed
+// during the StageCloseStorage stage, in the reverse of the order they were built.
+// Providing a type again replaces the earlier provider, unless it has already been used.
+func Provide[T any](lifetime *Lifetime, build func(ctx context.Context) (T, error)) {
+	lifetime.provide(typeOf[T](), func(ctx context.Context) (interface{}, error) {
+		return build(ctx)
+	})
+}
+
+// Use returns the dependency of type T, building it if it hasn't been used before. See Provide.
+// ErrNotProvided is returned if no dependency of type T has been provided.
+func Use[T any](lifetime *Lifetime) (T, error) {
+	value, err := lifetime.use(typeOf[T]())
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	// A nil interface value can't be asserted, so the zero value is returned for it.
+	typed, _ := value.(T)
+	return typed, nil
+}
+
+// typeOf returns the reflect.Type of T, which works for interface types too.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,119 @@
+//go:build go1.18
+// +build go1.18
+
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+)
+
+// closeRecorder records the order that dependencies are closed in.
+type closeRecorder struct {
+	mu     sync.Mutex
+	closed []string
+}
+
+func (recorder *closeRecorder) record(name string) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.closed = append(recorder.closed, name)
+}
+
+// testDB is a dependency that implements io.Closer.
+type testDB struct {
+	recorder *closeRecorder
+}
+
+func (db *testDB) Close() error {
+	db.recorder.record("db")
+	return nil
+}
+
+// testCache is a dependency with a Close method that returns nothing, built from a testDB.
+type testCache struct {
+	db       *testDB
+	recorder *closeRecorder
+}
+
+func (cache *testCache) Close() {
+	cache.recorder.record("cache")
+}
+
+func TestProvide(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	recorder := &closeRecorder{}
+
+	builds := 0
+	lifetime.Provide(lt, func(ctx context.Context) (*testDB, error) {
+		builds++
+		return &testDB{recorder: recorder}, nil
+	})
+	lifetime.Provide(lt, func(ctx context.Context) (*testCache, error) {
+		db, err := lifetime.Use[*testDB](lt)
+		if err != nil {
+			return nil, err
+		}
+		return &testCache{db: db, recorder: recorder}, nil
+	})
+
+	cache, err := lifetime.Use[*testCache](lt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	db, err := lifetime.Use[*testDB](lt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cache.db != db || builds != 1 {
+		t.Errorf("expected the db to be built once and shared")
+	}
+	if _, err := lifetime.Use[*testing.T](lt); !errors.Is(err, lifetime.ErrNotProvided) {
+		t.Errorf("expected ErrNotProvided, got %v", err)
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recorder.closed) != 2 || recorder.closed[0] != "cache" || recorder.closed[1] != "db" {
+		t.Errorf("expected dependencies to be closed in reverse order, got %v", recorder.closed)
+	}
+}
+
+func TestProvide_Error(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lifetime.Provide(lt, func(ctx context.Context) (*testDB, error) {
+		return nil, errors.New("connection refused")
+	})
+	if _, err := lifetime.Use[*testDB](lt); err == nil || err.Error() != "could not build *lifetime_test.testDB: connection refused" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	lt.Shutdown()
+	_ = lt.Wait()
+}
+
+func TestProvide_Twice(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	provideName := func(name string) {
+		lifetime.Provide(lt, func(ctx context.Context) (string, error) {
+			return name, nil
+		})
+	}
+
+	provideName("first")
+	provideName("second")
+	if name, err := lifetime.Use[string](lt); err != nil || name != "second" {
+		t.Errorf("expected the unused provider to be replaced, got %q, %v", name, err)
+	}
+
+	provideName("third")
+	if name, err := lifetime.Use[string](lt); err != nil || name != "second" {
+		t.Errorf("expected the used provider to be kept, got %q, %v", name, err)
+	}
+	lt.Shutdown()
+	_ = lt.Wait()
+}
@@ -0,0 +1,76 @@
+package lifetime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Quiescer can be implemented by a Service that should stop taking on new work before any service is stopped,
+// such as a consumer that stops pulling messages so that the work in progress can be completed.
+// During a shutdown every running Quiescer is asked to quiesce in parallel, and services are only stopped
+// once they have all returned or the shutdown timeout has been reached.
+type Quiescer interface {
+	// Quiesce stops the service from taking on new work. The context is done when the shutdown timeout is reached.
+	Quiesce(ctx context.Context) error
+}
+
+// quiesce asks each of the given services that implement Quiescer to quiesce, and blocks until they have
+// all returned or the given context is done.
+func (lifetime *Lifetime) quiesce(ctx context.Context, running []*managedService) {
+	quiescers := make([]*managedService, 0)
+	for _, managed := range running {
+		if _, ok := managed.svc.(Quiescer); ok {
+			quiescers = append(quiescers, managed)
+		}
+	}
+	if len(quiescers) == 0 {
+		return
+	}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(len(quiescers))
+	for _, managed := range quiescers {
+		go func(managed *managedService) {
+			defer wg.Done()
+			managed.quiesce(ctx)
+		}(managed)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		lifetime.log(LevelWarn, "lifetime services did not quiesce in time")
+	}
+}
+
+// quiesce asks the service to quiesce and emits an EventServiceQuiesced once it has returned.
+func (managed *managedService) quiesce(ctx context.Context) {
+	select {
+	case <-managed.exited:
+		return
+	default:
+	}
+	start := time.Now()
+	var err error
+	if err = managed.svc.(Quiescer).Quiesce(ctx); err != nil {
+		err = managed.wrapError(err)
+	}
+	managed.lifetime.emit(Event{
+		Type:     EventServiceQuiesced,
+		Service:  managed.name,
+		Labels:   managed.labels,
+		Metadata: managed.metadata,
+		Err:      err,
+		Duration: time.Since(start),
+	})
+	if err != nil {
+		managed.log(LevelWarn, "lifetime service could not quiesce", err)
+		return
+	}
+	managed.log(LevelDebug, "lifetime "+string(EventServiceQuiesced), nil)
+}
@@ -0,0 +1,62 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+)
+
+// quiescingService is a service that returns the given error when asked to quiesce.
+type quiescingService struct {
+	*blockingService
+	err error
+}
+
+func (s *quiescingService) Quiesce(ctx context.Context) error {
+	return s.err
+}
+
+func TestQuiescer(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	var mu sync.Mutex
+	var events []lifetime.Event
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceQuiesced || event.Type == lifetime.EventServiceStopping {
+			mu.Lock()
+			events = append(events, event)
+			mu.Unlock()
+		}
+	})
+
+	lt.Start(&quiescingService{blockingService: newBlockingService()}, lifetime.WithName("consumer"))
+	lt.Start(&quiescingService{blockingService: newBlockingService(), err: errors.New("broker unavailable")},
+		lifetime.WithName("scheduler"),
+	)
+	lt.Start(newBlockingService(), lifetime.WithName("api"))
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 5 {
+		t.Fatalf("expected 5 events, got %d", len(events))
+	}
+	failed := 0
+	for _, event := range events[:2] {
+		if event.Type != lifetime.EventServiceQuiesced {
+			t.Fatalf("expected services to quiesce before any are stopped, got %s for %s", event.Type, event.Service)
+		}
+		if event.Err != nil {
+			failed++
+		}
+	}
+	if failed != 1 {
+		t.Errorf("expected 1 service to fail to quiesce, got %d", failed)
+	}
+}
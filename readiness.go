@@ -0,0 +1,175 @@
+package lifetime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ReadinessCheck checks that an external dependency is available.
+// It should return an error if the dependency cannot be used.
+type ReadinessCheck func(ctx context.Context) error
+
+// GateOption is used to configure a readiness gate.
+type GateOption func(gate *readinessGate)
+
+// WithGateRetryInterval sets how long to wait between checks while the gate has not yet passed.
+// Defaults to 1 second.
+func WithGateRetryInterval(interval time.Duration) GateOption {
+	return func(gate *readinessGate) {
+		gate.retryInterval = interval
+	}
+}
+
+// WithGateTimeout sets the deadline given to each check.
+// Defaults to 5 seconds.
+func WithGateTimeout(timeout time.Duration) GateOption {
+	return func(gate *readinessGate) {
+		gate.timeout = timeout
+	}
+}
+
+// WithGateHardDependency marks the dependency as one the application cannot work without.
+// Once the gate has passed it is checked again on the given interval, and the application is
+// reported as not ready for as long as the check fails.
+func WithGateHardDependency(interval time.Duration) GateOption {
+	return func(gate *readinessGate) {
+		gate.hard = true
+		gate.interval = interval
+	}
+}
+
+// readinessGate is a check that must pass before the lifetime is ready.
+type readinessGate struct {
+	lifetime      *Lifetime
+	name          string
+	check         ReadinessCheck
+	retryInterval time.Duration
+	timeout       time.Duration
+	// hard is true if the gate continues to be checked once it has passed.
+	hard     bool
+	interval time.Duration
+
+	// passed is closed the first time the check passes.
+	passed chan struct{}
+
+	mu      sync.Mutex
+	healthy bool
+	lastErr error
+}
+
+// AddReadinessGate registers a check that must pass before the application is ready.
+// The check is retried until it passes or a shutdown is triggered. Gates that are added
+// before Wait is called hold back the startup phase. See Ready.
+// Use WithGateHardDependency to keep checking the dependency once the application is ready.
+func (lifetime *Lifetime) AddReadinessGate(name string, check ReadinessCheck, opts ...GateOption) {
+	gate := &readinessGate{
+		lifetime:      lifetime,
+		name:          name,
+		check:         check,
+		retryInterval: time.Second,
+		timeout:       time.Second * 5,
+		passed:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(gate)
+	}
+
+	lifetime.gatesMu.Lock()
+	lifetime.gates = append(lifetime.gates, gate)
+	lifetime.gatesMu.Unlock()
+
+	go gate.run()
+}
+
+// IsReady returns true if the startup phase has completed, every hard dependency is currently
+// available and the application is not shutting down.
+func (lifetime *Lifetime) IsReady() bool {
+	select {
+	case <-lifetime.ready:
+	default:
+		return false
+	}
+	if lifetime.ctx.Err() != nil {
+		return false
+	}
+
+	lifetime.gatesMu.Lock()
+	defer lifetime.gatesMu.Unlock()
+	for _, gate := range lifetime.gates {
+		if healthy, _ := gate.state(); !healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// run checks the gate until it passes, and then keeps checking hard dependencies on their interval.
+func (gate *readinessGate) run() {
+	ctx := gate.lifetime.ctx
+
+	for !gate.checkOnce(ctx) {
+		if !sleepContext(ctx, gate.retryInterval) {
+			return
+		}
+	}
+	close(gate.passed)
+
+	if !gate.hard {
+		return
+	}
+	for sleepContext(ctx, gate.interval) {
+		gate.checkOnce(ctx)
+	}
+}
+
+// checkOnce executes the check and records the result.
+// An event is emitted whenever the gate changes state.
+func (gate *readinessGate) checkOnce(ctx context.Context) bool {
+	checkCtx, cancel := context.WithTimeout(ctx, gate.timeout)
+	err := gate.check(checkCtx)
+	cancel()
+	if ctx.Err() != nil {
+		return false
+	}
+
+	gate.mu.Lock()
+	changed := gate.healthy != (err == nil) || (err != nil && gate.lastErr == nil)
+	gate.healthy = err == nil
+	gate.lastErr = err
+	gate.mu.Unlock()
+
+	if changed {
+		if err == nil {
+			gate.lifetime.emit(Event{Type: EventGatePassed, Gate: gate.name})
+			gate.lifetime.log(LevelInfo, "lifetime readiness gate passed", Field{Key: "gate", Value: gate.name})
+		} else {
+			gate.lifetime.emit(Event{Type: EventGateFailed, Gate: gate.name, Err: err})
+			gate.lifetime.log(LevelWarn, "lifetime readiness gate failed",
+				Field{Key: "gate", Value: gate.name},
+				Field{Key: "error", Value: err.Error()},
+			)
+		}
+	}
+	return err == nil
+}
+
+// state returns whether the gate is currently passing, along with the last error returned by the check.
+func (gate *readinessGate) state() (bool, error) {
+	gate.mu.Lock()
+	defer gate.mu.Unlock()
+	return gate.healthy, gate.lastErr
+}
+
+// sleepContext sleeps for the given duration.
+// It returns false if the context was done first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,63 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLifetime_AddReadinessGate(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(newBlockingService())
+
+	var available int32
+	lt.AddReadinessGate("db", func(ctx context.Context) error {
+		if atomic.LoadInt32(&available) == 0 {
+			return errors.New("db unavailable")
+		}
+		return nil
+	}, lifetime.WithGateRetryInterval(time.Millisecond*5), lifetime.WithGateHardDependency(time.Millisecond*5))
+
+	waitErr := make(chan error)
+	go func() {
+		waitErr <- lt.Wait()
+	}()
+
+	select {
+	case <-lt.Ready():
+		t.Fatalf("expected lifetime not to be ready before the gate passed")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	atomic.StoreInt32(&available, 1)
+	select {
+	case <-lt.Ready():
+	case <-time.After(time.Second):
+		t.Fatalf("expected lifetime to be ready once the gate passed")
+	}
+	if !lt.IsReady() {
+		t.Errorf("expected IsReady to be true")
+	}
+
+	atomic.StoreInt32(&available, 0)
+	deadline := time.Now().Add(time.Second)
+	for lt.IsReady() {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected IsReady to be false once the hard dependency disappeared")
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+
+	status := lt.Status()
+	if len(status.Gates) != 1 || status.Gates[0].Healthy || status.Gates[0].Error != "db unavailable" {
+		t.Errorf("unexpected gate status: %+v", status.Gates)
+	}
+
+	lt.Shutdown()
+	if err := <-waitErr; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package lifetime
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ServiceSpec describes a service that should be running. See Reconcile.
+type ServiceSpec struct {
+	// Name is the name of the service, which identifies it between calls to Reconcile.
+	Name string
+	// Service is the service to run.
+	Service Service
+	// Options are used when the service is first started. They are kept when the service is swapped.
+	Options []StartOption
+}
+
+// Reconcile compares the services that should be running with those started by previous calls to Reconcile,
+// so that a dynamic configuration system can decide which services run without restarting the process.
+// Services that are no longer wanted are stopped, services that have been given a different Service are
+// replaced using Swap, and services that are new, or that have exited, are started.
+// Only services started by Reconcile are affected, by their name. Services are compared with ==, so
+// a service that cannot be compared is replaced every time.
+// Every service is reconciled even if one fails, and the first error is returned.
+// ErrShuttingDown is returned if the application is shutting down.
+func (lifetime *Lifetime) Reconcile(desired []ServiceSpec) error {
+	lifetime.reconcileMu.Lock()
+	defer lifetime.reconcileMu.Unlock()
+
+	if lifetime.ctx.Err() != nil {
+		return ErrShuttingDown
+	}
+	if lifetime.reconciled == nil {
+		lifetime.reconciled = map[string]*ServiceHandle{}
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, spec := range desired {
+		wanted[spec.Name] = true
+	}
+	removed := make([]string, 0)
+	for name := range lifetime.reconciled {
+		if !wanted[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		lifetime.reconciled[name].Stop()
+		delete(lifetime.reconciled, name)
+	}
+
+	var firstErr error
+	started, swapped := 0, 0
+	for _, spec := range desired {
+		handle, ok := lifetime.reconciled[spec.Name]
+		if !ok {
+			lifetime.reconciled[spec.Name] = lifetime.StartNamed(spec.Name, spec.Service, spec.Options...)
+			started++
+			continue
+		}
+		if !sameService(handle.Service(), spec.Service) {
+			if err := handle.Swap(spec.Service); err != nil && firstErr == nil {
+				firstErr = err
+			}
+			swapped++
+			continue
+		}
+		select {
+		case <-handle.Done():
+			lifetime.start(handle)
+			started++
+		default:
+		}
+	}
+
+	lifetime.log(LevelInfo, "lifetime services reconciled",
+		Field{Key: "started", Value: started},
+		Field{Key: "stopped", Value: len(removed)},
+		Field{Key: "swapped", Value: swapped},
+	)
+	return firstErr
+}
+
+// sameService returns true if the given services are the same.
+// Services that cannot be compared are never the same.
+func sameService(a Service, b Service) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
@@ -0,0 +1,74 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"testing"
+)
+
+func TestLifetime_Reconcile(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	other := newBlockingService()
+	lt.Start(other)
+
+	a, b, c := newBlockingService(), newBlockingService(), newBlockingService()
+	err := lt.Reconcile([]lifetime.ServiceSpec{
+		{Name: "a", Service: a},
+		{Name: "b", Service: b},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	replacement := newBlockingService()
+	err = lt.Reconcile([]lifetime.ServiceSpec{
+		{Name: "b", Service: replacement},
+		{Name: "c", Service: c},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !a.stopped {
+		t.Errorf("expected a to be stopped")
+	}
+	if !b.stopped {
+		t.Errorf("expected b to be swapped out")
+	}
+
+	states := map[string]lifetime.ServiceState{}
+	for _, svc := range lt.Services() {
+		states[svc.Name] = svc.State
+	}
+	exp := map[string]lifetime.ServiceState{
+		"a": lifetime.ServiceStopped,
+		"b": lifetime.ServiceRunning,
+	}
+	for name, state := range exp {
+		if states[name] != state {
+			t.Errorf("expected %s to be %s, got %s", name, state, states[name])
+		}
+	}
+	// The new service may not have reported that it is ready yet.
+	if states["c"] != lifetime.ServiceStarting && states["c"] != lifetime.ServiceRunning {
+		t.Errorf("expected c to be started, got %s", states["c"])
+	}
+
+	if err := lt.Reconcile(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !replacement.stopped || !c.stopped {
+		t.Errorf("expected the reconciled services to be stopped")
+	}
+	if other.stopped {
+		t.Errorf("expected services not started by Reconcile to be left running")
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := lt.Reconcile(nil); err != lifetime.ErrShuttingDown {
+		t.Errorf("expected ErrShuttingDown, got %v", err)
+	}
+}
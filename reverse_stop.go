@@ -0,0 +1,22 @@
+package lifetime
+
+// WithReverseStopOrder stops services one at a time during a shutdown, in the reverse of the order they
+// were started in, so that infrastructure that was started first, such as database pools and message brokers,
+// is stopped last. Service classes and CascadeOrder still take precedence, with the services within
+// each of them being stopped in reverse order.
+// It takes precedence over the ordering of deterministic mode and WithStopConcurrency.
+func WithReverseStopOrder() Option {
+	return func(lifetime *Lifetime) {
+		lifetime.reverseStopOrder = true
+	}
+}
+
+// reverseSteps splits each of the given steps into steps of a single service, in the reverse of the order
+// the services were started in.
+func reverseSteps(steps [][]*managedService) [][]*managedService {
+	reversed := make([][]*managedService, 0, len(steps))
+	for _, step := range steps {
+		reversed = append(reversed, rollbackSteps(step)...)
+	}
+	return reversed
+}
@@ -0,0 +1,45 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+)
+
+func TestWithReverseStopOrder(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithReverseStopOrder())
+
+	var mu sync.Mutex
+	var got []string
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceStopping {
+			mu.Lock()
+			got = append(got, event.Service)
+			mu.Unlock()
+		}
+	})
+
+	lt.Start(newBlockingService(), lifetime.WithName("db"), lifetime.WithClass(lifetime.ClassStorage))
+	lt.Start(newBlockingService(), lifetime.WithName("broker"))
+	lt.Start(newBlockingService(), lifetime.WithName("consumer"))
+	lt.Start(newBlockingService(), lifetime.WithName("api"))
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	exp := []string{"api", "consumer", "broker", "db"}
+	if len(got) != len(exp) {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+	for i := range exp {
+		if got[i] != exp[i] {
+			t.Errorf("expected %v, got %v", exp, got)
+			break
+		}
+	}
+}
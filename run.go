@@ -0,0 +1,29 @@
+package lifetime
+
+import (
+	"context"
+)
+
+// Run creates and initialises a lifetime, starts the given services and waits for them to finish.
+// It returns the exit code for the application. See Lifetime.Run and ExitCode.
+// Use New when the lifetime needs options.
+//
+//	func main() {
+//		os.Exit(lifetime.Run(context.Background(), api, worker))
+//	}
+func Run(ctx context.Context, services ...Service) int {
+	lt := New(ctx).Init()
+	for _, svc := range services {
+		lt.Start(svc)
+	}
+	return lt.Run()
+}
+
+// Run waits for the services to finish and returns the exit code for the application. See ExitCode.
+// The error returned by Wait, if any, is logged.
+func (lifetime *Lifetime) Run() int {
+	if err := lifetime.Wait(); err != nil {
+		lifetime.log(LevelError, "lifetime wait failed", errorFields(err)...)
+	}
+	return lifetime.ExitCode()
+}
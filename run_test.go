@@ -0,0 +1,34 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("clean shutdown", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if code := lifetime.Run(ctx, newBlockingService(), newBlockingService()); code != lifetime.ExitCodeOK {
+			t.Errorf("expected exit code %d, got %d", lifetime.ExitCodeOK, code)
+		}
+	})
+
+	t.Run("startup failure", func(t *testing.T) {
+		code := lifetime.Run(context.Background(), newBlockingService(), &failingService{err: errors.New("bad config")})
+		if code != lifetime.ExitCodeStartupFailure {
+			t.Errorf("expected exit code %d, got %d", lifetime.ExitCodeStartupFailure, code)
+		}
+	})
+}
+
+func TestLifetime_Run(t *testing.T) {
+	lt := lifetime.New(context.Background()).Init()
+	lt.Start(newBlockingService())
+	lt.Shutdown()
+	if code := lt.Run(); code != lifetime.ExitCodeOK {
+		t.Errorf("expected exit code %d, got %d", lifetime.ExitCodeOK, code)
+	}
+}
@@ -0,0 +1,51 @@
+package lifetime
+
+import (
+	"io/ioutil"
+	"time"
+)
+
+// WithRuntimeSnapshots captures a snapshot of the Go runtime when a shutdown starts and when it completes,
+// and attaches them to the EventShutdownStarted and EventShutdownComplete events, so that a slow shutdown
+// can be correlated with resource pressure.
+func WithRuntimeSnapshots() Option {
+	return func(lifetime *Lifetime) {
+		lifetime.runtimeSnapshots = true
+	}
+}
+
+// RuntimeSnapshot describes the state of the Go runtime at a point in time.
+type RuntimeSnapshot struct {
+	// Time is the time the snapshot was captured.
+	Time time.Time `json:"time"`
+	// Goroutines is the number of goroutines.
+	Goroutines int `json:"goroutines"`
+	// HeapBytes is the number of bytes occupied by heap objects.
+	HeapBytes uint64 `json:"heap_bytes"`
+	// GCCycles is the number of completed GC cycles.
+	GCCycles uint64 `json:"gc_cycles"`
+	// GCPauseMax is an upper bound on the longest recent GC pause.
+	GCPauseMax time.Duration `json:"gc_pause_max"`
+	// OpenFiles is the number of open file descriptors, or -1 if it is not known on this platform.
+	OpenFiles int `json:"open_files"`
+}
+
+// runtimeSnapshot returns a snapshot of the runtime if runtime snapshots are enabled, otherwise nil.
+func (lifetime *Lifetime) runtimeSnapshot() *RuntimeSnapshot {
+	if !lifetime.runtimeSnapshots {
+		return nil
+	}
+	snapshot := captureRuntime()
+	snapshot.Time = time.Now()
+	snapshot.OpenFiles = openFiles()
+	return &snapshot
+}
+
+// openFiles returns the number of open file descriptors, or -1 if it can't be found.
+func openFiles() int {
+	fds, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		return -1
+	}
+	return len(fds)
+}
@@ -0,0 +1,27 @@
+//go:build !go1.16
+// +build !go1.16
+
+package lifetime
+
+import (
+	"runtime"
+	"time"
+)
+
+// captureRuntime reads the runtime state using runtime.ReadMemStats, as runtime/metrics is not available.
+func captureRuntime() RuntimeSnapshot {
+	stats := &runtime.MemStats{}
+	runtime.ReadMemStats(stats)
+
+	snapshot := RuntimeSnapshot{
+		Goroutines: runtime.NumGoroutine(),
+		HeapBytes:  stats.HeapAlloc,
+		GCCycles:   uint64(stats.NumGC),
+	}
+	for _, pause := range stats.PauseNs {
+		if d := time.Duration(pause); d > snapshot.GCPauseMax {
+			snapshot.GCPauseMax = d
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,51 @@
+//go:build go1.16
+// +build go1.16
+
+package lifetime
+
+import (
+	"math"
+	"runtime/metrics"
+	"time"
+)
+
+// captureRuntime reads the runtime state using runtime/metrics.
+func captureRuntime() RuntimeSnapshot {
+	samples := []metrics.Sample{
+		{Name: "/sched/goroutines:goroutines"},
+		{Name: "/memory/classes/heap/objects:bytes"},
+		{Name: "/gc/cycles/total:gc-cycles"},
+		{Name: "/gc/pauses:seconds"},
+	}
+	metrics.Read(samples)
+
+	snapshot := RuntimeSnapshot{}
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		snapshot.Goroutines = int(samples[0].Value.Uint64())
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		snapshot.HeapBytes = samples[1].Value.Uint64()
+	}
+	if samples[2].Value.Kind() == metrics.KindUint64 {
+		snapshot.GCCycles = samples[2].Value.Uint64()
+	}
+	if samples[3].Value.Kind() == metrics.KindFloat64Histogram {
+		snapshot.GCPauseMax = histogramMax(samples[3].Value.Float64Histogram())
+	}
+	return snapshot
+}
+
+// histogramMax returns the upper bound of the highest bucket in the given histogram that has any samples.
+func histogramMax(histogram *metrics.Float64Histogram) time.Duration {
+	for i := len(histogram.Counts) - 1; i >= 0; i-- {
+		if histogram.Counts[i] == 0 {
+			continue
+		}
+		upper := histogram.Buckets[i+1]
+		if math.IsInf(upper, 1) {
+			upper = histogram.Buckets[i]
+		}
+		return time.Duration(upper * float64(time.Second))
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+)
+
+func TestWithRuntimeSnapshots(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithRuntimeSnapshots()).HandleErrors(nil)
+
+	var mu sync.Mutex
+	snapshots := map[lifetime.EventType]*lifetime.RuntimeSnapshot{}
+	lt.OnEvent(func(event lifetime.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if event.Runtime != nil {
+			snapshots[event.Type] = event.Runtime
+		}
+	})
+
+	lt.Start(newBlockingService())
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, eventType := range []lifetime.EventType{lifetime.EventShutdownStarted, lifetime.EventShutdownComplete} {
+		snapshot, ok := snapshots[eventType]
+		if !ok {
+			t.Errorf("expected a runtime snapshot on %s", eventType)
+			continue
+		}
+		if snapshot.Goroutines == 0 || snapshot.HeapBytes == 0 || snapshot.Time.IsZero() {
+			t.Errorf("expected runtime snapshot to be populated, got %+v", snapshot)
+		}
+	}
+}
@@ -1,5 +1,9 @@
 package lifetime
 
+import (
+	"context"
+)
+
 // Service defines a single service in an application.
 type Service interface {
 	// Start will start the service.
@@ -10,3 +14,32 @@ type Service interface {
 	// Stop is not called if Start returned an error.
 	Stop()
 }
+
+// ContextStarter can be implemented by a Service that wants to receive its own context when it is started.
+// If a service implements ContextStarter, StartContext is called instead of Start.
+// The context is done when the service is stopped, either individually or as part of a shutdown,
+// just before Stop is called.
+type ContextStarter interface {
+	// StartContext will start the service.
+	// This is a blocking call and should block for the lifetime of the service.
+	// Returns an error which is treated as fatal.
+	StartContext(ctx context.Context) error
+}
+
+// Readier can be implemented by a Service that needs time to become ready after Start is called,
+// such as a server that must first bind to a port.
+// Services that do not implement Readier are considered ready as soon as they are started.
+type Readier interface {
+	// Ready returns a channel that is closed once the service is ready.
+	Ready() <-chan struct{}
+}
+
+// ContextStopper can be implemented by a Service that wants to know how long it has to stop.
+// If a service implements ContextStopper, StopContext is called instead of Stop.
+// The context has a deadline when a shutdown timeout or stop budget applies. See WithShutdownTimeout
+// and WithStopBudget.
+type ContextStopper interface {
+	// StopContext will stop the service, aiming to finish before the context is done.
+	// StopContext is not called if Start returned an error.
+	StopContext(ctx context.Context)
+}
@@ -0,0 +1,28 @@
+package lifetime
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// ACMEManager obtains certificates from an ACME certificate authority, such as Let's Encrypt.
+// It is implemented by *autocert.Manager from golang.org/x/crypto/acme/autocert, which should be given a
+// Cache so that certificates survive restarts.
+type ACMEManager interface {
+	// HTTPHandler returns a handler that responds to HTTP-01 challenges, passing other requests to fallback.
+	HTTPHandler(fallback http.Handler) http.Handler
+	// TLSConfig returns a tls.Config that obtains certificates as they are needed.
+	TLSConfig() *tls.Config
+}
+
+// NewACMEChallengeService returns a service that answers the HTTP-01 challenges of the given manager
+// on the given address, which must be reachable on port 80.
+// Any other requests are redirected to HTTPS. The listener is shutdown gracefully when the service is stopped.
+func NewACMEChallengeService(manager ACMEManager, addr string, opts ...HTTPOption) Service {
+	return NewHTTPHandlerService(addr, manager.HTTPHandler(nil), opts...)
+}
+
+// WithHTTPACME serves HTTPS using certificates obtained by the given manager.
+func WithHTTPACME(manager ACMEManager) HTTPOption {
+	return WithHTTPTLSConfig(manager.TLSConfig())
+}
@@ -0,0 +1,57 @@
+package lifetime_test
+
+import (
+	"context"
+	"crypto/tls"
+	"github.com/tomwright/lifetime"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+)
+
+// fakeACMEManager answers a single challenge and has no certificates.
+type fakeACMEManager struct{}
+
+func (m *fakeACMEManager) HTTPHandler(fallback http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/.well-known/acme-challenge/token" {
+			_, _ = writer.Write([]byte("key-authorization"))
+			return
+		}
+		http.Redirect(writer, request, "https://"+request.Host+request.URL.Path, http.StatusFound)
+	})
+}
+
+func (m *fakeACMEManager) TLSConfig() *tls.Config {
+	return &tls.Config{NextProtos: []string{"acme-tls/1"}}
+}
+
+func TestNewACMEChallengeService(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not find a free port: %v", err)
+	}
+	addr := lis.Addr().String()
+	_ = lis.Close()
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	svc := lifetime.NewACMEChallengeService(&fakeACMEManager{}, addr)
+	lt.Start(svc)
+	<-svc.(lifetime.Readier).Ready()
+
+	resp, err := http.Get("http://" + addr + "/.well-known/acme-challenge/token")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "key-authorization" {
+		t.Errorf("expected the challenge to be answered, got %q", string(body))
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
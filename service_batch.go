@@ -0,0 +1,172 @@
+package lifetime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBatchFlusherStopped is returned when an item is added to a BatchFlusher that has been stopped.
+var ErrBatchFlusherStopped = errors.New("batch flusher stopped")
+
+// BatchFlushFunc writes a batch of items.
+type BatchFlushFunc func(ctx context.Context, items []interface{}) error
+
+// BatchOption is used to configure a BatchFlusher.
+type BatchOption func(flusher *BatchFlusher)
+
+// WithBatchInterval sets how often the batch is flushed, regardless of its size.
+// Defaults to 1 second.
+func WithBatchInterval(interval time.Duration) BatchOption {
+	return func(flusher *BatchFlusher) {
+		flusher.interval = interval
+	}
+}
+
+// WithBatchSize sets the number of items that causes the batch to be flushed straight away.
+// Defaults to 100.
+func WithBatchSize(size int) BatchOption {
+	return func(flusher *BatchFlusher) {
+		flusher.size = size
+	}
+}
+
+// WithBatchFinalFlushTimeout sets the deadline given to the final flush when the flusher is stopped.
+// Defaults to 10 seconds.
+func WithBatchFinalFlushTimeout(timeout time.Duration) BatchOption {
+	return func(flusher *BatchFlusher) {
+		flusher.finalFlushTimeout = timeout
+	}
+}
+
+// WithBatchErrorHandler sets a func that is called when a batch could not be flushed.
+// If it returns nil the flusher carries on and the items are discarded, otherwise the returned
+// error is treated as fatal.
+// Defaults to treating every error as fatal.
+func WithBatchErrorHandler(handler func(items []interface{}, err error) error) BatchOption {
+	return func(flusher *BatchFlusher) {
+		flusher.errorHandler = handler
+	}
+}
+
+// NewBatchFlusher returns a service that accumulates items and flushes them using the given func
+// when the batch reaches a certain size or on an interval, whichever comes first.
+// When the flusher is stopped a final flush is performed with a deadline.
+func NewBatchFlusher(flush BatchFlushFunc, opts ...BatchOption) *BatchFlusher {
+	flusher := &BatchFlusher{
+		flush:             flush,
+		interval:          time.Second,
+		size:              100,
+		finalFlushTimeout: time.Second * 10,
+		errorHandler: func(items []interface{}, err error) error {
+			return err
+		},
+		full: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(flusher)
+	}
+	return flusher
+}
+
+// BatchFlusher is a Service that accumulates items and flushes them in batches.
+type BatchFlusher struct {
+	flush             BatchFlushFunc
+	interval          time.Duration
+	size              int
+	finalFlushTimeout time.Duration
+	errorHandler      func(items []interface{}, err error) error
+
+	mu      sync.Mutex
+	items   []interface{}
+	stopped bool
+
+	// full receives a value when the batch reaches its size.
+	full     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// Add adds an item to the current batch.
+// It is safe to call from multiple go routines.
+// Returns ErrBatchFlusherStopped if the flusher has been stopped.
+func (flusher *BatchFlusher) Add(item interface{}) error {
+	flusher.mu.Lock()
+	if flusher.stopped {
+		flusher.mu.Unlock()
+		return ErrBatchFlusherStopped
+	}
+	flusher.items = append(flusher.items, item)
+	full := len(flusher.items) >= flusher.size
+	flusher.mu.Unlock()
+
+	if full {
+		select {
+		case flusher.full <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Start will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal.
+func (flusher *BatchFlusher) Start() error {
+	ticker := time.NewTicker(flusher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-flusher.full:
+		case <-flusher.stop:
+			return flusher.finalFlush()
+		}
+		if err := flusher.flushPending(context.Background()); err != nil {
+			return err
+		}
+	}
+}
+
+// finalFlush stops accepting items and flushes anything that is left, within the final flush timeout.
+func (flusher *BatchFlusher) finalFlush() error {
+	flusher.mu.Lock()
+	flusher.stopped = true
+	flusher.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), flusher.finalFlushTimeout)
+	defer cancel()
+	return flusher.flushPending(ctx)
+}
+
+// flushPending flushes the items in the current batch, if there are any.
+func (flusher *BatchFlusher) flushPending(ctx context.Context) error {
+	flusher.mu.Lock()
+	items := flusher.items
+	flusher.items = nil
+	flusher.mu.Unlock()
+
+	if len(items) == 0 {
+		return nil
+	}
+	if err := flusher.flush(ctx, items); err != nil {
+		return flusher.errorHandler(items, err)
+	}
+	return nil
+}
+
+// Stop will stop the service.
+// Stop is not called if Start returned an error.
+func (flusher *BatchFlusher) Stop() {
+	flusher.stopOnce.Do(func() {
+		close(flusher.stop)
+	})
+}
+
+// Class returns ClassStateful, so the service is stopped once the stateless services have stopped.
+func (flusher *BatchFlusher) Class() ServiceClass {
+	return ClassStateful
+}
@@ -0,0 +1,51 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchFlusher(t *testing.T) {
+	var mu sync.Mutex
+	batches := make([][]interface{}, 0)
+	flush := func(ctx context.Context, items []interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, items)
+		return nil
+	}
+
+	flusher := lifetime.NewBatchFlusher(flush, lifetime.WithBatchSize(2), lifetime.WithBatchInterval(time.Hour))
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(flusher)
+
+	for i := 0; i < 3; i++ {
+		if err := flusher.Add(i); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := flusher.Add(4); !errors.Is(err, lifetime.ErrBatchFlusherStopped) {
+		t.Errorf("expected ErrBatchFlusherStopped, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	total := 0
+	for _, batch := range batches {
+		total += len(batch)
+	}
+	if total != 3 {
+		t.Errorf("expected 3 items to be flushed, got %d", total)
+	}
+}
@@ -0,0 +1,75 @@
+package lifetime
+
+// ServiceClass describes what kind of state a service holds, so that services can be stopped in a
+// sensible order without declaring dependencies between them.
+// During a shutdown stateless services are stopped first, then stateful services, and storage last.
+type ServiceClass int
+
+const (
+	// ClassUnspecified is used for services that have not been classified.
+	// They are stopped along with stateless services.
+	ClassUnspecified ServiceClass = iota
+	// ClassStateless is used for services that hold no state of their own, such as HTTP and gRPC servers
+	// at the edge of the application. They are stopped first so that no new work is accepted.
+	ClassStateless
+	// ClassStateful is used for services that hold work in progress, such as consumers and workers.
+	// They are stopped once the stateless services have stopped.
+	ClassStateful
+	// ClassStorage is used for services that other services store state in, such as connection pools
+	// and caches. They are stopped last.
+	ClassStorage
+)
+
+// String returns the name of the class.
+func (class ServiceClass) String() string {
+	switch class {
+	case ClassStateless:
+		return "stateless"
+	case ClassStateful:
+		return "stateful"
+	case ClassStorage:
+		return "storage"
+	default:
+		return "unspecified"
+	}
+}
+
+// serviceClasses are the classes in the order they are stopped.
+var serviceClasses = []ServiceClass{ClassStateless, ClassStateful, ClassStorage}
+
+// Classifier can be implemented by a service to declare its class.
+type Classifier interface {
+	// Class returns the class of the service.
+	Class() ServiceClass
+}
+
+// WithClass sets the class of the service, overriding any class the service declares itself.
+func WithClass(class ServiceClass) StartOption {
+	return func(managed *managedService) {
+		managed.class = class
+	}
+}
+
+// stopClass returns the class the service is stopped with.
+func (managed *managedService) stopClass() ServiceClass {
+	if managed.class == ClassUnspecified {
+		return ClassStateless
+	}
+	return managed.class
+}
+
+// classSteps splits the given services into a step per class, in the order the classes are stopped.
+// Classes that have no services are skipped.
+func classSteps(running []*managedService) [][]*managedService {
+	byClass := map[ServiceClass][]*managedService{}
+	for _, managed := range running {
+		byClass[managed.stopClass()] = append(byClass[managed.stopClass()], managed)
+	}
+	steps := make([][]*managedService, 0, len(serviceClasses))
+	for _, class := range serviceClasses {
+		if len(byClass[class]) > 0 {
+			steps = append(steps, byClass[class])
+		}
+	}
+	return steps
+}
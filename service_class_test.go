@@ -0,0 +1,60 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+)
+
+// orderedStopService records the order in which services are stopped.
+type orderedStopService struct {
+	name  string
+	class lifetime.ServiceClass
+	mu    *sync.Mutex
+	order *[]string
+	stop  chan struct{}
+}
+
+func (s *orderedStopService) Start() error {
+	<-s.stop
+	return nil
+}
+
+func (s *orderedStopService) Stop() {
+	s.mu.Lock()
+	*s.order = append(*s.order, s.name)
+	s.mu.Unlock()
+	close(s.stop)
+}
+
+func (s *orderedStopService) Class() lifetime.ServiceClass {
+	return s.class
+}
+
+func TestServiceClass_StopOrder(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	mu := &sync.Mutex{}
+	order := make([]string, 0)
+	newService := func(name string, class lifetime.ServiceClass) *orderedStopService {
+		return &orderedStopService{name: name, class: class, mu: mu, order: &order, stop: make(chan struct{})}
+	}
+
+	lt.Start(newService("db", lifetime.ClassStorage))
+	lt.Start(newService("worker", lifetime.ClassStateful))
+	lt.Start(newService("api", lifetime.ClassStateless))
+	lt.Start(newService("cache", lifetime.ClassUnspecified), lifetime.WithClass(lifetime.ClassStorage))
+
+	plan := lt.PlanShutdown()
+	if len(plan.Steps) != 3 || plan.Steps[0][0].Class != "stateless" || plan.Steps[2][0].Class != "storage" {
+		t.Errorf("expected a step per class, got %+v", plan.Steps)
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	if len(order) != 4 || order[0] != "api" || order[1] != "worker" {
+		t.Errorf("expected stateless, then stateful, then storage services to stop, got %v", order)
+	}
+}
@@ -0,0 +1,189 @@
+package lifetime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ConsumerFetchFunc fetches the next batch of messages from a broker.
+// It should block until messages are available or the context is done.
+type ConsumerFetchFunc func(ctx context.Context) ([]interface{}, error)
+
+// ConsumerHandleFunc processes a single message.
+type ConsumerHandleFunc func(ctx context.Context, msg interface{}) error
+
+// ConsumerCommitFunc acknowledges a single message once it has been handled successfully.
+type ConsumerCommitFunc func(ctx context.Context, msg interface{}) error
+
+// ConsumerOption is used to configure a consumer service.
+type ConsumerOption func(service *consumerService)
+
+// WithConsumerConcurrency sets the number of messages that are handled concurrently.
+// Defaults to 1.
+func WithConsumerConcurrency(concurrency int) ConsumerOption {
+	return func(service *consumerService) {
+		if concurrency > 0 {
+			service.concurrency = concurrency
+		}
+	}
+}
+
+// WithConsumerDrainTimeout sets how long in-flight messages are given to be handled and committed once the
+// service is stopped. Once the timeout is reached the context given to the handle and commit funcs is cancelled.
+// Defaults to 30 seconds.
+func WithConsumerDrainTimeout(timeout time.Duration) ConsumerOption {
+	return func(service *consumerService) {
+		service.drainTimeout = timeout
+	}
+}
+
+// WithConsumerErrorHandler sets a func that is called when a message could not be handled or committed.
+// If it returns nil the consumer carries on, otherwise the returned error is treated as fatal.
+// Defaults to treating every error as fatal.
+func WithConsumerErrorHandler(handler func(msg interface{}, err error) error) ConsumerOption {
+	return func(service *consumerService) {
+		service.errorHandler = handler
+	}
+}
+
+// NewConsumerService returns a service that consumes messages from any broker using the given funcs.
+// Messages are fetched in a loop and handed to a pool of workers. Each message that is handled
+// successfully is then committed.
+// When the service is stopped no more messages are fetched, and messages that are already being handled
+// are given time to finish. Messages that were fetched but not yet handed to a worker are not committed.
+func NewConsumerService(fetch ConsumerFetchFunc, handle ConsumerHandleFunc, commit ConsumerCommitFunc, opts ...ConsumerOption) Service {
+	service := &consumerService{
+		fetch:        fetch,
+		handle:       handle,
+		commit:       commit,
+		concurrency:  1,
+		drainTimeout: time.Second * 30,
+		errorHandler: func(msg interface{}, err error) error {
+			return err
+		},
+		stop: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service
+}
+
+// consumerService is an implementation of Service that consumes messages using the given funcs.
+type consumerService struct {
+	fetch        ConsumerFetchFunc
+	handle       ConsumerHandleFunc
+	commit       ConsumerCommitFunc
+	concurrency  int
+	drainTimeout time.Duration
+	errorHandler func(msg interface{}, err error) error
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// Start will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal.
+func (service *consumerService) Start() error {
+	fetchCtx, cancelFetch := context.WithCancel(context.Background())
+	defer cancelFetch()
+	handleCtx, cancelHandle := context.WithCancel(context.Background())
+	defer cancelHandle()
+
+	errs := make(chan error, service.concurrency+1)
+	fail := func(err error) {
+		errs <- err
+		cancelFetch()
+	}
+
+	go func() {
+		// Stop fetching as soon as we are told to stop, and give in-flight messages until
+		// the drain timeout to finish.
+		select {
+		case <-service.stop:
+		case <-fetchCtx.Done():
+		}
+		cancelFetch()
+		timer := time.NewTimer(service.drainTimeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancelHandle()
+		case <-handleCtx.Done():
+		}
+	}()
+
+	msgs := make(chan interface{})
+	workerWg := &sync.WaitGroup{}
+	for i := 0; i < service.concurrency; i++ {
+		workerWg.Add(1)
+		go func() {
+			defer workerWg.Done()
+			for msg := range msgs {
+				if err := service.process(handleCtx, msg); err != nil {
+					fail(err)
+					return
+				}
+			}
+		}()
+	}
+
+	service.fetchLoop(fetchCtx, msgs, fail)
+	close(msgs)
+	workerWg.Wait()
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return nil
+	}
+}
+
+// fetchLoop fetches messages and sends them to the workers until the context is done.
+func (service *consumerService) fetchLoop(ctx context.Context, msgs chan<- interface{}, fail func(err error)) {
+	for {
+		batch, err := service.fetch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			fail(err)
+			return
+		}
+		for _, msg := range batch {
+			select {
+			case msgs <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// process handles and commits a single message.
+func (service *consumerService) process(ctx context.Context, msg interface{}) error {
+	err := service.handle(ctx, msg)
+	if err == nil {
+		err = service.commit(ctx, msg)
+	}
+	if err == nil {
+		return nil
+	}
+	return service.errorHandler(msg, err)
+}
+
+// Stop will stop the service.
+// Stop is not called if Start returned an error.
+func (service *consumerService) Stop() {
+	service.stopOnce.Do(func() {
+		close(service.stop)
+	})
+}
+
+// Class returns ClassStateful, so the service is stopped once the stateless services have stopped.
+func (service *consumerService) Class() ServiceClass {
+	return ClassStateful
+}
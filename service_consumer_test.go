@@ -0,0 +1,62 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewConsumerService(t *testing.T) {
+	var mu sync.Mutex
+	next := 0
+	handled := map[int]bool{}
+	committed := map[int]bool{}
+
+	fetch := func(ctx context.Context) ([]interface{}, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		batch := []interface{}{next, next + 1}
+		next += 2
+		return batch, nil
+	}
+	handle := func(ctx context.Context, msg interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled[msg.(int)] = true
+		return nil
+	}
+	commit := func(ctx context.Context, msg interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !handled[msg.(int)] {
+			t.Errorf("message %d committed before being handled", msg)
+		}
+		committed[msg.(int)] = true
+		return nil
+	}
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(lifetime.NewConsumerService(fetch, handle, commit, lifetime.WithConsumerConcurrency(3)))
+
+	time.Sleep(time.Millisecond * 50)
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(committed) == 0 {
+		t.Fatalf("expected messages to be committed")
+	}
+	if len(committed) != len(handled) {
+		t.Errorf("expected every handled message to be committed: handled %d, committed %d", len(handled), len(committed))
+	}
+}
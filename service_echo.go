@@ -0,0 +1,14 @@
+package lifetime
+
+import (
+	"net/http"
+)
+
+// NewEchoService returns a service that serves the given echo instance on the given address.
+// The instance is served by a http.Server created by NewHTTPHandlerService, so the same defaults
+// and options apply, including giving in-flight requests time to complete when the service is stopped.
+// The handler is expected to be an *echo.Echo from github.com/labstack/echo/v4, although this package
+// does not depend on echo.
+func NewEchoService(e http.Handler, addr string, opts ...HTTPOption) Service {
+	return NewHTTPHandlerService(addr, e, opts...)
+}
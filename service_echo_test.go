@@ -0,0 +1,14 @@
+package lifetime_test
+
+import (
+	"github.com/tomwright/lifetime"
+	"net/http"
+	"testing"
+)
+
+// TestNewEchoService uses a http.ServeMux in place of an *echo.Echo, as both are a http.Handler.
+func TestNewEchoService(t *testing.T) {
+	testHandlerService(t, func(handler http.Handler, addr string) lifetime.Service {
+		return lifetime.NewEchoService(handler, addr)
+	})
+}
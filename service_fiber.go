@@ -0,0 +1,134 @@
+package lifetime
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// FiberApp is the part of a *fiber.App that is used by the fiber service.
+// It is satisfied by *fiber.App from github.com/gofiber/fiber/v2 without this package depending on fiber.
+type FiberApp interface {
+	// Listener serves HTTP requests from the given listener.
+	Listener(ln net.Listener) error
+	// ShutdownWithTimeout gracefully shuts down the server, closing any remaining connections once the timeout is reached.
+	ShutdownWithTimeout(timeout time.Duration) error
+}
+
+// FiberOption is used to configure a service created by NewFiberService.
+type FiberOption func(service *fiberService)
+
+// WithFiberShutdownTimeout sets how long in-flight requests are given to complete when the service
+// is stopped, after which any remaining connections are closed.
+// Defaults to 30 seconds.
+func WithFiberShutdownTimeout(timeout time.Duration) FiberOption {
+	return func(service *fiberService) {
+		service.shutdownTimeout = timeout
+	}
+}
+
+// NewFiberService returns a service that will listen and serve the given fiber app on the given address.
+// When the service is stopped the app is shutdown gracefully. See WithFiberShutdownTimeout.
+func NewFiberService(app FiberApp, addr string, opts ...FiberOption) Service {
+	service := &fiberService{
+		app:             app,
+		addr:            addr,
+		shutdownTimeout: time.Second * 30,
+		run:             newFiberRun(),
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service
+}
+
+// fiberService is an implementation of Service that will listen and serve the given fiber app.
+type fiberService struct {
+	app             FiberApp
+	addr            string
+	shutdownTimeout time.Duration
+
+	// runMu guards run.
+	runMu sync.Mutex
+	// run is the current run of the service. It is replaced once Start returns so that the service can be restarted.
+	run *fiberRun
+}
+
+// fiberRun tracks the readiness and shutdown of a single run of a fiber service.
+type fiberRun struct {
+	ready     chan struct{}
+	readyOnce sync.Once
+	// shutdown is closed once a graceful shutdown of the app has completed.
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+	// shutdownErr is the error returned when shutting down the app.
+	shutdownErr error
+}
+
+// newFiberRun returns a run that is neither ready nor shutdown.
+func newFiberRun() *fiberRun {
+	return &fiberRun{
+		ready:    make(chan struct{}),
+		shutdown: make(chan struct{}),
+	}
+}
+
+// currentRun returns the current run of the service.
+func (service *fiberService) currentRun() *fiberRun {
+	service.runMu.Lock()
+	defer service.runMu.Unlock()
+	return service.run
+}
+
+// nextRun replaces the current run of the service with a new one.
+func (service *fiberService) nextRun() {
+	service.runMu.Lock()
+	defer service.runMu.Unlock()
+	service.run = newFiberRun()
+}
+
+// Start will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal.
+func (service *fiberService) Start() error {
+	run := service.currentRun()
+	defer service.nextRun()
+
+	lis, err := net.Listen("tcp", service.addr)
+	if err != nil {
+		return fmt.Errorf("could not listen on tcp address: %w", err)
+	}
+	run.readyOnce.Do(func() {
+		close(run.ready)
+	})
+	if err := service.app.Listener(lis); err != nil {
+		return err
+	}
+	// Listener returns as soon as a shutdown begins so wait for in-flight requests to complete.
+	<-run.shutdown
+	if run.shutdownErr != nil {
+		return fmt.Errorf("could not shutdown fiber app: %w", run.shutdownErr)
+	}
+	return nil
+}
+
+// Stop will stop the service.
+// Stop is not called if Start returned an error.
+func (service *fiberService) Stop() {
+	run := service.currentRun()
+	run.shutdownOnce.Do(func() {
+		run.shutdownErr = service.app.ShutdownWithTimeout(service.shutdownTimeout)
+		close(run.shutdown)
+	})
+}
+
+// Class returns ClassStateless, so the service is stopped before any stateful services.
+func (service *fiberService) Class() ServiceClass {
+	return ClassStateless
+}
+
+// Ready returns a channel that is closed once the service is listening for connections.
+func (service *fiberService) Ready() <-chan struct{} {
+	return service.currentRun().ready
+}
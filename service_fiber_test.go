@@ -0,0 +1,71 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeFiberApp is a lifetime.FiberApp that accepts no requests and records how it was shutdown.
+type fakeFiberApp struct {
+	lis     chan net.Listener
+	timeout time.Duration
+}
+
+func (app *fakeFiberApp) Listener(ln net.Listener) error {
+	app.lis <- ln
+	for {
+		if _, err := ln.Accept(); err != nil {
+			return nil
+		}
+	}
+}
+
+func (app *fakeFiberApp) ShutdownWithTimeout(timeout time.Duration) error {
+	app.timeout = timeout
+	return (<-app.lis).Close()
+}
+
+func TestNewFiberService(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	app := &fakeFiberApp{lis: make(chan net.Listener, 1)}
+	lt.Start(lifetime.NewFiberService(app, "127.0.0.1:0", lifetime.WithFiberShutdownTimeout(time.Second)))
+
+	waitErr := make(chan error)
+	go func() {
+		waitErr <- lt.Wait()
+	}()
+	<-lt.Ready()
+
+	lt.Shutdown()
+	if err := <-waitErr; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if app.timeout != time.Second {
+		t.Errorf("expected shutdown timeout to be used, got %s", app.timeout)
+	}
+	if cause, err := lt.Cause(); cause != lifetime.CauseManual {
+		t.Errorf("expected manual shutdown, got %s: %v", cause, err)
+	}
+}
+
+func TestNewFiberService_Restart(t *testing.T) {
+	app := &fakeFiberApp{lis: make(chan net.Listener, 1)}
+	service := lifetime.NewFiberService(app, "127.0.0.1:0")
+
+	for i := 0; i < 2; i++ {
+		startErr := make(chan error)
+		go func() {
+			startErr <- service.Start()
+		}()
+		<-service.(lifetime.Readier).Ready()
+
+		service.Stop()
+		if err := <-startErr; err != nil {
+			t.Errorf("run %d: unexpected error: %v", i, err)
+		}
+	}
+}
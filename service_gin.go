@@ -0,0 +1,14 @@
+package lifetime
+
+import (
+	"net/http"
+)
+
+// NewGinService returns a service that serves the given gin engine on the given address.
+// The engine is served by a http.Server created by NewHTTPHandlerService, so the same defaults
+// and options apply, including giving in-flight requests time to complete when the service is stopped.
+// The handler is expected to be a *gin.Engine from github.com/gin-gonic/gin, although this package
+// does not depend on gin.
+func NewGinService(engine http.Handler, addr string, opts ...HTTPOption) Service {
+	return NewHTTPHandlerService(addr, engine, opts...)
+}
@@ -0,0 +1,14 @@
+package lifetime_test
+
+import (
+	"github.com/tomwright/lifetime"
+	"net/http"
+	"testing"
+)
+
+// TestNewGinService uses a http.ServeMux in place of a *gin.Engine, as both are a http.Handler.
+func TestNewGinService(t *testing.T) {
+	testHandlerService(t, func(handler http.Handler, addr string) lifetime.Service {
+		return lifetime.NewGinService(handler, addr)
+	})
+}
@@ -0,0 +1,120 @@
+package lifetime
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// NewSubscriptionTracker returns a tracker for active GraphQL subscriptions.
+func NewSubscriptionTracker() *SubscriptionTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SubscriptionTracker{
+		ctx:        ctx,
+		cancelFunc: cancel,
+		idle:       make(chan struct{}),
+	}
+}
+
+// SubscriptionTracker keeps track of active GraphQL subscriptions so that they can be completed
+// in an orderly fashion before the server is closed.
+//
+// Subscription resolvers should call Track and close the channel they return once the returned
+// context is done. gqlgen then sends a complete message to the client for the subscription.
+type SubscriptionTracker struct {
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+
+	mu     sync.Mutex
+	active int
+	// idle is closed when the tracker is draining and there are no active subscriptions.
+	idle     chan struct{}
+	draining bool
+}
+
+// Track registers an active subscription using the context given to the subscription resolver.
+// The returned context is done when the request context is done or the subscriptions are being drained.
+// The returned func must be called once the subscription has finished. It is safe to call it more than once.
+func (tracker *SubscriptionTracker) Track(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	tracker.mu.Lock()
+	if tracker.draining {
+		tracker.mu.Unlock()
+		cancel()
+		return ctx, func() {}
+	}
+	tracker.active++
+	tracker.mu.Unlock()
+
+	stopWatching := make(chan struct{})
+	go func() {
+		select {
+		case <-tracker.ctx.Done():
+			cancel()
+		case <-stopWatching:
+		}
+	}()
+
+	once := &sync.Once{}
+	return ctx, func() {
+		once.Do(func() {
+			close(stopWatching)
+			cancel()
+			tracker.release()
+		})
+	}
+}
+
+// release marks a single subscription as finished.
+func (tracker *SubscriptionTracker) release() {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	tracker.active--
+	if tracker.draining && tracker.active == 0 {
+		close(tracker.idle)
+	}
+}
+
+// Active returns the number of active subscriptions.
+func (tracker *SubscriptionTracker) Active() int {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	return tracker.active
+}
+
+// Drain tells all active subscriptions to complete and waits for them to finish, or for the given
+// context to be done. New subscriptions are completed straight away once a drain has started.
+// The context error is returned if the subscriptions did not finish in time.
+func (tracker *SubscriptionTracker) Drain(ctx context.Context) error {
+	tracker.mu.Lock()
+	if !tracker.draining {
+		tracker.draining = true
+		if tracker.active == 0 {
+			close(tracker.idle)
+		}
+	}
+	tracker.mu.Unlock()
+
+	tracker.cancelFunc()
+
+	select {
+	case <-tracker.idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NewGraphQLService returns a service that serves the given GraphQL handler, such as a gqlgen
+// handler.Server, on the given address.
+// When the service is stopped the active subscriptions are drained using the given tracker
+// before the server is shutdown, so clients receive a complete message for each subscription.
+// The drain and the server shutdown share the shutdown timeout. See WithHTTPShutdownTimeout.
+func NewGraphQLService(addr string, handler http.Handler, tracker *SubscriptionTracker, opts ...HTTPOption) Service {
+	service := NewHTTPHandlerService(addr, handler, opts...).(*httpHandlerService)
+	service.beforeShutdown = func(ctx context.Context) {
+		_ = tracker.Drain(ctx)
+	}
+	return service
+}
@@ -0,0 +1,56 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionTracker_Drain(t *testing.T) {
+	tracker := lifetime.NewSubscriptionTracker()
+
+	completed := make(chan struct{})
+	ctx, done := tracker.Track(context.Background())
+	go func() {
+		// Mimic a subscription resolver closing its channel once the context is done.
+		<-ctx.Done()
+		close(completed)
+		done()
+	}()
+
+	if active := tracker.Active(); active != 1 {
+		t.Errorf("expected 1 active subscription, got %d", active)
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := tracker.Drain(drainCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-completed:
+	default:
+		t.Errorf("expected subscription to be completed")
+	}
+	if active := tracker.Active(); active != 0 {
+		t.Errorf("expected 0 active subscriptions, got %d", active)
+	}
+
+	ctx, _ = tracker.Track(context.Background())
+	if ctx.Err() == nil {
+		t.Errorf("expected subscriptions started during a drain to be completed straight away")
+	}
+}
+
+func TestSubscriptionTracker_DrainTimeout(t *testing.T) {
+	tracker := lifetime.NewSubscriptionTracker()
+	_, _ = tracker.Track(context.Background())
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	if err := tracker.Drain(drainCtx); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
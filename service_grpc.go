@@ -3,23 +3,68 @@ package lifetime
 import (
 	"fmt"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 	"net"
+	"sync"
+	"time"
 )
 
+// GRPCOption is used to configure a service created by NewGRPCService.
+type GRPCOption func(service *grpcService)
+
+// WithGRPCShutdownTimeout sets how long in-flight RPCs are given to complete when the service is stopped.
+// Stopping the service sends a GOAWAY to every client so they stop sending new RPCs on their connection,
+// and once the timeout is reached any remaining connections are closed.
+// Defaults to 0, which waits for in-flight RPCs for as long as they take.
+func WithGRPCShutdownTimeout(timeout time.Duration) GRPCOption {
+	return func(service *grpcService) {
+		service.shutdownTimeout = timeout
+	}
+}
+
+// GRPCRollingDeployOptions returns server options that suit rolling deploys, to be given to grpc.NewServer.
+// Clients hold on to their connections, so without a maximum age they keep sending every RPC to the same
+// instances long after new ones have been started. Once a connection reaches maxConnectionAge the server
+// sends a GOAWAY, the client reconnects through the load balancer and in-flight RPCs are given grace to complete.
+// Use the same grace with WithGRPCShutdownTimeout so that a shutdown drains connections in the same way.
+// Keepalive pings are enforced leniently enough that clients with aggressive keepalive settings are not disconnected.
+func GRPCRollingDeployOptions(maxConnectionAge time.Duration, grace time.Duration) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionAge:      maxConnectionAge,
+			MaxConnectionAgeGrace: grace,
+			Time:                  time.Second * 30,
+			Timeout:               time.Second * 10,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             time.Second * 10,
+			PermitWithoutStream: true,
+		}),
+	}
+}
+
 // NewGRPCService returns a service that will run listen and serve the given
 // GRPC server.
-func NewGRPCService(server *grpc.Server, listenAddress string) Service {
-	return &grpcService{
+func NewGRPCService(server *grpc.Server, listenAddress string, opts ...GRPCOption) Service {
+	service := &grpcService{
 		server:        server,
 		listenAddress: listenAddress,
+		ready:         make(chan struct{}),
 	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service
 }
 
 // grpcService is an implementation of Service that will listen and serve the given
 // HTTP server.
 type grpcService struct {
-	server        *grpc.Server
-	listenAddress string
+	server          *grpc.Server
+	listenAddress   string
+	shutdownTimeout time.Duration
+	ready           chan struct{}
+	readyOnce       sync.Once
 }
 
 // Start will start the service.
@@ -30,6 +75,9 @@ func (service *grpcService) Start() error {
 	if err != nil {
 		return fmt.Errorf("could not listen on tcp address: %w", err)
 	}
+	service.readyOnce.Do(func() {
+		close(service.ready)
+	})
 	err = service.server.Serve(lis)
 	if err == nil {
 		return nil
@@ -45,5 +93,32 @@ func (service *grpcService) Start() error {
 // Stop will stop the service.
 // Stop is not called if Start returned an error.
 func (service *grpcService) Stop() {
-	service.server.GracefulStop()
+	if service.shutdownTimeout <= 0 {
+		service.server.GracefulStop()
+		return
+	}
+	stopped := make(chan struct{})
+	go func() {
+		service.server.GracefulStop()
+		close(stopped)
+	}()
+	timer := time.NewTimer(service.shutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+	case <-timer.C:
+		// Stop closes the remaining connections, which also causes GracefulStop to return.
+		service.server.Stop()
+		<-stopped
+	}
+}
+
+// Class returns ClassStateless, so the service is stopped before any stateful services.
+func (service *grpcService) Class() ServiceClass {
+	return ClassStateless
+}
+
+// Ready returns a channel that is closed once the service is listening for connections.
+func (service *grpcService) Ready() <-chan struct{} {
+	return service.ready
 }
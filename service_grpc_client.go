@@ -0,0 +1,128 @@
+package lifetime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc"
+	"sync"
+)
+
+// ErrGRPCClientConnNotFound is returned when a client connection is requested that has not been dialled.
+var ErrGRPCClientConnNotFound = errors.New("grpc client connection not found")
+
+// GRPCClientConns is a Service that owns a set of grpc.ClientConns.
+// The connections are dialled when the service is started and closed when it is stopped. As a ClassStorage
+// service it is stopped late in a shutdown, so RPCs made by other services while they drain still work.
+type GRPCClientConns struct {
+	targets []grpcTarget
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+
+	ready    chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// grpcTarget is a client connection that is dialled when the service is started.
+type grpcTarget struct {
+	name   string
+	target string
+	opts   []grpc.DialOption
+}
+
+// NewGRPCClientConns returns a service that owns a set of grpc.ClientConns. Use Add to register connections
+// before the service is started.
+func NewGRPCClientConns() *GRPCClientConns {
+	return &GRPCClientConns{
+		conns: map[string]*grpc.ClientConn{},
+		ready: make(chan struct{}),
+		stop:  make(chan struct{}),
+	}
+}
+
+// Add registers a connection to the given target that is dialled with the given options when the service is started.
+// It must be called before the service is started.
+func (conns *GRPCClientConns) Add(name string, target string, opts ...grpc.DialOption) *GRPCClientConns {
+	conns.targets = append(conns.targets, grpcTarget{name: name, target: target, opts: opts})
+	return conns
+}
+
+// Conn returns the connection with the given name, or ErrGRPCClientConnNotFound if it has not been dialled.
+func (conns *GRPCClientConns) Conn(name string) (*grpc.ClientConn, error) {
+	conns.mu.Lock()
+	defer conns.mu.Unlock()
+	conn, ok := conns.conns[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrGRPCClientConnNotFound, name)
+	}
+	return conn, nil
+}
+
+// StartContext will start the service.
+// Each connection is dialled with the given context, so a blocking dial is abandoned if the service is stopped.
+// If any connection can't be dialled the others are closed and the error is returned.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal.
+func (conns *GRPCClientConns) StartContext(ctx context.Context) error {
+	for _, target := range conns.targets {
+		conn, err := grpc.DialContext(ctx, target.target, target.opts...)
+		if err != nil {
+			conns.close()
+			return fmt.Errorf("could not dial %s: %w", target.name, err)
+		}
+		conns.mu.Lock()
+		select {
+		case <-conns.stop:
+			// The service was stopped while dialling.
+			conns.mu.Unlock()
+			_ = conn.Close()
+			return nil
+		default:
+		}
+		conns.conns[target.name] = conn
+		conns.mu.Unlock()
+	}
+	close(conns.ready)
+	<-conns.stop
+	return nil
+}
+
+// Start will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal.
+func (conns *GRPCClientConns) Start() error {
+	return conns.StartContext(context.Background())
+}
+
+// Stop will stop the service, closing every connection.
+// Stop is not called if Start returned an error.
+func (conns *GRPCClientConns) Stop() {
+	conns.mu.Lock()
+	conns.stopOnce.Do(func() {
+		close(conns.stop)
+	})
+	conns.mu.Unlock()
+	conns.close()
+}
+
+// close closes every connection that has been dialled.
+func (conns *GRPCClientConns) close() {
+	conns.mu.Lock()
+	defer conns.mu.Unlock()
+	for name, conn := range conns.conns {
+		_ = conn.Close()
+		delete(conns.conns, name)
+	}
+}
+
+// Class returns ClassStorage, so the connections are closed after the services that use them have stopped.
+func (conns *GRPCClientConns) Class() ServiceClass {
+	return ClassStorage
+}
+
+// Ready returns a channel that is closed once every connection has been dialled.
+func (conns *GRPCClientConns) Ready() <-chan struct{} {
+	return conns.ready
+}
@@ -0,0 +1,41 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"testing"
+)
+
+func TestGRPCClientConns(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	conns := lifetime.NewGRPCClientConns().
+		Add("users", "127.0.0.1:9000", grpc.WithInsecure()).
+		Add("orders", "127.0.0.1:9001", grpc.WithInsecure())
+	lt.Start(conns)
+
+	waitErr := make(chan error)
+	go func() {
+		waitErr <- lt.Wait()
+	}()
+	<-lt.Ready()
+
+	users, err := conns.Conn("users")
+	if err != nil {
+		t.Fatalf("expected the users connection to be dialled, got %v", err)
+	}
+	if _, err := conns.Conn("payments"); !errors.Is(err, lifetime.ErrGRPCClientConnNotFound) {
+		t.Errorf("expected ErrGRPCClientConnNotFound, got %v", err)
+	}
+
+	lt.Shutdown()
+	if err := <-waitErr; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if state := users.GetState(); state != connectivity.Shutdown {
+		t.Errorf("expected the connection to be closed, got %s", state)
+	}
+}
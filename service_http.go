@@ -1,7 +1,10 @@
 package lifetime
 
 import (
+	"fmt"
+	"net"
 	"net/http"
+	"sync"
 )
 
 // NewHTTPService returns a service that will run listen and serve the given
@@ -9,20 +12,34 @@ import (
 func NewHTTPService(server *http.Server) Service {
 	return &httpService{
 		server: server,
+		ready:  make(chan struct{}),
 	}
 }
 
 // httpService is an implementation of Service that will listen and serve the given
 // HTTP server.
 type httpService struct {
-	server *http.Server
+	server    *http.Server
+	ready     chan struct{}
+	readyOnce sync.Once
 }
 
 // Start will start the service.
 // This is a blocking call and should block for the lifetime of the service.
 // Returns an error which is treated as fatal.
 func (service *httpService) Start() error {
-	err := service.server.ListenAndServe()
+	addr := service.server.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("could not listen on tcp address: %w", err)
+	}
+	service.readyOnce.Do(func() {
+		close(service.ready)
+	})
+	err = service.server.Serve(lis)
 	if err == nil {
 		return nil
 	}
@@ -39,3 +56,13 @@ func (service *httpService) Start() error {
 func (service *httpService) Stop() {
 	_ = service.server.Close()
 }
+
+// Class returns ClassStateless, so the service is stopped before any stateful services.
+func (service *httpService) Class() ServiceClass {
+	return ClassStateless
+}
+
+// Ready returns a channel that is closed once the service is listening for connections.
+func (service *httpService) Ready() <-chan struct{} {
+	return service.ready
+}
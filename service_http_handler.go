@@ -0,0 +1,294 @@
+package lifetime
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HTTPOption is used to configure a service created by NewHTTPHandlerService.
+type HTTPOption func(service *httpHandlerService)
+
+// WithHTTPReadHeaderTimeout sets the ReadHeaderTimeout of the http.Server.
+// Defaults to 10 seconds.
+func WithHTTPReadHeaderTimeout(timeout time.Duration) HTTPOption {
+	return func(service *httpHandlerService) {
+		service.server.ReadHeaderTimeout = timeout
+	}
+}
+
+// WithHTTPReadTimeout sets the ReadTimeout of the http.Server.
+// Defaults to 30 seconds.
+func WithHTTPReadTimeout(timeout time.Duration) HTTPOption {
+	return func(service *httpHandlerService) {
+		service.server.ReadTimeout = timeout
+	}
+}
+
+// WithHTTPWriteTimeout sets the WriteTimeout of the http.Server.
+// Defaults to 30 seconds.
+func WithHTTPWriteTimeout(timeout time.Duration) HTTPOption {
+	return func(service *httpHandlerService) {
+		service.server.WriteTimeout = timeout
+	}
+}
+
+// WithHTTPIdleTimeout sets the IdleTimeout of the http.Server.
+// Defaults to 2 minutes.
+func WithHTTPIdleTimeout(timeout time.Duration) HTTPOption {
+	return func(service *httpHandlerService) {
+		service.server.IdleTimeout = timeout
+	}
+}
+
+// WithHTTPShutdownTimeout sets how long in-flight requests are given to complete when the service
+// is stopped, after which any remaining connections are closed.
+// Defaults to 30 seconds.
+func WithHTTPShutdownTimeout(timeout time.Duration) HTTPOption {
+	return func(service *httpHandlerService) {
+		service.shutdownTimeout = timeout
+	}
+}
+
+// WithHTTPCancelRequestsOnStop causes the context of every request to be done as soon as the
+// service is told to stop, rather than only once the request has completed.
+// This is useful for long running requests, such as streams, that would otherwise hold up the shutdown.
+func WithHTTPCancelRequestsOnStop() HTTPOption {
+	return func(service *httpHandlerService) {
+		service.cancelRequestsOnStop = true
+	}
+}
+
+// WithHTTPStagedDrain drains the server in steps when the service is stopped, to reduce the errors seen by
+// clients that connect to it directly rather than through a load balancer:
+//  1. For the announce period, requests continue to be served but every response has a Connection: close header,
+//     so that keep-alive connections are closed, and a Retry-After header with the given delay in seconds.
+//  2. The server is shutdown, giving in-flight requests the shutdown timeout to complete. See WithHTTPShutdownTimeout.
+//  3. Any connections that remain are closed.
+func WithHTTPStagedDrain(announce time.Duration, retryAfter time.Duration) HTTPOption {
+	return func(service *httpHandlerService) {
+		service.drainAnnounce = announce
+		service.drainRetryAfter = retryAfter
+	}
+}
+
+// WithHTTPTLSConfig serves HTTPS using the given tls.Config, which must provide a certificate
+// either through Certificates or GetCertificate, such as the one returned by CertReloader.TLSConfig.
+func WithHTTPTLSConfig(config *tls.Config) HTTPOption {
+	return func(service *httpHandlerService) {
+		service.server.TLSConfig = config
+	}
+}
+
+// WithHTTPNonCritical declares the service as one the application can run without. See NonCritical.
+func WithHTTPNonCritical() HTTPOption {
+	return func(service *httpHandlerService) {
+		service.nonCritical = true
+	}
+}
+
+// WithHTTPServerFunc executes the given func against the http.Server once the other options have been applied,
+// so that any other fields can be set.
+func WithHTTPServerFunc(fn func(server *http.Server)) HTTPOption {
+	return func(service *httpHandlerService) {
+		service.serverFuncs = append(service.serverFuncs, fn)
+	}
+}
+
+// NewHTTPHandlerService returns a service that serves the given handler on the given address
+// using a http.Server with sensible timeouts.
+// The context of each request is derived from the context of the service, so it holds any values
+// given to the service context. When the service is stopped, in-flight requests are given time
+// to complete before the server is closed. See WithHTTPShutdownTimeout.
+func NewHTTPHandlerService(addr string, handler http.Handler, opts ...HTTPOption) Service {
+	service := &httpHandlerService{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ReadHeaderTimeout: time.Second * 10,
+			ReadTimeout:       time.Second * 30,
+			WriteTimeout:      time.Second * 30,
+			IdleTimeout:       time.Minute * 2,
+		},
+		shutdownTimeout: time.Second * 30,
+		ready:           make(chan struct{}),
+		shutdown:        make(chan struct{}),
+		draining:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	for _, fn := range service.serverFuncs {
+		fn(service.server)
+	}
+	if service.drainAnnounce > 0 {
+		service.server.Handler = service.drainHandler(service.server.Handler)
+	}
+	return service
+}
+
+// httpHandlerService is an implementation of Service that serves a http.Handler using
+// a http.Server it manages itself.
+type httpHandlerService struct {
+	server               *http.Server
+	serverFuncs          []func(server *http.Server)
+	shutdownTimeout      time.Duration
+	cancelRequestsOnStop bool
+	// nonCritical is true if the application can run without the service. See WithHTTPNonCritical.
+	nonCritical bool
+	// drainAnnounce is how long responses announce that the server is draining before it is shutdown.
+	drainAnnounce   time.Duration
+	drainRetryAfter time.Duration
+	// draining is closed once the service has been told to stop, if WithHTTPStagedDrain is used.
+	draining     chan struct{}
+	drainingOnce sync.Once
+	// beforeShutdown is executed when the service is stopped, before the server is shutdown.
+	// It shares the shutdown timeout with the server.
+	beforeShutdown func(ctx context.Context)
+
+	ready     chan struct{}
+	readyOnce sync.Once
+	// shutdown is closed once a graceful shutdown of the server has completed.
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+// StartContext will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal.
+func (service *httpHandlerService) StartContext(ctx context.Context) error {
+	baseCtx := ctx
+	if !service.cancelRequestsOnStop {
+		baseCtx = valuesOnlyContext{parent: ctx}
+	}
+	service.server.BaseContext = func(lis net.Listener) context.Context {
+		return baseCtx
+	}
+
+	addr := service.server.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("could not listen on tcp address: %w", err)
+	}
+	service.readyOnce.Do(func() {
+		close(service.ready)
+	})
+	if service.server.TLSConfig != nil {
+		err = service.server.ServeTLS(lis, "", "")
+	} else {
+		err = service.server.Serve(lis)
+	}
+	if err == http.ErrServerClosed {
+		// Serve returns as soon as a shutdown begins so wait for in-flight requests to complete.
+		<-service.shutdown
+		return nil
+	}
+	return err
+}
+
+// Start will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal.
+func (service *httpHandlerService) Start() error {
+	return service.StartContext(context.Background())
+}
+
+// Stop will stop the service.
+// Stop is not called if Start returned an error.
+func (service *httpHandlerService) Stop() {
+	service.StopContext(context.Background())
+}
+
+// StopContext will stop the service, aiming to finish before the given context is done.
+// The drain announce period and the shutdown timeout are cut short if the context is done first.
+// StopContext is not called if Start returned an error.
+func (service *httpHandlerService) StopContext(ctx context.Context) {
+	if service.drainAnnounce > 0 {
+		service.drainingOnce.Do(func() {
+			close(service.draining)
+		})
+		service.server.SetKeepAlivesEnabled(false)
+		timer := time.NewTimer(service.drainAnnounce)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, service.shutdownTimeout)
+	defer cancel()
+	if service.beforeShutdown != nil {
+		service.beforeShutdown(ctx)
+	}
+	if err := service.server.Shutdown(ctx); err != nil {
+		_ = service.server.Close()
+	}
+	service.shutdownOnce.Do(func() {
+		close(service.shutdown)
+	})
+}
+
+// drainHandler returns a handler that adds the Connection and Retry-After headers to every response
+// once the service is draining. See WithHTTPStagedDrain.
+func (service *httpHandlerService) drainHandler(next http.Handler) http.Handler {
+	retryAfter := strconv.Itoa(int(math.Ceil(service.drainRetryAfter.Seconds())))
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		select {
+		case <-service.draining:
+			rw.Header().Set("Connection", "close")
+			rw.Header().Set("Retry-After", retryAfter)
+		default:
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// Class returns ClassStateless, so the service is stopped before any stateful services.
+func (service *httpHandlerService) Class() ServiceClass {
+	return ClassStateless
+}
+
+// Critical returns false if the service was created with WithHTTPNonCritical.
+func (service *httpHandlerService) Critical() bool {
+	return !service.nonCritical
+}
+
+// Ready returns a channel that is closed once the service is listening for connections.
+func (service *httpHandlerService) Ready() <-chan struct{} {
+	return service.ready
+}
+
+// valuesOnlyContext is a context that holds the values of its parent but is never done.
+type valuesOnlyContext struct {
+	parent context.Context
+}
+
+// Deadline returns no deadline.
+func (ctx valuesOnlyContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done returns nil, as the context is never done.
+func (ctx valuesOnlyContext) Done() <-chan struct{} {
+	return nil
+}
+
+// Err returns nil, as the context is never done.
+func (ctx valuesOnlyContext) Err() error {
+	return nil
+}
+
+// Value returns the value associated with the given key in the parent context.
+func (ctx valuesOnlyContext) Value(key interface{}) interface{} {
+	return ctx.parent.Value(key)
+}
@@ -0,0 +1,224 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPHandlerService(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not find a free port: %v", err)
+	}
+	addr := lis.Addr().String()
+	_ = lis.Close()
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	inFlight := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		close(inFlight)
+		<-release
+		if err := r.Context().Err(); err != nil {
+			t.Errorf("expected request context not to be done, got %v", err)
+		}
+		_, _ = rw.Write([]byte("hello"))
+	})
+	lt.Start(lifetime.NewHTTPHandlerService(addr, handler, lifetime.WithHTTPShutdownTimeout(time.Second*5)))
+
+	waitErr := make(chan error)
+	go func() {
+		waitErr <- lt.Wait()
+	}()
+	<-lt.Ready()
+
+	body := make(chan string)
+	go func() {
+		resp, err := http.Get("http://" + addr)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			body <- ""
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := ioutil.ReadAll(resp.Body)
+		body <- string(b)
+	}()
+
+	<-inFlight
+	lt.Shutdown()
+
+	select {
+	case <-waitErr:
+		t.Fatalf("expected Wait to block until the in-flight request completed")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	close(release)
+	if got := <-body; got != "hello" {
+		t.Errorf("expected in-flight request to complete, got %q", got)
+	}
+	if err := <-waitErr; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNewHTTPHandlerService_StagedDrain(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not find a free port: %v", err)
+	}
+	addr := lis.Addr().String()
+	_ = lis.Close()
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, _ = rw.Write([]byte("hello"))
+	})
+	lt.Start(lifetime.NewHTTPHandlerService(addr, handler,
+		lifetime.WithHTTPStagedDrain(time.Millisecond*500, time.Millisecond*1500),
+	))
+
+	waitErr := make(chan error)
+	go func() {
+		waitErr <- lt.Wait()
+	}()
+	<-lt.Ready()
+
+	resp, err := http.Get("http://" + addr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.Header.Get("Retry-After") != "" || resp.Close {
+		t.Errorf("expected no drain headers before the service was stopped")
+	}
+
+	lt.Shutdown()
+	deadline := time.Now().Add(time.Millisecond * 400)
+	for {
+		resp, err = http.Get("http://" + addr)
+		if err != nil {
+			t.Fatalf("expected requests to be served while draining, got %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.Header.Get("Retry-After") != "" || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	if got := resp.Header.Get("Retry-After"); got != "2" {
+		t.Errorf("expected Retry-After to be 2, got %q", got)
+	}
+	if !resp.Close {
+		t.Errorf("expected the connection to be closed")
+	}
+
+	if err := <-waitErr; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNewHTTPHandlerService_StopContext(t *testing.T) {
+	service := lifetime.NewHTTPHandlerService("127.0.0.1:0", http.NotFoundHandler(),
+		lifetime.WithHTTPStagedDrain(time.Second*10, time.Second),
+	)
+	stopper, ok := service.(lifetime.ContextStopper)
+	if !ok {
+		t.Fatalf("expected the service to implement ContextStopper")
+	}
+
+	startErr := make(chan error)
+	go func() {
+		startErr <- service.Start()
+	}()
+	<-service.(lifetime.Readier).Ready()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	started := time.Now()
+	stopper.StopContext(ctx)
+	if elapsed := time.Since(started); elapsed > time.Second*2 {
+		t.Errorf("expected the drain announce to be cut short by the context, took %s", elapsed)
+	}
+	if err := <-startErr; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	// A second stop must not panic.
+	stopper.StopContext(ctx)
+}
+
+// testHandlerService starts the service returned by newService with a handler that serves /hello, and checks that
+// it serves requests and gives an in-flight request time to complete when the lifetime is shutdown.
+func testHandlerService(t *testing.T, newService func(handler http.Handler, addr string) lifetime.Service) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not find a free port: %v", err)
+	}
+	addr := lis.Addr().String()
+	_ = lis.Close()
+
+	inFlight := make(chan struct{})
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hello", func(rw http.ResponseWriter, r *http.Request) {
+		_, _ = rw.Write([]byte("hello"))
+	})
+	mux.HandleFunc("/slow", func(rw http.ResponseWriter, r *http.Request) {
+		close(inFlight)
+		<-release
+		_, _ = rw.Write([]byte("slow"))
+	})
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(newService(mux, addr))
+	waitErr := make(chan error)
+	go func() {
+		waitErr <- lt.Wait()
+	}()
+	<-lt.Ready()
+
+	get := func(path string) string {
+		resp, err := http.Get("http://" + addr + path)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return ""
+		}
+		defer resp.Body.Close()
+		b, _ := ioutil.ReadAll(resp.Body)
+		return string(b)
+	}
+	if got := get("/hello"); got != "hello" {
+		t.Errorf("expected hello, got %q", got)
+	}
+
+	body := make(chan string)
+	go func() {
+		body <- get("/slow")
+	}()
+	<-inFlight
+	lt.Shutdown()
+
+	select {
+	case <-waitErr:
+		t.Fatalf("expected Wait to block until the in-flight request completed")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	close(release)
+	if got := <-body; got != "slow" {
+		t.Errorf("expected the in-flight request to complete, got %q", got)
+	}
+	if err := <-waitErr; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,273 @@
+package lifetime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PipelineSource produces the items that flow through a Pipeline, passing each to emit.
+// It should return once the context is done. Errors returned by emit should be returned.
+type PipelineSource func(ctx context.Context, emit func(item interface{}) error) error
+
+// PipelineStageFunc processes an item in a stage of a Pipeline, returning the item to pass to the next stage.
+// Returning a nil item filters it out. The output of the last stage is discarded.
+// The context is done if the item is still being processed when the drain timeout is reached.
+type PipelineStageFunc func(ctx context.Context, item interface{}) (interface{}, error)
+
+// PipelineOption is used to configure a Pipeline.
+type PipelineOption func(pipeline *Pipeline)
+
+// WithPipelineQueueSize sets the number of items that can be queued between each stage.
+// Defaults to 100.
+func WithPipelineQueueSize(size int) PipelineOption {
+	return func(pipeline *Pipeline) {
+		pipeline.queueSize = size
+	}
+}
+
+// WithPipelineDrainTimeout sets how long the stages have to drain their queues once the pipeline is stopped.
+// Items that have not been processed by then are dropped.
+// Defaults to 10 seconds.
+func WithPipelineDrainTimeout(timeout time.Duration) PipelineOption {
+	return func(pipeline *Pipeline) {
+		pipeline.drainTimeout = timeout
+	}
+}
+
+// PipelineStageStats describes how a single stage of a Pipeline has performed.
+type PipelineStageStats struct {
+	// Name is the name of the stage.
+	Name string
+	// Processed is the number of items the stage has processed, or emitted for the source.
+	Processed uint64
+	// Dropped is the number of items that were dropped because the drain timeout was reached.
+	Dropped uint64
+	// Pending is the number of items queued for the stage.
+	Pending int
+	// DrainDuration is how long the stage took to finish once the pipeline was stopped.
+	DrainDuration time.Duration
+}
+
+// NewPipeline returns a service that passes the items produced by the given source through stages
+// that are added with Stage, such as ingest, transform and sink.
+// When the pipeline is stopped the source is stopped first, and each stage then drains its queue before
+// the next stage is told that no more items are coming, so no item is lost between stages.
+func NewPipeline(sourceName string, source PipelineSource, opts ...PipelineOption) *Pipeline {
+	pipeline := &Pipeline{
+		source:       &pipelineStage{name: sourceName},
+		sourceFunc:   source,
+		queueSize:    100,
+		drainTimeout: time.Second * 10,
+		stop:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(pipeline)
+	}
+	return pipeline
+}
+
+// Pipeline is a Service that passes items through an ordered set of stages and shuts them down upstream-first.
+type Pipeline struct {
+	source       *pipelineStage
+	sourceFunc   PipelineSource
+	stages       []*pipelineStage
+	queueSize    int
+	drainTimeout time.Duration
+
+	mu sync.Mutex
+	// stopped is the time at which the pipeline was told to stop.
+	stopped time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// pipelineStage holds the state of a single stage of a pipeline.
+type pipelineStage struct {
+	name string
+	fn   PipelineStageFunc
+	// in is the queue of items for the stage.
+	in chan interface{}
+
+	mu        sync.Mutex
+	processed uint64
+	dropped   uint64
+	drained   time.Duration
+}
+
+// Stage adds a stage to the end of the pipeline. Stages must be added before the pipeline is started.
+func (pipeline *Pipeline) Stage(name string, fn PipelineStageFunc) *Pipeline {
+	pipeline.stages = append(pipeline.stages, &pipelineStage{
+		name: name,
+		fn:   fn,
+		in:   make(chan interface{}, pipeline.queueSize),
+	})
+	return pipeline
+}
+
+// Stats returns the stats of the source followed by each stage, in order.
+func (pipeline *Pipeline) Stats() []PipelineStageStats {
+	stats := make([]PipelineStageStats, 0, len(pipeline.stages)+1)
+	for _, stage := range append([]*pipelineStage{pipeline.source}, pipeline.stages...) {
+		stage.mu.Lock()
+		stats = append(stats, PipelineStageStats{
+			Name:          stage.name,
+			Processed:     stage.processed,
+			Dropped:       stage.dropped,
+			Pending:       len(stage.in),
+			DrainDuration: stage.drained,
+		})
+		stage.mu.Unlock()
+	}
+	return stats
+}
+
+// Start will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal if the source or any of the stages return an error.
+func (pipeline *Pipeline) Start() error {
+	drainCtx, cancelDrain := context.WithCancel(context.Background())
+	defer cancelDrain()
+	sourceCtx, cancelSource := context.WithCancel(drainCtx)
+	defer cancelSource()
+
+	var errOnce sync.Once
+	var firstErr error
+	failed := make(chan struct{})
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			close(failed)
+			cancelDrain()
+		})
+	}
+
+	var next chan interface{}
+	if len(pipeline.stages) > 0 {
+		next = pipeline.stages[0].in
+	}
+	wg := &sync.WaitGroup{}
+	wg.Add(len(pipeline.stages) + 1)
+	go func() {
+		defer wg.Done()
+		err := pipeline.sourceFunc(sourceCtx, func(item interface{}) error {
+			if err := pipeline.source.forward(sourceCtx, next, item); err != nil {
+				return err
+			}
+			pipeline.source.count()
+			return nil
+		})
+		if err != nil && sourceCtx.Err() == nil {
+			fail(fmt.Errorf("pipeline source %s: %w", pipeline.source.name, err))
+		}
+		pipeline.finish(pipeline.source, next)
+	}()
+	for i, stage := range pipeline.stages {
+		var out chan interface{}
+		if i+1 < len(pipeline.stages) {
+			out = pipeline.stages[i+1].in
+		}
+		go func(stage *pipelineStage, out chan interface{}) {
+			defer wg.Done()
+			stage.run(drainCtx, out, fail)
+			pipeline.finish(stage, out)
+		}(stage, out)
+	}
+
+	select {
+	case <-pipeline.stop:
+	case <-failed:
+	}
+	pipeline.mu.Lock()
+	pipeline.stopped = time.Now()
+	pipeline.mu.Unlock()
+	cancelSource()
+
+	timer := time.AfterFunc(pipeline.drainTimeout, cancelDrain)
+	wg.Wait()
+	timer.Stop()
+	return firstErr
+}
+
+// run processes the items queued for the stage until the queue is closed.
+// If the stage returns an error it is passed to fail, which is expected to cancel the given context.
+// Once the given context is done, any items that are left are dropped.
+func (stage *pipelineStage) run(ctx context.Context, out chan interface{}, fail func(err error)) {
+	for item := range stage.in {
+		if ctx.Err() != nil {
+			stage.drop()
+			continue
+		}
+		result, err := stage.fn(ctx, item)
+		if err != nil {
+			stage.drop()
+			if ctx.Err() == nil {
+				fail(fmt.Errorf("pipeline stage %s: %w", stage.name, err))
+			}
+			continue
+		}
+		stage.count()
+		if result != nil {
+			_ = stage.forward(ctx, out, result)
+		}
+	}
+}
+
+// count counts an item as processed.
+func (stage *pipelineStage) count() {
+	stage.mu.Lock()
+	stage.processed++
+	stage.mu.Unlock()
+}
+
+// forward passes the item to the given queue, if there is one.
+// The item is dropped if the given context is done before there is space in the queue.
+func (stage *pipelineStage) forward(ctx context.Context, out chan interface{}, item interface{}) error {
+	if out == nil {
+		return nil
+	}
+	select {
+	case out <- item:
+		return nil
+	case <-ctx.Done():
+		stage.drop()
+		return ctx.Err()
+	}
+}
+
+// drop counts an item as dropped.
+func (stage *pipelineStage) drop() {
+	stage.mu.Lock()
+	stage.dropped++
+	stage.mu.Unlock()
+}
+
+// finish records how long the stage took to drain and tells the next stage that no more items are coming.
+func (pipeline *Pipeline) finish(stage *pipelineStage, out chan interface{}) {
+	pipeline.mu.Lock()
+	stopped := pipeline.stopped
+	pipeline.mu.Unlock()
+	if !stopped.IsZero() {
+		stage.mu.Lock()
+		stage.drained = time.Since(stopped)
+		stage.mu.Unlock()
+	}
+	if out != nil {
+		close(out)
+	}
+}
+
+// Stop will stop the service.
+// Stop is not called if Start returned an error.
+func (pipeline *Pipeline) Stop() {
+	pipeline.stopOnce.Do(func() {
+		close(pipeline.stop)
+	})
+}
+
+// Class returns ClassStateful, so the service is stopped once the stateless services have stopped.
+func (pipeline *Pipeline) Class() ServiceClass {
+	return ClassStateful
+}
@@ -0,0 +1,90 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+	emitted := make(chan struct{})
+	source := func(ctx context.Context, emit func(item interface{}) error) error {
+		for i := 1; i <= 10; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+		close(emitted)
+		<-ctx.Done()
+		return nil
+	}
+
+	var mu sync.Mutex
+	sunk := make([]int, 0)
+	pipeline := lifetime.NewPipeline("ingest", source).
+		Stage("transform", func(ctx context.Context, item interface{}) (interface{}, error) {
+			return item.(int) * 2, nil
+		}).
+		Stage("sink", func(ctx context.Context, item interface{}) (interface{}, error) {
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			sunk = append(sunk, item.(int))
+			return nil, nil
+		})
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(pipeline)
+	<-emitted
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sunk) != 10 || sunk[9] != 20 {
+		t.Errorf("expected every item to reach the sink in order, got %v", sunk)
+	}
+	for _, stats := range pipeline.Stats() {
+		if stats.Processed != 10 || stats.Dropped != 0 || stats.Pending != 0 {
+			t.Errorf("unexpected stats for stage %s: %+v", stats.Name, stats)
+		}
+	}
+}
+
+func TestPipeline_StageError(t *testing.T) {
+	source := func(ctx context.Context, emit func(item interface{}) error) error {
+		for i := 0; ctx.Err() == nil; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	pipeline := lifetime.NewPipeline("ingest", source).
+		Stage("transform", func(ctx context.Context, item interface{}) (interface{}, error) {
+			if item.(int) == 5 {
+				return nil, errors.New("bad item")
+			}
+			return item, nil
+		})
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	var failed error
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceFailed {
+			failed = event.Err
+		}
+	})
+	lt.Start(pipeline)
+	_ = lt.Wait()
+
+	if failed == nil || !strings.Contains(failed.Error(), "pipeline stage transform: bad item") {
+		t.Errorf("expected the stage error to fail the service, got %v", failed)
+	}
+}
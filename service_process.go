@@ -0,0 +1,144 @@
+package lifetime
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ProcessOption is used to configure a process service. See ProcessService.
+type ProcessOption func(process *processService)
+
+// WithProcessStopSignal sets the signal that is sent to the process when the service is stopped,
+// rather than forwarding the signal that triggered the shutdown.
+func WithProcessStopSignal(sig os.Signal) ProcessOption {
+	return func(process *processService) {
+		process.stopSignal = sig
+	}
+}
+
+// WithProcessKillTimeout sets how long the process has to exit after it has been signalled before it is killed.
+// Defaults to 10 seconds.
+func WithProcessKillTimeout(timeout time.Duration) ProcessOption {
+	return func(process *processService) {
+		process.killTimeout = timeout
+	}
+}
+
+// ProcessService returns a service that runs the given command as a child process.
+// When the service is stopped the signal that triggered the shutdown is forwarded to the process, or SIGTERM
+// if the shutdown wasn't triggered by a signal, and the process is killed if it hasn't exited within the
+// kill timeout. If the process exits with an error before the service is stopped, the error is treated as fatal.
+// Use WithCascadeOrder to decide when the process is stopped.
+func (lifetime *Lifetime) ProcessService(cmd *exec.Cmd, opts ...ProcessOption) Service {
+	process := &processService{
+		lifetime:    lifetime,
+		cmd:         cmd,
+		killTimeout: time.Second * 10,
+		stop:        make(chan struct{}),
+		exited:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(process)
+	}
+	return process
+}
+
+// processService is a Service that runs a child process.
+type processService struct {
+	lifetime    *Lifetime
+	cmd         *exec.Cmd
+	stopSignal  os.Signal
+	killTimeout time.Duration
+
+	// mu ensures that the process is not started once the service has been stopped.
+	mu       sync.Mutex
+	running  bool
+	stop     chan struct{}
+	stopOnce sync.Once
+	// exited is closed once the process has exited.
+	exited chan struct{}
+}
+
+// Start will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal.
+func (process *processService) Start() error {
+	process.mu.Lock()
+	select {
+	case <-process.stop:
+		process.mu.Unlock()
+		return nil
+	default:
+	}
+	if err := process.cmd.Start(); err != nil {
+		process.mu.Unlock()
+		return fmt.Errorf("could not start process: %w", err)
+	}
+	process.running = true
+	process.mu.Unlock()
+
+	err := process.cmd.Wait()
+	close(process.exited)
+	select {
+	case <-process.stop:
+		return nil
+	default:
+	}
+	if err != nil {
+		return fmt.Errorf("process exited: %w", err)
+	}
+	return nil
+}
+
+// Stop will stop the service.
+// Stop is not called if Start returned an error.
+func (process *processService) Stop() {
+	process.mu.Lock()
+	process.stopOnce.Do(func() {
+		close(process.stop)
+	})
+	running := process.running
+	process.mu.Unlock()
+	if !running {
+		return
+	}
+
+	if err := process.cmd.Process.Signal(process.signal()); err != nil {
+		// The signal can't be delivered on every platform, such as SIGTERM on windows.
+		_ = process.cmd.Process.Kill()
+	}
+	timer := time.NewTimer(process.killTimeout)
+	defer timer.Stop()
+	select {
+	case <-process.exited:
+	case <-timer.C:
+		_ = process.cmd.Process.Kill()
+		<-process.exited
+	}
+}
+
+// Kill kills the process straight away. It is called if the process is still running once the shutdown
+// timeout has been exceeded. See Killer.
+func (process *processService) Kill() {
+	process.mu.Lock()
+	running := process.running
+	process.mu.Unlock()
+	if running {
+		_ = process.cmd.Process.Kill()
+	}
+}
+
+// signal returns the signal that is sent to the process when the service is stopped.
+func (process *processService) signal() os.Signal {
+	if process.stopSignal != nil {
+		return process.stopSignal
+	}
+	if signalErr, ok := process.lifetime.shutdownSignal(); ok {
+		return signalErr.Signal
+	}
+	return syscall.SIGTERM
+}
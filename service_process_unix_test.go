@@ -0,0 +1,87 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLifetime_ProcessService(t *testing.T) {
+	tests := map[string]struct {
+		signal syscall.Signal
+		opts   []lifetime.ProcessOption
+		exp    syscall.Signal
+	}{
+		"forwards the shutdown signal": {
+			signal: syscall.SIGINT,
+			exp:    syscall.SIGINT,
+		},
+		"stop signal": {
+			signal: syscall.SIGINT,
+			opts:   []lifetime.ProcessOption{lifetime.WithProcessStopSignal(syscall.SIGHUP)},
+			exp:    syscall.SIGHUP,
+		},
+		"kill timeout": {
+			signal: syscall.SIGINT,
+			opts: []lifetime.ProcessOption{
+				lifetime.WithProcessStopSignal(syscall.Signal(0)),
+				lifetime.WithProcessKillTimeout(time.Millisecond * 10),
+			},
+			exp: syscall.SIGKILL,
+		},
+	}
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			lt := lifetime.New(context.Background()).HandleErrors(nil)
+			lt.OnEvent(func(event lifetime.Event) {
+				if event.Type == lifetime.EventServiceStarted {
+					lt.ReportError(&lifetime.SignalError{Signal: tc.signal, Err: lifetime.ErrShutdownSignalReceived})
+				}
+			})
+
+			cmd := exec.Command("sleep", "10")
+			lt.Start(lt.ProcessService(cmd, tc.opts...))
+			_ = lt.Wait()
+
+			if cmd.ProcessState == nil {
+				t.Fatalf("expected the process to have been started")
+			}
+			status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+			if !ok || !status.Signaled() {
+				t.Fatalf("expected the process to be signalled, got %v", cmd.ProcessState)
+			}
+			if got := status.Signal(); got != tc.exp {
+				t.Errorf("expected %v, got %v", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestLifetime_ProcessService_Exited(t *testing.T) {
+	lt := lifetime.New(context.Background())
+	// The handler may still be running when Wait returns, so the error is received from a channel.
+	handled := make(chan error, 1)
+	lt.HandleErrors(func(e error) {
+		select {
+		case handled <- e:
+		default:
+		}
+		lt.Shutdown()
+	})
+	lt.Start(lt.ProcessService(exec.Command("false")))
+	_ = lt.Wait()
+	err := <-handled
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Errorf("expected an exec.ExitError, got %v", err)
+	}
+}
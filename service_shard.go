@@ -0,0 +1,312 @@
+package lifetime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ShardLease is a lease on a single shard of a stream, held by this instance.
+type ShardLease struct {
+	// ShardID is the ID of the shard.
+	ShardID string
+	// Checkpoint is the position to resume reading from. It is empty if the shard has never been checkpointed.
+	Checkpoint string
+}
+
+// ShardSource provides access to a shard based stream, such as Kinesis or DynamoDB Streams.
+type ShardSource interface {
+	// Acquire returns the shards that are currently leased to this instance, acquiring new leases if possible.
+	// It is called periodically, and shards that are no longer returned are assumed to have been taken over.
+	Acquire(ctx context.Context) ([]ShardLease, error)
+	// Read returns the next records from the shard after the given checkpoint, along with the checkpoint of
+	// the last record returned. It should block until records are available or the context is done.
+	Read(ctx context.Context, shardID string, checkpoint string) ([]interface{}, string, error)
+	// Checkpoint stores the position that has been processed up to in the shard.
+	Checkpoint(ctx context.Context, shardID string, checkpoint string) error
+	// Release gives up the lease on the shard so that another instance can pick it up straight away.
+	Release(ctx context.Context, shardID string) error
+}
+
+// ShardHandleFunc processes a single record from a shard.
+type ShardHandleFunc func(ctx context.Context, shardID string, record interface{}) error
+
+// ShardOption is used to configure a shard consumer service.
+type ShardOption func(service *shardConsumerService)
+
+// WithShardLeaseInterval sets how often leases are acquired and renewed.
+// Defaults to 10 seconds.
+func WithShardLeaseInterval(interval time.Duration) ShardOption {
+	return func(service *shardConsumerService) {
+		service.leaseInterval = interval
+	}
+}
+
+// WithShardCheckpointInterval sets how often each shard is checkpointed while it is being consumed.
+// Defaults to 5 seconds.
+func WithShardCheckpointInterval(interval time.Duration) ShardOption {
+	return func(service *shardConsumerService) {
+		service.checkpointInterval = interval
+	}
+}
+
+// WithShardShutdownTimeout sets how long in-flight records, the final checkpoints and lease releases are
+// given once the service is stopped.
+// Defaults to 30 seconds.
+func WithShardShutdownTimeout(timeout time.Duration) ShardOption {
+	return func(service *shardConsumerService) {
+		service.shutdownTimeout = timeout
+	}
+}
+
+// WithShardErrorHandler sets a func that is called when a record could not be handled, or a shard could not
+// be read or checkpointed. If it returns nil the consumer carries on, otherwise the returned error is treated as fatal.
+// Defaults to treating every error as fatal.
+func WithShardErrorHandler(handler func(shardID string, err error) error) ShardOption {
+	return func(service *shardConsumerService) {
+		service.errorHandler = handler
+	}
+}
+
+// NewShardConsumerService returns a service that consumes a shard based stream using the given source.
+// Each shard leased to this instance is consumed by its own worker, and records are passed to the handle func in order.
+// When the service is stopped each worker finishes the records it has already read, checkpoints the shard
+// and releases its lease so that another instance can pick the shard up without waiting for the lease to expire.
+func NewShardConsumerService(source ShardSource, handle ShardHandleFunc, opts ...ShardOption) Service {
+	service := &shardConsumerService{
+		source:             source,
+		handle:             handle,
+		leaseInterval:      time.Second * 10,
+		checkpointInterval: time.Second * 5,
+		shutdownTimeout:    time.Second * 30,
+		errorHandler: func(shardID string, err error) error {
+			return err
+		},
+		stop: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service
+}
+
+// shardConsumerService is an implementation of Service that consumes a shard based stream.
+type shardConsumerService struct {
+	source             ShardSource
+	handle             ShardHandleFunc
+	leaseInterval      time.Duration
+	checkpointInterval time.Duration
+	shutdownTimeout    time.Duration
+	errorHandler       func(shardID string, err error) error
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// shardWorker consumes a single shard.
+type shardWorker struct {
+	lease ShardLease
+	// lost is closed when the lease has been taken over by another instance.
+	lost chan struct{}
+	// done is closed once the worker has returned.
+	done chan struct{}
+}
+
+// Start will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal.
+func (service *shardConsumerService) Start() error {
+	readCtx, cancelRead := context.WithCancel(context.Background())
+	defer cancelRead()
+	handleCtx, cancelHandle := context.WithCancel(context.Background())
+	defer cancelHandle()
+
+	errs := make(chan error, 1)
+	fail := func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+		cancelRead()
+	}
+
+	go func() {
+		// Stop reading as soon as we are told to stop, and give the workers until the shutdown
+		// timeout to checkpoint and release their shards.
+		select {
+		case <-service.stop:
+		case <-readCtx.Done():
+		}
+		cancelRead()
+		timer := time.NewTimer(service.shutdownTimeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancelHandle()
+		case <-handleCtx.Done():
+		}
+	}()
+
+	workers := map[string]*shardWorker{}
+	service.leaseLoop(readCtx, handleCtx, workers, fail)
+	for _, worker := range workers {
+		<-worker.done
+	}
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return nil
+	}
+}
+
+// leaseLoop acquires leases on an interval until the given read context is done.
+func (service *shardConsumerService) leaseLoop(readCtx context.Context, handleCtx context.Context, workers map[string]*shardWorker, fail func(err error)) {
+	ticker := time.NewTicker(service.leaseInterval)
+	defer ticker.Stop()
+	for {
+		if err := service.balance(readCtx, handleCtx, workers, fail); err != nil {
+			fail(err)
+		}
+		select {
+		case <-ticker.C:
+		case <-readCtx.Done():
+			return
+		}
+	}
+}
+
+// balance starts workers for newly leased shards and stops workers for shards that are no longer leased.
+func (service *shardConsumerService) balance(readCtx context.Context, handleCtx context.Context, workers map[string]*shardWorker, fail func(err error)) error {
+	leases, err := service.source.Acquire(readCtx)
+	if readCtx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not acquire shard leases: %w", err)
+	}
+
+	leased := map[string]struct{}{}
+	for _, lease := range leases {
+		leased[lease.ShardID] = struct{}{}
+		if worker, ok := workers[lease.ShardID]; ok {
+			select {
+			case <-worker.done:
+				// The worker stopped after losing its lease, and the shard has been leased to us again.
+			default:
+				continue
+			}
+		}
+		worker := &shardWorker{
+			lease: lease,
+			lost:  make(chan struct{}),
+			done:  make(chan struct{}),
+		}
+		workers[lease.ShardID] = worker
+		go service.consume(readCtx, handleCtx, worker, fail)
+	}
+
+	for shardID, worker := range workers {
+		if _, ok := leased[shardID]; ok {
+			continue
+		}
+		select {
+		case <-worker.lost:
+		default:
+			close(worker.lost)
+		}
+	}
+	return nil
+}
+
+// consume reads and handles records from a single shard until the service is stopped or the lease is lost.
+func (service *shardConsumerService) consume(readCtx context.Context, handleCtx context.Context, worker *shardWorker, fail func(err error)) {
+	defer close(worker.done)
+
+	shardID := worker.lease.ShardID
+	checkpoint := worker.lease.Checkpoint
+	lastCheckpoint := checkpoint
+
+	ctx, cancel := context.WithCancel(readCtx)
+	defer cancel()
+	go func() {
+		select {
+		case <-worker.lost:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(service.checkpointInterval)
+	defer ticker.Stop()
+
+	for ctx.Err() == nil {
+		records, next, err := service.source.Read(ctx, shardID, checkpoint)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			if err = service.errorHandler(shardID, fmt.Errorf("could not read shard %s: %w", shardID, err)); err != nil {
+				fail(err)
+				return
+			}
+			continue
+		}
+		for _, record := range records {
+			if err := service.handle(handleCtx, shardID, record); err != nil {
+				if err = service.errorHandler(shardID, err); err != nil {
+					fail(err)
+					return
+				}
+			}
+		}
+		checkpoint = next
+
+		select {
+		case <-ticker.C:
+			if checkpoint != lastCheckpoint {
+				if err := service.source.Checkpoint(handleCtx, shardID, checkpoint); err != nil {
+					if err = service.errorHandler(shardID, fmt.Errorf("could not checkpoint shard %s: %w", shardID, err)); err != nil {
+						fail(err)
+						return
+					}
+				} else {
+					lastCheckpoint = checkpoint
+				}
+			}
+		default:
+		}
+	}
+
+	select {
+	case <-worker.lost:
+		// Another instance owns the shard now so we must not checkpoint or release it.
+		return
+	default:
+	}
+
+	if checkpoint != lastCheckpoint {
+		if err := service.source.Checkpoint(handleCtx, shardID, checkpoint); err != nil {
+			fail(fmt.Errorf("could not checkpoint shard %s: %w", shardID, err))
+			return
+		}
+	}
+	if err := service.source.Release(handleCtx, shardID); err != nil {
+		fail(fmt.Errorf("could not release shard %s: %w", shardID, err))
+	}
+}
+
+// Stop will stop the service.
+// Stop is not called if Start returned an error.
+func (service *shardConsumerService) Stop() {
+	service.stopOnce.Do(func() {
+		close(service.stop)
+	})
+}
+
+// Class returns ClassStateful, so the service is stopped once the stateless services have stopped.
+func (service *shardConsumerService) Class() ServiceClass {
+	return ClassStateful
+}
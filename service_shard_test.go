@@ -0,0 +1,104 @@
+package lifetime_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/tomwright/lifetime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryShardSource is an in-memory lifetime.ShardSource where each shard contains an endless sequence of numbers.
+type memoryShardSource struct {
+	mu          sync.Mutex
+	shards      []string
+	checkpoints map[string]string
+	released    map[string]bool
+}
+
+func (source *memoryShardSource) Acquire(ctx context.Context) ([]lifetime.ShardLease, error) {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	leases := make([]lifetime.ShardLease, 0)
+	for _, shardID := range source.shards {
+		leases = append(leases, lifetime.ShardLease{ShardID: shardID, Checkpoint: source.checkpoints[shardID]})
+	}
+	return leases, nil
+}
+
+func (source *memoryShardSource) Read(ctx context.Context, shardID string, checkpoint string) ([]interface{}, string, error) {
+	select {
+	case <-ctx.Done():
+		return nil, checkpoint, ctx.Err()
+	case <-time.After(time.Millisecond):
+	}
+	next := 0
+	if checkpoint != "" {
+		next, _ = strconv.Atoi(checkpoint)
+		next++
+	}
+	return []interface{}{next}, strconv.Itoa(next), nil
+}
+
+func (source *memoryShardSource) Checkpoint(ctx context.Context, shardID string, checkpoint string) error {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	source.checkpoints[shardID] = checkpoint
+	return nil
+}
+
+func (source *memoryShardSource) Release(ctx context.Context, shardID string) error {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	source.released[shardID] = true
+	return nil
+}
+
+func TestNewShardConsumerService(t *testing.T) {
+	source := &memoryShardSource{
+		shards:      []string{"shard-1", "shard-2"},
+		checkpoints: map[string]string{},
+		released:    map[string]bool{},
+	}
+
+	var mu sync.Mutex
+	handled := map[string]int{}
+	handle := func(ctx context.Context, shardID string, record interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if exp := handled[shardID]; record.(int) != exp {
+			return fmt.Errorf("expected record %d, got %v", exp, record)
+		}
+		handled[shardID]++
+		return nil
+	}
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(lifetime.NewShardConsumerService(source, handle, lifetime.WithShardCheckpointInterval(time.Hour)))
+
+	time.Sleep(time.Millisecond * 50)
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	if cause, err := lt.Cause(); cause != lifetime.CauseManual {
+		t.Fatalf("expected manual shutdown, got %s: %v", cause, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	for _, shardID := range source.shards {
+		if handled[shardID] == 0 {
+			t.Errorf("expected records to be handled for %s", shardID)
+		}
+		if exp := strconv.Itoa(handled[shardID] - 1); source.checkpoints[shardID] != exp {
+			t.Errorf("expected %s to be checkpointed at %s, got %s", shardID, exp, source.checkpoints[shardID])
+		}
+		if !source.released[shardID] {
+			t.Errorf("expected %s to be released", shardID)
+		}
+	}
+}
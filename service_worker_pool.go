@@ -0,0 +1,184 @@
+package lifetime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrWorkerPoolStopped is returned when an item is submitted to a WorkerPool that has been stopped.
+var ErrWorkerPoolStopped = errors.New("worker pool stopped")
+
+// WorkerPoolHandler processes a single item submitted to a WorkerPool.
+// The context is done if the item is still being processed when the drain timeout is reached.
+type WorkerPoolHandler func(ctx context.Context, item interface{})
+
+// WorkerPoolOption is used to configure a WorkerPool.
+type WorkerPoolOption func(pool *WorkerPool)
+
+// WithWorkerPoolQueueSize sets the number of items that can be queued before Submit blocks.
+// Defaults to 100.
+func WithWorkerPoolQueueSize(size int) WorkerPoolOption {
+	return func(pool *WorkerPool) {
+		pool.queueSize = size
+	}
+}
+
+// WithWorkerPoolDrainTimeout sets how long the pool has to finish queued and in-flight items once stopped.
+// Defaults to 10 seconds.
+func WithWorkerPoolDrainTimeout(timeout time.Duration) WorkerPoolOption {
+	return func(pool *WorkerPool) {
+		pool.drainTimeout = timeout
+	}
+}
+
+// WorkerPoolDropError is returned from the Start func of a WorkerPool when queued items were dropped
+// because they could not be processed before the drain timeout.
+type WorkerPoolDropError struct {
+	// Dropped is the number of items that were dropped.
+	Dropped int
+}
+
+// Error returns the error message.
+func (e *WorkerPoolDropError) Error() string {
+	return fmt.Sprintf("worker pool dropped %d items", e.Dropped)
+}
+
+// NewWorkerPool returns a service that processes submitted items using the given number of workers.
+// When the pool is stopped it stops accepting submissions and finishes the queued and in-flight
+// items, within the drain timeout.
+func NewWorkerPool(workers int, handler WorkerPoolHandler, opts ...WorkerPoolOption) *WorkerPool {
+	pool := &WorkerPool{
+		workers:      workers,
+		handler:      handler,
+		queueSize:    100,
+		drainTimeout: time.Second * 10,
+		stop:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(pool)
+	}
+	if pool.workers < 1 {
+		pool.workers = 1
+	}
+	pool.queue = make(chan interface{}, pool.queueSize)
+	return pool
+}
+
+// WorkerPool is a Service that processes items with a bounded number of workers.
+type WorkerPool struct {
+	workers      int
+	handler      WorkerPoolHandler
+	queueSize    int
+	drainTimeout time.Duration
+
+	queue chan interface{}
+
+	// mu is held for reading while an item is being submitted, so that the queue is not closed mid-submit.
+	mu      sync.RWMutex
+	stopped bool
+
+	droppedMu sync.Mutex
+	dropped   int
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// Submit queues an item to be processed, blocking until there is space in the queue or the given
+// context is done. It is safe to call from multiple go routines.
+// Returns ErrWorkerPoolStopped if the pool has been stopped.
+func (pool *WorkerPool) Submit(ctx context.Context, item interface{}) error {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	if pool.stopped {
+		return ErrWorkerPoolStopped
+	}
+	select {
+	case <-pool.stop:
+		return ErrWorkerPoolStopped
+	default:
+	}
+	select {
+	case pool.queue <- item:
+		return nil
+	case <-pool.stop:
+		return ErrWorkerPoolStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Pending returns the number of items that are queued and have not yet been picked up by a worker.
+func (pool *WorkerPool) Pending() int {
+	return len(pool.queue)
+}
+
+// Dropped returns the number of items that were dropped because they could not be processed
+// before the drain timeout.
+func (pool *WorkerPool) Dropped() int {
+	pool.droppedMu.Lock()
+	defer pool.droppedMu.Unlock()
+	return pool.dropped
+}
+
+// Start will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns a WorkerPoolDropError if any items were dropped while draining.
+func (pool *WorkerPool) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wg := &sync.WaitGroup{}
+	wg.Add(pool.workers)
+	for i := 0; i < pool.workers; i++ {
+		go func() {
+			defer wg.Done()
+			pool.work(ctx)
+		}()
+	}
+
+	<-pool.stop
+	pool.mu.Lock()
+	pool.stopped = true
+	close(pool.queue)
+	pool.mu.Unlock()
+
+	timer := time.AfterFunc(pool.drainTimeout, cancel)
+	wg.Wait()
+	timer.Stop()
+
+	if dropped := pool.Dropped(); dropped > 0 {
+		return &WorkerPoolDropError{Dropped: dropped}
+	}
+	return nil
+}
+
+// work processes items until the queue is closed and empty.
+// Items that are picked up once the drain timeout has been reached are dropped.
+func (pool *WorkerPool) work(ctx context.Context) {
+	for item := range pool.queue {
+		if ctx.Err() != nil {
+			pool.droppedMu.Lock()
+			pool.dropped++
+			pool.droppedMu.Unlock()
+			continue
+		}
+		pool.handler(ctx, item)
+	}
+}
+
+// Stop will stop the service.
+// Stop is not called if Start returned an error.
+func (pool *WorkerPool) Stop() {
+	pool.stopOnce.Do(func() {
+		close(pool.stop)
+	})
+}
+
+// Class returns ClassStateful, so the service is stopped once the stateless services have stopped.
+func (pool *WorkerPool) Class() ServiceClass {
+	return ClassStateful
+}
@@ -0,0 +1,76 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool(t *testing.T) {
+	var mu sync.Mutex
+	processed := 0
+	pool := lifetime.NewWorkerPool(2, func(ctx context.Context, item interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		processed++
+	})
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(pool)
+
+	for i := 0; i < 5; i++ {
+		if err := pool.Submit(context.Background(), i); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := pool.Submit(context.Background(), 5); !errors.Is(err, lifetime.ErrWorkerPoolStopped) {
+		t.Errorf("expected ErrWorkerPoolStopped, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if processed != 5 {
+		t.Errorf("expected 5 items to be processed, got %d", processed)
+	}
+}
+
+func TestWorkerPool_DrainTimeout(t *testing.T) {
+	pool := lifetime.NewWorkerPool(1, func(ctx context.Context, item interface{}) {
+		<-ctx.Done()
+	}, lifetime.WithWorkerPoolDrainTimeout(time.Millisecond*20))
+
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	var lateErr error
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceLateError {
+			lateErr = event.Err
+		}
+	})
+	lt.Start(pool)
+
+	for i := 0; i < 3; i++ {
+		if err := pool.Submit(context.Background(), i); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	if dropped := pool.Dropped(); dropped != 2 {
+		t.Errorf("expected 2 items to be dropped, got %d", dropped)
+	}
+	var dropErr *lifetime.WorkerPoolDropError
+	if !errors.As(lateErr, &dropErr) || dropErr.Dropped != 2 {
+		t.Errorf("expected a WorkerPoolDropError, got %v", lateErr)
+	}
+}
@@ -0,0 +1,85 @@
+package lifetime
+
+// ServiceState describes where a service is in its lifecycle.
+type ServiceState string
+
+const (
+	// ServiceStarting is used once a service has been started, until it is ready.
+	ServiceStarting ServiceState = "starting"
+	// ServiceRunning is used once a service is ready, until it is told to stop.
+	ServiceRunning ServiceState = "running"
+	// ServiceStopping is used once a service has been told to stop, until it has finished execution.
+	ServiceStopping ServiceState = "stopping"
+	// ServiceStopped is used once a service has been stopped and has finished execution.
+	ServiceStopped ServiceState = "stopped"
+	// ServiceFailed is used once a service has returned an error before it was stopped.
+	ServiceFailed ServiceState = "failed"
+	// ServiceDisabled is used for a service that was never executed because it has been disabled. See WithDisabledServices.
+	ServiceDisabled ServiceState = "disabled"
+)
+
+// StartNamed will start the given service with the given name. See Start and WithName.
+func (lifetime *Lifetime) StartNamed(name string, svc Service, opts ...StartOption) *ServiceHandle {
+	return lifetime.Start(svc, append(opts, WithName(name))...)
+}
+
+// Services returns a snapshot of every service that has been started, in the order they were first started,
+// including those that have since stopped or failed. Services registered with Lazy are included once they
+// have been started, and a service that has been restarted is described by its current instance.
+func (lifetime *Lifetime) Services() []ServiceStatus {
+	lifetime.servicesMu.Lock()
+	handles := append([]*ServiceHandle(nil), lifetime.handles...)
+	lifetime.servicesMu.Unlock()
+
+	statuses := make([]ServiceStatus, 0, len(handles))
+	for _, handle := range handles {
+		if managed := handle.current(); managed != nil {
+			statuses = append(statuses, managed.status())
+		}
+	}
+	return statuses
+}
+
+// register records the handle so that it is included by Services. It is safe to register a handle more than once.
+func (lifetime *Lifetime) register(handle *ServiceHandle) {
+	lifetime.servicesMu.Lock()
+	defer lifetime.servicesMu.Unlock()
+	lifetime.registerLocked(handle)
+}
+
+// registerLocked is the same as register, but the servicesMu of the lifetime must already be held.
+func (lifetime *Lifetime) registerLocked(handle *ServiceHandle) {
+	if handle.registered {
+		return
+	}
+	handle.registered = true
+	lifetime.handles = append(lifetime.handles, handle)
+}
+
+// state returns the current state of the service.
+func (managed *managedService) state() ServiceState {
+	managed.mu.Lock()
+	stopping, failed, disabled := managed.stopping, managed.failed, managed.disabled
+	managed.mu.Unlock()
+
+	switch {
+	case disabled:
+		return ServiceDisabled
+	case failed:
+		return ServiceFailed
+	}
+	select {
+	case <-managed.exited:
+		return ServiceStopped
+	default:
+	}
+	if stopping {
+		return ServiceStopping
+	}
+	select {
+	case <-managed.ready:
+		return ServiceRunning
+	default:
+		return ServiceStarting
+	}
+}
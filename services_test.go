@@ -0,0 +1,78 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+)
+
+func TestLifetime_Services(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithDisabledServices("reports")).HandleErrors(nil)
+
+	failed := make(chan struct{})
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceFailed {
+			close(failed)
+		}
+	})
+
+	api := lt.StartNamed("api", newBlockingService())
+	lt.StartNamed("cron", newBlockingService()).Stop()
+	lt.StartNamed("worker", &failingService{err: errors.New("queue unavailable")}, lifetime.NonCritical())
+	lt.StartNamed("reports", newBlockingService())
+	lt.StartNamed("search", &gatedService{blockingService: newBlockingService(), ready: make(chan struct{})})
+	lt.Lazy(newBlockingService(), lifetime.WithName("indexer"))
+	<-failed
+	if err := api.Ensure(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := map[string]lifetime.ServiceState{
+		"api":     lifetime.ServiceRunning,
+		"cron":    lifetime.ServiceStopped,
+		"worker":  lifetime.ServiceFailed,
+		"reports": lifetime.ServiceDisabled,
+		"search":  lifetime.ServiceStarting,
+	}
+	services := lt.Services()
+	if len(services) != len(exp) {
+		t.Fatalf("expected %d services, got %d", len(exp), len(services))
+	}
+	if services[0].Name != "api" {
+		t.Errorf("expected services in the order they were started, got %s first", services[0].Name)
+	}
+	for _, svc := range services {
+		if svc.State != exp[svc.Name] {
+			t.Errorf("expected %s to be %s, got %s", svc.Name, exp[svc.Name], svc.State)
+		}
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, svc := range lt.Services() {
+		if svc.Name == "api" && svc.State != lifetime.ServiceStopped {
+			t.Errorf("expected api to be stopped, got %s", svc.State)
+		}
+	}
+}
+
+func TestLifetime_Services_Duplicate(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(func(err error) {})
+
+	svc := newBlockingService()
+	lt.StartNamed("api", svc)
+	lt.StartNamed("duplicate", svc)
+
+	services := lt.Services()
+	if len(services) != 1 || services[0].Name != "api" {
+		t.Errorf("expected only the running service to be included, got %v", services)
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
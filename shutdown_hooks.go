@@ -0,0 +1,55 @@
+package lifetime
+
+import (
+	"sync"
+)
+
+// OnShutdown registers a func that is executed once when a shutdown begins, before any service is stopped.
+// Funcs are executed one at a time in the reverse of the order they were registered in.
+// A func that is registered once the shutdown has begun is executed straight away.
+// Use OnShutdownComplete for cleanup that must happen once the services have stopped, or OnStage for
+// cleanup that needs a context or can fail.
+func (lifetime *Lifetime) OnShutdown(fn func()) {
+	lifetime.shutdownHooks.add(fn)
+}
+
+// OnShutdownComplete registers a func that is executed once when a shutdown has completed, after every
+// shutdown stage. Wait does not return until the funcs have returned.
+// Funcs are executed one at a time in the reverse of the order they were registered in.
+// A func that is registered once the shutdown has completed is executed straight away.
+func (lifetime *Lifetime) OnShutdownComplete(fn func()) {
+	lifetime.shutdownCompleteHooks.add(fn)
+}
+
+// hooks is a set of funcs that are executed once.
+type hooks struct {
+	mu    sync.Mutex
+	funcs []func()
+	// ran is true once the funcs have been executed.
+	ran bool
+}
+
+// add registers the given func, or executes it straight away if the funcs have already been executed.
+func (hooks *hooks) add(fn func()) {
+	hooks.mu.Lock()
+	if hooks.ran {
+		hooks.mu.Unlock()
+		fn()
+		return
+	}
+	hooks.funcs = append(hooks.funcs, fn)
+	hooks.mu.Unlock()
+}
+
+// run executes the registered funcs in the reverse of the order they were registered in.
+func (hooks *hooks) run() {
+	hooks.mu.Lock()
+	funcs := hooks.funcs
+	hooks.funcs = nil
+	hooks.ran = true
+	hooks.mu.Unlock()
+
+	for i := len(funcs) - 1; i >= 0; i-- {
+		funcs[i]()
+	}
+}
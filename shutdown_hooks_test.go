@@ -0,0 +1,51 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"testing"
+)
+
+func TestLifetime_OnShutdown(t *testing.T) {
+	lt := lifetime.New(context.Background())
+
+	svc := newBlockingService()
+	lt.Start(svc)
+
+	var got []string
+	lt.OnShutdown(func() {
+		got = append(got, "first")
+	})
+	lt.OnShutdown(func() {
+		got = append(got, "second")
+		if svc.stopped {
+			t.Errorf("expected shutdown hooks to run before services are stopped")
+		}
+	})
+	lt.OnShutdownComplete(func() {
+		got = append(got, "complete")
+		if !svc.stopped {
+			t.Errorf("expected shutdown complete hooks to run once services are stopped")
+		}
+	})
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lt.OnShutdownComplete(func() {
+		got = append(got, "late")
+	})
+
+	exp := []string{"second", "first", "complete", "late"}
+	if len(got) != len(exp) {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+	for i := range exp {
+		if got[i] != exp[i] {
+			t.Errorf("expected %v, got %v", exp, got)
+			break
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package lifetime
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// ShutdownID returns the correlation ID of the shutdown, or an empty string if no shutdown has begun.
+// The ID is generated when the shutdown begins and is attached to every lifecycle log message and
+// event from then on, so the full shutdown of a single instance can be found in aggregated logs.
+func (lifetime *Lifetime) ShutdownID() string {
+	lifetime.shutdownIDMu.RLock()
+	defer lifetime.shutdownIDMu.RUnlock()
+	return lifetime.shutdownID
+}
+
+// startShutdownID generates the shutdown correlation ID.
+func (lifetime *Lifetime) startShutdownID() {
+	id := newShutdownID()
+	lifetime.shutdownIDMu.Lock()
+	lifetime.shutdownID = id
+	lifetime.shutdownIDMu.Unlock()
+}
+
+// newShutdownID returns a random ID.
+func newShutdownID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// Fall back to the time, which is unique enough to correlate the logs of a single instance.
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
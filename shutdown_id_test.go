@@ -0,0 +1,52 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+)
+
+func TestLifetime_ShutdownID(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	var mu sync.Mutex
+	events := make([]lifetime.Event, 0)
+	started := make(chan struct{})
+	lt.OnEvent(func(event lifetime.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+		if event.Type == lifetime.EventServiceStarted {
+			close(started)
+		}
+	})
+
+	lt.Start(newBlockingService())
+	<-started
+	if id := lt.ShutdownID(); id != "" {
+		t.Errorf("expected no shutdown ID before the shutdown, got %s", id)
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	id := lt.ShutdownID()
+	if id == "" {
+		t.Fatalf("expected a shutdown ID")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, event := range events {
+		if event.Type == lifetime.EventServiceStarted {
+			if event.ShutdownID != "" {
+				t.Errorf("expected events before the shutdown not to have a shutdown ID")
+			}
+			continue
+		}
+		if event.ShutdownID != id {
+			t.Errorf("expected %s event to have shutdown ID %s, got %q", event.Type, id, event.ShutdownID)
+		}
+	}
+}
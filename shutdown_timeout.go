@@ -0,0 +1,94 @@
+package lifetime
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrShutdownTimeout is returned by Wait when the shutdown did not complete within the shutdown timeout.
+var ErrShutdownTimeout = errors.New("shutdown timeout exceeded")
+
+// WithShutdownTimeout sets how long a graceful shutdown can take. Funcs registered against a shutdown
+// stage receive a context with the matching deadline.
+// If the shutdown has not completed in time, the services that are still running are logged and forcibly
+// terminated if they implement Killer, and Wait returns ErrShutdownTimeout without waiting for them.
+// ExitCode then returns ExitCodeShutdownTimeout. A timeout of 0 disables the timeout.
+// Defaults to 0.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.shutdownTimeout = timeout
+	}
+}
+
+// Killer can be implemented by a Service that can be forcibly terminated, such as one that runs an external process.
+// Kill is called if the service is still running once the shutdown timeout has been exceeded,
+// which is usually because Stop has not returned. See WithShutdownTimeout.
+type Killer interface {
+	// Kill forcibly terminates the service. It must not block.
+	Kill()
+}
+
+// WithDrainDelay sets how long to wait once a shutdown has been triggered before services are stopped.
+// Readiness is reported as false straight away, so the delay gives load balancers and service discovery
+// time to stop sending new traffic while the services continue to handle it.
+// The delay counts towards the shutdown timeout and is cut short by an immediate shutdown.
+// Defaults to 0.
+func WithDrainDelay(delay time.Duration) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.drainDelay = delay
+	}
+}
+
+// waitDrainDelay waits for the drain delay to pass.
+func (lifetime *Lifetime) waitDrainDelay() {
+	if lifetime.drainDelay <= 0 {
+		return
+	}
+	lifetime.log(LevelInfo, "lifetime waiting for drain delay", Field{Key: "delay", Value: lifetime.drainDelay})
+	timer := time.NewTimer(lifetime.drainDelay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-lifetime.immediate:
+	}
+}
+
+// watchShutdown closes the shutdownTimedOut channel if the shutdown has not completed by the given deadline.
+// It returns a func that must be called once the shutdown has completed.
+func (lifetime *Lifetime) watchShutdown(deadline time.Time) func() {
+	timer := time.NewTimer(time.Until(deadline))
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-timer.C:
+		case <-done:
+			timer.Stop()
+			return
+		}
+
+		lifetime.servicesMu.Lock()
+		remaining := make([]string, 0, len(lifetime.services))
+		var killers []*managedService
+		for managed := range lifetime.services {
+			remaining = append(remaining, managed.name)
+			if _, ok := managed.svc.(Killer); ok {
+				killers = append(killers, managed)
+			}
+		}
+		lifetime.servicesMu.Unlock()
+
+		lifetime.log(LevelError, "lifetime shutdown timeout exceeded",
+			Field{Key: "timeout", Value: lifetime.shutdownTimeout},
+			Field{Key: "remaining", Value: remaining},
+		)
+		lifetime.writeProfiles("timeout")
+		for _, managed := range killers {
+			managed.svc.(Killer).Kill()
+			managed.emit(EventServiceKilled, nil)
+		}
+		close(lifetime.shutdownTimedOut)
+	}()
+	return func() {
+		close(done)
+	}
+}
@@ -0,0 +1,38 @@
+package lifetime
+
+import (
+	"os"
+	"os/signal"
+)
+
+// SignalSource relays os signals to the lifetime. It is usually only replaced in tests. See WithSignalSource.
+type SignalSource interface {
+	// Notify causes the given signals to be relayed to the given channel.
+	Notify(c chan<- os.Signal, sigs ...os.Signal)
+}
+
+// osSignals is the SignalSource that relays the signals received by the process.
+type osSignals struct{}
+
+// Notify causes the given signals to be relayed to the given channel. See signal.Notify.
+func (osSignals) Notify(c chan<- os.Signal, sigs ...os.Signal) {
+	signal.Notify(c, sigs...)
+}
+
+// WithSignalSource sets where the signals that are listened for by HandleSignals and HandleLogReopen come from,
+// so that tests can send signals without signalling the test process.
+// Defaults to the signals received by the process.
+func WithSignalSource(source SignalSource) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.signalSource = source
+	}
+}
+
+// WithExitFunc sets the func that is used to exit the application on an immediate shutdown, so that tests can
+// record the exit code rather than exiting.
+// Defaults to os.Exit.
+func WithExitFunc(exit func(code int)) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.exit = exit
+	}
+}
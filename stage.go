@@ -0,0 +1,140 @@
+package lifetime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Stage is a named step of the shutdown sequence.
+type Stage string
+
+const (
+	// StageStopServices is the first shutdown stage, in which every running service is stopped
+	// so that no new work is taken in.
+	StageStopServices Stage = "stop-services"
+	// StageDrain runs once every service has stopped. It is where outbox publishers and internal
+	// queues should flush any work they are still holding.
+	StageDrain Stage = "drain"
+	// StageCloseStorage runs once the drain stage has completed. It is where connections to
+	// databases and other storage should be closed.
+	StageCloseStorage Stage = "close-storage"
+)
+
+// shutdownStages are the stages of the shutdown sequence, in the order they are executed.
+var shutdownStages = []Stage{StageStopServices, StageDrain, StageCloseStorage}
+
+// StageFunc is executed as part of a shutdown stage.
+// The context has a deadline if WithShutdownTimeout is used.
+type StageFunc func(ctx context.Context) error
+
+// stageFunc is a StageFunc that has been registered against a stage.
+type stageFunc struct {
+	name string
+	fn   StageFunc
+}
+
+// OnStage registers a func that is executed during the given stage of the shutdown sequence.
+// Funcs registered against the same stage are executed concurrently and the next stage is not
+// started until they have all returned. Errors are logged and attached to the stage events.
+// Once a func has been registered, Wait will not return until the shutdown sequence has completed.
+// Funcs registered after the shutdown sequence has completed are never executed.
+func (lifetime *Lifetime) OnStage(stage Stage, name string, fn StageFunc) {
+	lifetime.stagesMu.Lock()
+	defer lifetime.stagesMu.Unlock()
+
+	if lifetime.stagesDone {
+		lifetime.log(LevelWarn, "lifetime stage func registered after shutdown",
+			Field{Key: "stage", Value: string(stage)},
+			Field{Key: "name", Value: name},
+		)
+		return
+	}
+	if !lifetime.stagesPending {
+		// Make sure Wait blocks until the stages have been executed.
+		lifetime.stagesPending = true
+		lifetime.serviceWg.Add(1)
+	}
+	lifetime.stages[stage] = append(lifetime.stages[stage], stageFunc{name: name, fn: fn})
+}
+
+// RegisterDrain registers a func that is executed during the StageDrain stage of the shutdown sequence.
+// It is the equivalent of calling OnStage(StageDrain, name, fn).
+func (lifetime *Lifetime) RegisterDrain(name string, fn StageFunc) {
+	lifetime.OnStage(StageDrain, name, fn)
+}
+
+// runStage executes the given stage, along with any work the lifetime does itself in that stage.
+// The given context is passed to the stage funcs.
+func (lifetime *Lifetime) runStage(ctx context.Context, stage Stage, work func()) {
+	lifetime.stagesMu.Lock()
+	funcs := lifetime.stages[stage]
+	lifetime.stagesMu.Unlock()
+
+	lifetime.emit(Event{Type: EventStageStarted, Stage: stage})
+	lifetime.log(LevelDebug, "lifetime stage started", Field{Key: "stage", Value: string(stage)}, Field{Key: "funcs", Value: len(funcs)})
+	start := time.Now()
+
+	var errMu sync.Mutex
+	var firstErr error
+	runFunc := func(f stageFunc) {
+		if err := f.fn(ctx); err != nil {
+			err = &StageError{Stage: stage, Name: f.name, Err: err}
+			lifetime.log(LevelError, "lifetime stage func failed", errorFields(err)...)
+			lifetime.recordFailure(err)
+			errMu.Lock()
+			if firstErr == nil {
+				firstErr = err
+			}
+			errMu.Unlock()
+		}
+	}
+
+	if lifetime.deterministic != nil {
+		if work != nil {
+			work()
+		}
+		for _, f := range lifetime.deterministic.shuffleStageFuncs(funcs) {
+			runFunc(f)
+		}
+	} else {
+		wg := &sync.WaitGroup{}
+		wg.Add(len(funcs))
+		for _, f := range funcs {
+			go func(f stageFunc) {
+				defer wg.Done()
+				runFunc(f)
+			}(f)
+		}
+		if work != nil {
+			work()
+		}
+		wg.Wait()
+	}
+
+	duration := time.Since(start)
+	lifetime.emit(Event{Type: EventStageCompleted, Stage: stage, Err: firstErr, Duration: duration})
+	lifetime.log(LevelInfo, "lifetime stage complete",
+		Field{Key: "stage", Value: string(stage)},
+		Field{Key: "duration", Value: duration},
+	)
+}
+
+// runStages executes the shutdown stages in order. The given func is used to stop services.
+func (lifetime *Lifetime) runStages(ctx context.Context, stopServices func()) {
+	for _, stage := range shutdownStages {
+		if stage == StageStopServices {
+			lifetime.runStage(ctx, stage, stopServices)
+			continue
+		}
+		lifetime.runStage(ctx, stage, nil)
+	}
+
+	lifetime.stagesMu.Lock()
+	lifetime.stagesDone = true
+	pending := lifetime.stagesPending
+	lifetime.stagesMu.Unlock()
+	if pending {
+		lifetime.serviceWg.Done()
+	}
+}
@@ -0,0 +1,68 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+)
+
+func TestLifetime_OnStage(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	var mu sync.Mutex
+	order := make([]string, 0)
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+	}
+
+	svc := newBlockingService()
+	lt.Start(svc)
+
+	drainErr := errors.New("drain failed")
+	lt.OnStage(lifetime.StageCloseStorage, "db", func(ctx context.Context) error {
+		record("close-storage")
+		return nil
+	})
+	lt.RegisterDrain("outbox", func(ctx context.Context) error {
+		if !svc.stopped {
+			t.Errorf("expected services to be stopped before the drain stage")
+		}
+		record("drain")
+		return drainErr
+	})
+
+	var completed []lifetime.Event
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventStageCompleted {
+			mu.Lock()
+			defer mu.Unlock()
+			completed = append(completed, event)
+		}
+	})
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(order) != 2 || order[0] != "drain" || order[1] != "close-storage" {
+		t.Errorf("unexpected stage order: %v", order)
+	}
+	if len(completed) != 3 {
+		t.Fatalf("expected 3 stage completed events, got %d", len(completed))
+	}
+	if completed[1].Stage != lifetime.StageDrain {
+		t.Errorf("expected drain stage, got %s", completed[1].Stage)
+	}
+	var stageErr *lifetime.StageError
+	if !errors.As(completed[1].Err, &stageErr) || stageErr.Name != "outbox" || !errors.Is(stageErr, drainErr) {
+		t.Errorf("expected drain error, got %v", completed[1].Err)
+	}
+}
@@ -0,0 +1,111 @@
+package lifetime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// OnBeforeStart registers a func that is executed before any service is started, such as one that runs
+// database migrations. The funcs are executed one at a time in the order they were registered, using the
+// lifetime context, when the first service is started or when Wait is called, whichever happens first,
+// and that call blocks until they have returned. The funcs must not start services.
+// If a func returns an error the remaining funcs are not executed, the error is reported and no services
+// are executed. A func that is registered once the funcs have been executed is executed straight away.
+func (lifetime *Lifetime) OnBeforeStart(fn Initializer) {
+	if !lifetime.beforeStart.add(fn) {
+		lifetime.reportHookError("before start", fn(lifetime.ctx))
+	}
+}
+
+// OnAfterStart registers a func that is executed once the application is ready, see Ready, such as one
+// that registers the application with service discovery. The funcs are executed one at a time in the order
+// they were registered, using the lifetime context. If a func returns an error the remaining funcs are not
+// executed and the error is reported. A func that is registered once the funcs have been executed is
+// executed straight away.
+func (lifetime *Lifetime) OnAfterStart(fn Initializer) {
+	if !lifetime.afterStart.add(fn) {
+		lifetime.reportHookError("after start", fn(lifetime.ctx))
+	}
+}
+
+// runBeforeStart executes the funcs registered with OnBeforeStart if they haven't been executed yet,
+// and returns the error that stopped them, if any.
+func (lifetime *Lifetime) runBeforeStart() error {
+	ran, err := lifetime.beforeStart.run(lifetime.ctx)
+	if ran {
+		lifetime.reportHookError("before start", err)
+	}
+	return err
+}
+
+// runAfterStart executes the funcs registered with OnAfterStart if they haven't been executed yet.
+func (lifetime *Lifetime) runAfterStart() {
+	if ran, err := lifetime.afterStart.run(lifetime.ctx); ran {
+		lifetime.reportHookError("after start", err)
+	}
+}
+
+// reportHookError reports the given error from a start hook, if it isn't nil.
+func (lifetime *Lifetime) reportHookError(hook string, err error) {
+	if err != nil {
+		lifetime.reportError(fmt.Errorf("%s: %w", hook, err))
+	}
+}
+
+// startHooks is a set of funcs that are executed once, in the order they were registered.
+type startHooks struct {
+	// mu is held while the funcs are executed, so that callers of run wait for them to finish.
+	mu    sync.Mutex
+	funcs []Initializer
+	// ran is true once the funcs have been executed.
+	ran bool
+	// err is the error that stopped the funcs, if any.
+	err error
+}
+
+// add registers the given func, returning false if the funcs have already been executed.
+func (hooks *startHooks) add(fn Initializer) bool {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	if hooks.ran {
+		return false
+	}
+	hooks.funcs = append(hooks.funcs, fn)
+	return true
+}
+
+// run executes the funcs if they haven't been executed yet, stopping at the first error.
+// It returns true if this call executed them, and the error that stopped the funcs.
+func (hooks *startHooks) run(ctx context.Context) (bool, error) {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	if hooks.ran {
+		return false, hooks.err
+	}
+	hooks.ran = true
+	for _, fn := range hooks.funcs {
+		if err := fn(ctx); err != nil {
+			hooks.err = err
+			break
+		}
+	}
+	hooks.funcs = nil
+	return true, hooks.err
+}
+
+// abandon marks the service as having failed without executing it, because a func registered with
+// OnBeforeStart returned an error.
+func (managed *managedService) abandon() {
+	managed.failed = true
+	managed.ctx, managed.cancelFunc = context.WithCancel(managed.lifetime.ctx)
+	managed.cancelFunc()
+	close(managed.done)
+	close(managed.exited)
+	if managed.started != nil {
+		close(managed.started)
+	}
+	if !managed.replacing {
+		managed.handle.setManaged(managed)
+	}
+}
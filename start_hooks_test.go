@@ -0,0 +1,80 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+)
+
+func TestLifetime_OnBeforeStart(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	var mu sync.Mutex
+	var got []string
+	record := func(name string) {
+		mu.Lock()
+		got = append(got, name)
+		mu.Unlock()
+	}
+	lt.OnBeforeStart(func(ctx context.Context) error {
+		record("migrate")
+		return nil
+	})
+	lt.OnBeforeStart(func(ctx context.Context) error {
+		record("seed")
+		return nil
+	})
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceStarted {
+			record("service")
+		}
+	})
+
+	lt.Start(newBlockingService())
+	lt.Start(newBlockingService())
+
+	afterStart := make(chan struct{})
+	lt.OnAfterStart(func(ctx context.Context) error {
+		close(afterStart)
+		return nil
+	})
+	go func() {
+		<-afterStart
+		lt.Shutdown()
+	}()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 4 || got[0] != "migrate" || got[1] != "seed" {
+		t.Errorf("expected the hooks to run in order before the services, got %v", got)
+	}
+}
+
+func TestLifetime_OnBeforeStart_Error(t *testing.T) {
+	lt := lifetime.New(context.Background()).Init()
+
+	lt.OnBeforeStart(func(ctx context.Context) error {
+		return errors.New("migration failed")
+	})
+	svc := newBlockingService()
+	handle := lt.Start(svc)
+
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cause, err := lt.Cause(); cause != lifetime.CauseServiceError || err == nil {
+		t.Errorf("expected the shutdown to be caused by the error, got %s: %v", cause, err)
+	}
+	if services := lt.Services(); len(services) != 1 || services[0].State != lifetime.ServiceFailed {
+		t.Errorf("expected the service to have failed, got %v", services)
+	}
+	handle.Stop()
+	if svc.stopped {
+		t.Errorf("expected Stop not to be called on a service that was never executed")
+	}
+}
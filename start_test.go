@@ -0,0 +1,139 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+func TestLifetime_Start_Duplicate(t *testing.T) {
+	lt := lifetime.New(context.Background())
+
+	errs := make(chan error, 1)
+	lt.HandleErrors(func(err error) {
+		errs <- err
+	})
+
+	svc := newBlockingService()
+	lt.Start(svc)
+	lt.Start(svc)
+
+	err := <-errs
+	if !errors.Is(err, lifetime.ErrServiceAlreadyStarted) {
+		t.Errorf("expected ErrServiceAlreadyStarted, got %v", err)
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// contextService is a service that runs until its context is done.
+type contextService struct {
+	started chan struct{}
+}
+
+func (s *contextService) Start() error {
+	return errors.New("expected StartContext to be used")
+}
+
+func (s *contextService) StartContext(ctx context.Context) error {
+	close(s.started)
+	<-ctx.Done()
+	return nil
+}
+
+func (s *contextService) Stop() {}
+
+func TestServiceHandle_Context(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	svc := &contextService{started: make(chan struct{})}
+	handle := lt.Start(svc, lifetime.WithName("ctx"))
+	<-svc.started
+
+	if handle.Name() != "ctx" {
+		t.Errorf("expected name ctx, got %q", handle.Name())
+	}
+
+	handle.Stop()
+
+	if handle.Context().Err() == nil {
+		t.Errorf("expected service context to be done")
+	}
+	if lt.Context().Err() != nil {
+		t.Errorf("expected lifetime context to still be active")
+	}
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNonCritical(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	failed := make(chan lifetime.Event, 1)
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceFailed {
+			failed <- event
+		}
+	})
+
+	lt.Start(&failingService{err: errors.New("metrics unavailable")}, lifetime.NonCritical())
+	lt.Start(newBlockingService())
+
+	waitErr := make(chan error)
+	go func() {
+		waitErr <- lt.Wait()
+	}()
+
+	<-failed
+	select {
+	case <-lt.Ready():
+	case <-time.After(time.Second):
+		t.Fatalf("expected non-critical service not to hold back startup")
+	}
+	if lt.ShuttingDown() {
+		t.Errorf("expected non-critical failure not to trigger a shutdown")
+	}
+
+	lt.Shutdown()
+	if err := <-waitErr; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// nonCriticalService is a failing service that declares that the application can run without it.
+type nonCriticalService struct {
+	*failingService
+}
+
+func (s nonCriticalService) Critical() bool {
+	return false
+}
+
+func TestNonCritical_Declared(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	failed := make(chan struct{})
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceFailed {
+			close(failed)
+		}
+	})
+	lt.Start(nonCriticalService{&failingService{err: errors.New("metrics unavailable")}})
+	<-failed
+
+	lt.Shutdown()
+	if err := lt.WaitErr(); err != nil {
+		t.Errorf("expected the failure not to be reported, got %v", err)
+	}
+	if services := lt.Services(); len(services) != 1 || services[0].State != lifetime.ServiceFailed {
+		t.Errorf("expected the service to have failed, got %v", services)
+	}
+}
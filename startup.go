@@ -0,0 +1,85 @@
+package lifetime
+
+import (
+	"time"
+)
+
+// Ready returns a channel that is closed once the startup phase of the lifetime has completed.
+// The startup phase completes once Wait has been called, every service that was started before
+// then has reported that it is ready, other than those that are NonCritical unless WithStrictStartup
+// is used, every readiness gate added before then has passed and every warmer added before then
+// has returned.
+// It never completes if a shutdown is triggered first.
+func (lifetime *Lifetime) Ready() <-chan struct{} {
+	return lifetime.ready
+}
+
+// completeStartup marks the end of service registration and starts waiting for the
+// registered services to become ready.
+func (lifetime *Lifetime) completeStartup() {
+	lifetime.startupOnce.Do(func() {
+		_ = lifetime.runBeforeStart()
+
+		lifetime.servicesMu.Lock()
+		starting := make([]*managedService, 0, len(lifetime.services))
+		for managed := range lifetime.services {
+			starting = append(starting, managed)
+		}
+		lifetime.servicesMu.Unlock()
+
+		lifetime.gatesMu.Lock()
+		gates := make([]*readinessGate, len(lifetime.gates))
+		copy(gates, lifetime.gates)
+		lifetime.gatesMu.Unlock()
+
+		lifetime.warmersMu.Lock()
+		warmers := make([]*warmer, len(lifetime.warmers))
+		copy(warmers, lifetime.warmers)
+		lifetime.warmersMu.Unlock()
+
+		go lifetime.waitForReady(starting, gates, warmers)
+	})
+}
+
+// waitForReady waits for all of the given services to be ready, then runs the given warmers and waits
+// for them to return and for all of the given gates to pass, and then marks the lifetime as ready.
+func (lifetime *Lifetime) waitForReady(starting []*managedService, gates []*readinessGate, warmers []*warmer) {
+	for _, managed := range starting {
+		if managed.nonCritical && !lifetime.strictStartup {
+			continue
+		}
+		select {
+		case <-managed.ready:
+		case <-lifetime.ctx.Done():
+			return
+		}
+	}
+	lifetime.startWarmers(warmers)
+	for _, gate := range gates {
+		select {
+		case <-gate.passed:
+		case <-lifetime.ctx.Done():
+			return
+		}
+	}
+	for _, w := range warmers {
+		select {
+		case <-w.released:
+		case <-lifetime.ctx.Done():
+			return
+		}
+	}
+
+	// Make sure a shutdown that raced with the services becoming ready is still treated as a startup failure.
+	lifetime.causeMu.Lock()
+	if lifetime.cause != CauseNone {
+		lifetime.causeMu.Unlock()
+		return
+	}
+	close(lifetime.ready)
+	lifetime.causeMu.Unlock()
+
+	lifetime.log(LevelInfo, "lifetime ready", Field{Key: "services", Value: len(starting)})
+	lifetime.emit(Event{Type: EventReady, Duration: time.Since(lifetime.created)})
+	lifetime.runAfterStart()
+}
@@ -0,0 +1,140 @@
+package lifetime
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// WithStartupRollback changes how a failure during the startup phase is handled. Rather than stopping
+// services in the usual order, the services that have already started are rolled back by stopping them
+// one at a time in the reverse of the order they were started, and Wait returns a StartupReport describing
+// what started, what failed and what was rolled back.
+func WithStartupRollback() Option {
+	return func(lifetime *Lifetime) {
+		lifetime.rollback = &startupRollback{}
+	}
+}
+
+// ServiceFailure describes a service that returned an error.
+type ServiceFailure struct {
+	// Service is the name of the service.
+	Service string
+	// Err is the error returned by the service.
+	Err error
+}
+
+// StartupReport is returned by Wait when the startup failed and WithStartupRollback is used.
+type StartupReport struct {
+	// Started contains the names of the services that were started, in the order they were started.
+	Started []string
+	// Failed contains the services that failed during the startup phase, in the order they failed.
+	Failed []ServiceFailure
+	// RolledBack contains the names of the services that were stopped, in the order they were stopped.
+	RolledBack []string
+	// Errors contains any other errors that were returned while the services were rolled back.
+	Errors []error
+}
+
+// Error returns the error message.
+func (report *StartupReport) Error() string {
+	failures := make([]string, len(report.Failed))
+	for i, failure := range report.Failed {
+		failures[i] = failure.Err.Error()
+	}
+	msg := fmt.Sprintf("startup failed: %s", strings.Join(failures, "; "))
+	if len(report.RolledBack) > 0 {
+		msg += fmt.Sprintf(": rolled back %s", strings.Join(report.RolledBack, ", "))
+	}
+	if len(report.Errors) > 0 {
+		msg += fmt.Sprintf(": %d errors during rollback", len(report.Errors))
+	}
+	return msg
+}
+
+// Unwrap returns the error of the first service that failed.
+func (report *StartupReport) Unwrap() error {
+	if len(report.Failed) == 0 {
+		return nil
+	}
+	return report.Failed[0].Err
+}
+
+// startupRollback records what happens during a failed startup.
+type startupRollback struct {
+	mu     sync.Mutex
+	report StartupReport
+}
+
+// start records the events of the given lifetime.
+func (rollback *startupRollback) start(lifetime *Lifetime) {
+	lifetime.OnEvent(func(event Event) {
+		rollback.mu.Lock()
+		defer rollback.mu.Unlock()
+		switch event.Type {
+		case EventServiceFailed:
+			if !lifetime.isStarted() {
+				rollback.report.Failed = append(rollback.report.Failed, ServiceFailure{Service: event.Service, Err: event.Err})
+			}
+		case EventServiceStopped:
+			if lifetime.rollingBack() {
+				rollback.report.RolledBack = append(rollback.report.RolledBack, event.Service)
+			}
+		case EventServiceLateError, EventStageCompleted:
+			if event.Err != nil && lifetime.rollingBack() {
+				rollback.report.Errors = append(rollback.report.Errors, event.Err)
+			}
+		case EventShutdownComplete:
+			if lifetime.rollingBack() && len(rollback.report.Failed) > 0 {
+				report := rollback.report
+				lifetime.causeMu.Lock()
+				if lifetime.startupErr == nil {
+					lifetime.startupErr = &report
+				}
+				lifetime.causeMu.Unlock()
+			}
+		}
+	})
+}
+
+// recordStarted records that the service with the given name was started, if the startup phase has not completed.
+// It is called as the service is given its sequence number, so that the services are recorded in order.
+func (rollback *startupRollback) recordStarted(lifetime *Lifetime, name string) {
+	if lifetime.isStarted() {
+		return
+	}
+	rollback.mu.Lock()
+	defer rollback.mu.Unlock()
+	rollback.report.Started = append(rollback.report.Started, name)
+}
+
+// isStarted returns true once the startup phase has completed.
+func (lifetime *Lifetime) isStarted() bool {
+	select {
+	case <-lifetime.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// rollingBack returns true if WithStartupRollback is used and a service failed during the startup phase.
+func (lifetime *Lifetime) rollingBack() bool {
+	if lifetime.rollback == nil {
+		return false
+	}
+	lifetime.causeMu.Lock()
+	defer lifetime.causeMu.Unlock()
+	return lifetime.cause == CauseServiceError && lifetime.causeDuringStartup
+}
+
+// rollbackSteps returns a step for each of the given services, in the reverse of the order they were started.
+func rollbackSteps(running []*managedService) [][]*managedService {
+	ordered := append([]*managedService(nil), running...)
+	sortBySeq(ordered)
+	steps := make([][]*managedService, len(ordered))
+	for i, managed := range ordered {
+		steps[len(ordered)-1-i] = []*managedService{managed}
+	}
+	return steps
+}
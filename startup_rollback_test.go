@@ -0,0 +1,57 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"reflect"
+	"testing"
+)
+
+func TestWithStartupRollback(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithStartupRollback()).HandleErrors(nil)
+
+	started := make(chan struct{})
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceStarted && event.Service == "cache" {
+			close(started)
+		}
+	})
+	lt.Start(unreadyService{newBlockingService()}, lifetime.WithName("db"))
+	lt.Start(unreadyService{newBlockingService()}, lifetime.WithName("cache"))
+	<-started
+	failure := errors.New("port in use")
+	lt.Start(&failingService{err: failure}, lifetime.WithName("api"))
+
+	err := lt.Wait()
+	var report *lifetime.StartupReport
+	if !errors.As(err, &report) {
+		t.Fatalf("expected a StartupReport, got %v", err)
+	}
+	if !errors.Is(err, failure) {
+		t.Errorf("expected the report to wrap the failure")
+	}
+	if exp := []string{"db", "cache", "api"}; !reflect.DeepEqual(report.Started, exp) {
+		t.Errorf("expected started %v, got %v", exp, report.Started)
+	}
+	if len(report.Failed) != 1 || report.Failed[0].Service != "api" {
+		t.Errorf("expected api to have failed, got %+v", report.Failed)
+	}
+	if exp := []string{"cache", "db"}; !reflect.DeepEqual(report.RolledBack, exp) {
+		t.Errorf("expected services to be rolled back in reverse order %v, got %v", exp, report.RolledBack)
+	}
+}
+
+func TestWithStartupRollback_AfterReady(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithStartupRollback()).HandleErrors(nil)
+	lt.Start(newBlockingService())
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventReady {
+			lt.ReportError(errors.New("boom"))
+		}
+	})
+
+	if err := lt.Wait(); err != nil {
+		t.Errorf("expected no startup report once ready, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+// manualReadyService is a service that is ready once its ready channel is closed.
+type manualReadyService struct {
+	*blockingService
+	ready chan struct{}
+}
+
+func (s *manualReadyService) Ready() <-chan struct{} {
+	return s.ready
+}
+
+func TestLifetime_Ready(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	svc := &manualReadyService{blockingService: newBlockingService(), ready: make(chan struct{})}
+	lt.Start(svc)
+
+	events := make(chan lifetime.EventType, 10)
+	lt.OnEvent(func(event lifetime.Event) {
+		events <- event.Type
+	})
+
+	waitErr := make(chan error)
+	go func() {
+		waitErr <- lt.Wait()
+	}()
+
+	select {
+	case <-lt.Ready():
+		t.Fatalf("expected the lifetime not to be ready before the service")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	close(svc.ready)
+	select {
+	case <-lt.Ready():
+	case <-time.After(time.Second):
+		t.Fatalf("expected the lifetime to be ready once the service was ready")
+	}
+
+	seen := map[lifetime.EventType]bool{}
+	deadline := time.After(time.Second)
+	for !seen[lifetime.EventReady] {
+		select {
+		case eventType := <-events:
+			seen[eventType] = true
+		case <-deadline:
+			t.Fatalf("expected a ready event, got %v", seen)
+		}
+	}
+	if !seen[lifetime.EventServiceReady] {
+		t.Errorf("expected a service-ready event before the ready event, got %v", seen)
+	}
+
+	lt.Shutdown()
+	if err := <-waitErr; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package lifetime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrStartupTimeout is used when the application did not become ready within the startup timeout.
+var ErrStartupTimeout = errors.New("startup timeout exceeded")
+
+// StartupTimeoutError is returned by Wait when the application did not become ready within the startup timeout.
+type StartupTimeoutError struct {
+	// Timeout is the startup timeout.
+	Timeout time.Duration
+	// NotReady contains the names of the services that had not reported that they were ready, sorted.
+	NotReady []string
+}
+
+// Error returns the error message.
+func (e *StartupTimeoutError) Error() string {
+	if len(e.NotReady) == 0 {
+		return fmt.Sprintf("%s after %s", ErrStartupTimeout, e.Timeout)
+	}
+	return fmt.Sprintf("%s after %s: services not ready: %s", ErrStartupTimeout, e.Timeout, strings.Join(e.NotReady, ", "))
+}
+
+// Unwrap returns ErrStartupTimeout.
+func (e *StartupTimeoutError) Unwrap() error {
+	return ErrStartupTimeout
+}
+
+// WithStartupTimeout sets how long the application has to become ready, measured from when New is called.
+// If the application is not ready in time the startup is aborted: a shutdown is triggered with CauseTimeout,
+// the services that have already started are stopped and Wait returns a StartupTimeoutError listing the
+// services that never became ready. See Ready.
+// A timeout of 0 disables the timeout. Defaults to 0.
+func WithStartupTimeout(timeout time.Duration) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.startupTimeout = timeout
+	}
+}
+
+// watchStartup aborts the startup if the lifetime is not ready within the startup timeout.
+func (lifetime *Lifetime) watchStartup() {
+	timer := time.NewTimer(time.Until(lifetime.created.Add(lifetime.startupTimeout)))
+	defer timer.Stop()
+	select {
+	case <-lifetime.ready:
+		return
+	case <-lifetime.ctx.Done():
+		return
+	case <-timer.C:
+	}
+
+	err := &StartupTimeoutError{Timeout: lifetime.startupTimeout, NotReady: lifetime.notReady()}
+
+	lifetime.causeMu.Lock()
+	select {
+	case <-lifetime.ready:
+		// The lifetime became ready just as the timeout was reached.
+		lifetime.causeMu.Unlock()
+		return
+	default:
+	}
+	if lifetime.cause != CauseNone {
+		lifetime.causeMu.Unlock()
+		return
+	}
+	lifetime.cause = CauseTimeout
+	lifetime.causeErr = err
+	lifetime.causeDuringStartup = true
+	lifetime.startupErr = err
+	lifetime.causeMu.Unlock()
+
+	lifetime.log(LevelError, "lifetime startup timeout exceeded", errorFields(err)...)
+	lifetime.cancelFunc()
+}
+
+// notReady returns the names of the running services that have not reported that they are ready, sorted.
+// NonCritical services are not included as they do not hold back the startup phase, unless WithStrictStartup is used.
+func (lifetime *Lifetime) notReady() []string {
+	lifetime.servicesMu.Lock()
+	defer lifetime.servicesMu.Unlock()
+	names := make([]string, 0)
+	for managed := range lifetime.services {
+		if managed.nonCritical && !lifetime.strictStartup {
+			continue
+		}
+		select {
+		case <-managed.ready:
+		default:
+			names = append(names, managed.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// startupError returns the error that caused the startup to fail, if Wait should return it.
+func (lifetime *Lifetime) startupError() error {
+	lifetime.causeMu.Lock()
+	defer lifetime.causeMu.Unlock()
+	return lifetime.startupErr
+}
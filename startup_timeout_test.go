@@ -0,0 +1,56 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+// unreadyService is a blocking service that never reports that it is ready.
+type unreadyService struct {
+	*blockingService
+}
+
+func (s unreadyService) Ready() <-chan struct{} {
+	return make(chan struct{})
+}
+
+func TestWithStartupTimeout(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithStartupTimeout(time.Millisecond*20)).HandleErrors(nil)
+	lt.Start(newBlockingService(), lifetime.WithName("api"))
+	lt.Start(unreadyService{newBlockingService()}, lifetime.WithName("search"))
+
+	err := lt.Wait()
+	var timeoutErr *lifetime.StartupTimeoutError
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, lifetime.ErrStartupTimeout) {
+		t.Fatalf("expected a StartupTimeoutError, got %v", err)
+	}
+	if len(timeoutErr.NotReady) != 1 || timeoutErr.NotReady[0] != "search" {
+		t.Errorf("expected search to be reported as not ready, got %v", timeoutErr.NotReady)
+	}
+	if cause, _ := lt.Cause(); cause != lifetime.CauseTimeout {
+		t.Errorf("expected CauseTimeout, got %s", cause)
+	}
+	if code := lt.ExitCode(); code != lifetime.ExitCodeStartupFailure {
+		t.Errorf("expected exit code %d, got %d", lifetime.ExitCodeStartupFailure, code)
+	}
+}
+
+func TestWithStartupTimeout_Ready(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithStartupTimeout(time.Millisecond*20)).HandleErrors(nil)
+	lt.Start(newBlockingService())
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventReady {
+			go func() {
+				time.Sleep(time.Millisecond * 40)
+				lt.Shutdown()
+			}()
+		}
+	})
+
+	if err := lt.Wait(); err != nil {
+		t.Errorf("expected the timeout to have no effect once ready, got %v", err)
+	}
+}
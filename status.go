@@ -0,0 +1,144 @@
+package lifetime
+
+import (
+	"sort"
+)
+
+// Status is a snapshot of the state of the lifetime.
+type Status struct {
+	// Ready is true if the application is ready. See IsReady.
+	Ready bool `json:"ready"`
+	// Health is the overall health of the application. See Health.
+	Health Health `json:"health"`
+	// ShuttingDown is true once a shutdown has been triggered.
+	ShuttingDown bool `json:"shuttingDown"`
+	// Cause is the reason the shutdown was triggered, if any.
+	Cause string `json:"cause,omitempty"`
+	// Services contains the running services, sorted by name.
+	Services []ServiceStatus `json:"services"`
+	// Degraded contains the error message of each service that failed with an error classified
+	// as ActionDegrade, by the name of the service.
+	Degraded map[string]string `json:"degraded,omitempty"`
+	// Gates contains the readiness gates, in the order they were added.
+	Gates []GateStatus `json:"gates"`
+	// Warmers contains the warmers, in the order they were added.
+	Warmers []WarmerStatus `json:"warmers"`
+	// Barriers contains the barriers, sorted by name. See Barrier.
+	Barriers []BarrierStatus `json:"barriers"`
+	// Overloaded contains the sources that are reporting backpressure, sorted by name. See ReportBackpressure.
+	Overloaded []string `json:"overloaded,omitempty"`
+	// Plan describes what would happen if a shutdown was triggered now. See PlanShutdown.
+	Plan ShutdownPlan `json:"plan"`
+	// History contains the recorded transitions of the lifetime, oldest first. See History.
+	History []HistoryEntry `json:"history"`
+}
+
+// ServiceStatus describes a single running service.
+type ServiceStatus struct {
+	// Name is the name of the service.
+	Name string `json:"name"`
+	// Labels are the labels attached to the service.
+	Labels Labels `json:"labels,omitempty"`
+	// Class is the name of the class of the service. See ServiceClass.
+	Class string `json:"class"`
+	// Metadata is the metadata of the service, if it has any. See WithMetadata.
+	Metadata *ServiceMetadata `json:"metadata,omitempty"`
+	// Ready is true once the service has reported that it is ready.
+	Ready bool `json:"ready"`
+	// Stopping is true once the service has been told to stop.
+	Stopping bool `json:"stopping"`
+	// State is where the service is in its lifecycle.
+	State ServiceState `json:"state"`
+	// Paused is true while the service is paused. See Pauser.
+	Paused bool `json:"paused,omitempty"`
+}
+
+// GateStatus describes a single readiness gate.
+type GateStatus struct {
+	// Name is the name of the gate.
+	Name string `json:"name"`
+	// Healthy is true if the last check passed.
+	Healthy bool `json:"healthy"`
+	// Hard is true if the gate is checked for as long as the application is running.
+	Hard bool `json:"hard"`
+	// Error is the error returned by the last check, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Status returns a snapshot of the state of the lifetime.
+func (lifetime *Lifetime) Status() Status {
+	cause, _ := lifetime.Cause()
+	status := Status{
+		Ready:        lifetime.IsReady(),
+		Health:       lifetime.Health(),
+		ShuttingDown: lifetime.ctx.Err() != nil,
+		Services:     make([]ServiceStatus, 0),
+		Gates:        make([]GateStatus, 0),
+		Warmers:      make([]WarmerStatus, 0),
+		Barriers:     lifetime.barrierStatuses(),
+		Overloaded:   lifetime.Overloaded(),
+		Plan:         lifetime.PlanShutdown(),
+		History:      lifetime.History(),
+	}
+	if cause != CauseNone {
+		status.Cause = cause.String()
+	}
+
+	lifetime.servicesMu.Lock()
+	for managed := range lifetime.services {
+		status.Services = append(status.Services, managed.status())
+	}
+	lifetime.servicesMu.Unlock()
+	sort.Slice(status.Services, func(i, j int) bool {
+		return status.Services[i].Name < status.Services[j].Name
+	})
+
+	lifetime.degradedMu.Lock()
+	if len(lifetime.degraded) > 0 {
+		status.Degraded = make(map[string]string, len(lifetime.degraded))
+		for name, err := range lifetime.degraded {
+			status.Degraded[name] = err.Error()
+		}
+	}
+	lifetime.degradedMu.Unlock()
+
+	lifetime.gatesMu.Lock()
+	for _, gate := range lifetime.gates {
+		healthy, err := gate.state()
+		gateStatus := GateStatus{Name: gate.name, Healthy: healthy, Hard: gate.hard}
+		if err != nil {
+			gateStatus.Error = err.Error()
+		}
+		status.Gates = append(status.Gates, gateStatus)
+	}
+	lifetime.gatesMu.Unlock()
+
+	lifetime.warmersMu.Lock()
+	for _, w := range lifetime.warmers {
+		status.Warmers = append(status.Warmers, w.status())
+	}
+	lifetime.warmersMu.Unlock()
+
+	return status
+}
+
+// status returns the status of the service.
+func (managed *managedService) status() ServiceStatus {
+	status := ServiceStatus{
+		Name:     managed.name,
+		Labels:   managed.labels,
+		Class:    managed.class.String(),
+		Metadata: managed.metadata,
+	}
+	select {
+	case <-managed.ready:
+		status.Ready = true
+	default:
+	}
+	managed.mu.Lock()
+	status.Stopping = managed.stopping
+	managed.mu.Unlock()
+	status.Paused = managed.isPaused()
+	status.State = managed.state()
+	return status
+}
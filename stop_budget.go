@@ -0,0 +1,89 @@
+package lifetime
+
+import (
+	"context"
+	"time"
+)
+
+// WithStopBudget divides the time left before the shutdown timeout among the services that are being
+// stopped, so that no single slow service consumes the entire grace period and time is left for the
+// later shutdown stages. Each service receives a share in proportion to its weight, see WithStopWeight,
+// and services that implement ContextStopper receive a context with a deadline at the end of their share.
+// When services are stopped one at a time, as in deterministic mode, the time that is left is divided
+// again for each service, so the shares shrink as the deadline approaches.
+// It has no effect unless WithShutdownTimeout is used.
+func WithStopBudget() Option {
+	return func(lifetime *Lifetime) {
+		lifetime.stopBudget = true
+	}
+}
+
+// WithStopWeight sets the weight of the service when the stop budget is divided. See WithStopBudget.
+// Defaults to 1.
+func WithStopWeight(weight float64) StartOption {
+	return func(managed *managedService) {
+		if weight > 0 {
+			managed.stopWeight = weight
+		}
+	}
+}
+
+// stopBudget divides the time left before a deadline among services.
+type stopBudget struct {
+	deadline time.Time
+	// remainingWeight is the total weight of the services that have not yet been given a share.
+	remainingWeight float64
+}
+
+// newStopBudget returns a budget that divides the time before the given deadline among the given services.
+func newStopBudget(deadline time.Time, services []*managedService) *stopBudget {
+	budget := &stopBudget{deadline: deadline}
+	for _, managed := range services {
+		budget.remainingWeight += managed.weight()
+	}
+	return budget
+}
+
+// allocate returns the deadline of each service in the given step, which are stopped concurrently.
+// The time that is left is divided among all of the services that have not yet been given a share,
+// so steps that are stopped one after another receive shrinking shares.
+func (budget *stopBudget) allocate(step []*managedService) []time.Time {
+	return budget.allocateAt(time.Now(), step)
+}
+
+// allocateAt is the same as allocate, but as if it was called at the given time.
+func (budget *stopBudget) allocateAt(now time.Time, step []*managedService) []time.Time {
+	remaining := budget.deadline.Sub(now)
+	deadlines := make([]time.Time, len(step))
+	for i, managed := range step {
+		if remaining <= 0 || budget.remainingWeight <= 0 {
+			deadlines[i] = budget.deadline
+			continue
+		}
+		deadlines[i] = now.Add(time.Duration(float64(remaining) * managed.weight() / budget.remainingWeight))
+	}
+	for _, managed := range step {
+		budget.remainingWeight -= managed.weight()
+	}
+	return deadlines
+}
+
+// weight returns the weight of the service when dividing a stop budget.
+func (managed *managedService) weight() float64 {
+	if managed.stopWeight <= 0 {
+		return 1
+	}
+	return managed.stopWeight
+}
+
+// stopWithin stops the service with a context that is done at the given deadline.
+func (managed *managedService) stopWithin(ctx context.Context, deadline time.Time) {
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	managed.stopContext(ctx)
+	if time.Now().After(deadline) {
+		managed.log(LevelWarn, "lifetime service exceeded stop budget", nil,
+			Field{Key: "overrun", Value: time.Since(deadline)},
+		)
+	}
+}
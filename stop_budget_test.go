@@ -0,0 +1,67 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// budgetService is a lifetime.ContextStopper that records how long it was given to stop.
+type budgetService struct {
+	stop chan struct{}
+
+	mu     sync.Mutex
+	budget time.Duration
+}
+
+func (s *budgetService) Start() error {
+	<-s.stop
+	return nil
+}
+
+func (s *budgetService) Stop() {
+	close(s.stop)
+}
+
+func (s *budgetService) StopContext(ctx context.Context) {
+	deadline, _ := ctx.Deadline()
+	s.mu.Lock()
+	s.budget = time.Until(deadline)
+	s.mu.Unlock()
+	s.Stop()
+}
+
+func (s *budgetService) Budget() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.budget
+}
+
+func TestWithStopBudget(t *testing.T) {
+	lt := lifetime.New(context.Background(),
+		lifetime.WithShutdownTimeout(time.Second*4),
+		lifetime.WithStopBudget(),
+	).HandleErrors(nil)
+
+	light := &budgetService{stop: make(chan struct{})}
+	heavy := &budgetService{stop: make(chan struct{})}
+	lt.Start(light)
+	lt.Start(heavy, lifetime.WithStopWeight(3))
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	within := func(got time.Duration, exp time.Duration) bool {
+		return got > exp-time.Millisecond*200 && got <= exp
+	}
+	if got := light.Budget(); !within(got, time.Second) {
+		t.Errorf("expected light service to be given about 1s, got %s", got)
+	}
+	if got := heavy.Budget(); !within(got, time.Second*3) {
+		t.Errorf("expected heavy service to be given about 3s, got %s", got)
+	}
+}
@@ -0,0 +1,66 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// concurrencyCounter records the maximum number of services that are stopping at the same time.
+type concurrencyCounter struct {
+	mu      sync.Mutex
+	current int
+	max     int
+}
+
+func (c *concurrencyCounter) service() *countedStopService {
+	return &countedStopService{counter: c, stop: make(chan struct{})}
+}
+
+type countedStopService struct {
+	counter *concurrencyCounter
+	stop    chan struct{}
+}
+
+func (s *countedStopService) Start() error {
+	<-s.stop
+	return nil
+}
+
+func (s *countedStopService) Stop() {
+	s.counter.mu.Lock()
+	s.counter.current++
+	if s.counter.current > s.counter.max {
+		s.counter.max = s.counter.current
+	}
+	s.counter.mu.Unlock()
+
+	time.Sleep(time.Millisecond * 20)
+
+	s.counter.mu.Lock()
+	s.counter.current--
+	s.counter.mu.Unlock()
+	close(s.stop)
+}
+
+func TestWithStopConcurrency(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithStopConcurrency(2)).HandleErrors(nil)
+
+	counter := &concurrencyCounter{}
+	for i := 0; i < 7; i++ {
+		lt.Start(counter.service())
+	}
+
+	if steps := len(lt.PlanShutdown().Steps); steps != 4 {
+		t.Errorf("expected 4 steps, got %d", steps)
+	}
+
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	if counter.max != 2 {
+		t.Errorf("expected at most 2 services to stop at once, got %d", counter.max)
+	}
+}
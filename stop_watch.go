@@ -0,0 +1,113 @@
+package lifetime
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// defaultStopWarningThreshold is how long a service can take to stop before warnings are logged.
+const defaultStopWarningThreshold = time.Second * 10
+
+// WithStopWarning sets how long the Stop func of a service can take before a warning naming the service
+// is logged and an EventServiceStopSlow is emitted. Further warnings are logged each time the time waited
+// doubles, escalating to errors from the third warning onwards.
+// This is independent of any shutdown timeouts. A threshold of 0 disables the warnings.
+// Defaults to 10 seconds.
+func WithStopWarning(threshold time.Duration) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.stopWarningThreshold = threshold
+	}
+}
+
+// WithStopWarningStackDump causes a dump of all go routine stacks to be included in the first
+// warning logged for a slow Stop func, to help identify where the service is stuck.
+func WithStopWarningStackDump() Option {
+	return func(lifetime *Lifetime) {
+		lifetime.stopWarningStackDump = true
+	}
+}
+
+// watchStop logs escalating warnings while the given service is stopping.
+// It returns a func that must be called once the service has stopped.
+func (lifetime *Lifetime) watchStop(managed *managedService) func() {
+	if lifetime.stopWarningThreshold <= 0 {
+		return func() {}
+	}
+
+	watch := &stopWatch{
+		lifetime: lifetime,
+		managed:  managed,
+		start:    time.Now(),
+		next:     lifetime.stopWarningThreshold,
+	}
+	watch.mu.Lock()
+	watch.timer = time.AfterFunc(watch.next, watch.warn)
+	watch.mu.Unlock()
+
+	return watch.cancel
+}
+
+// stopWatch tracks a single service that is stopping.
+type stopWatch struct {
+	lifetime *Lifetime
+	managed  *managedService
+	start    time.Time
+
+	mu        sync.Mutex
+	timer     *time.Timer
+	next      time.Duration
+	warnings  int
+	cancelled bool
+}
+
+// warn logs a warning about the slow service and schedules the next warning.
+func (watch *stopWatch) warn() {
+	watch.mu.Lock()
+	defer watch.mu.Unlock()
+	if watch.cancelled {
+		return
+	}
+	watch.warnings++
+
+	waited := time.Since(watch.start)
+	level := LevelWarn
+	if watch.warnings >= 3 {
+		level = LevelError
+	}
+	fields := []Field{{Key: "waited", Value: waited.String()}}
+	if watch.warnings == 1 && watch.lifetime.stopWarningStackDump {
+		fields = append(fields, Field{Key: "goroutines", Value: string(stackDump())})
+	}
+	watch.managed.log(level, "lifetime service is slow to stop", nil, fields...)
+	watch.lifetime.emit(Event{
+		Type:     EventServiceStopSlow,
+		Service:  watch.managed.name,
+		Labels:   watch.managed.labels,
+		Metadata: watch.managed.metadata,
+		Duration: waited,
+	})
+
+	watch.next *= 2
+	watch.timer = time.AfterFunc(watch.next-waited, watch.warn)
+}
+
+// cancel stops any further warnings.
+func (watch *stopWatch) cancel() {
+	watch.mu.Lock()
+	defer watch.mu.Unlock()
+	watch.cancelled = true
+	watch.timer.Stop()
+}
+
+// stackDump returns the stack traces of all go routines.
+func stackDump() []byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+}
@@ -0,0 +1,22 @@
+package lifetime
+
+// WithStrictStartup makes the startup all-or-nothing, for batch jobs and other applications that must not
+// run with only some of their services.
+// The application is not ready until every service has reported that it is ready, including those that are
+// NonCritical. If any service fails during the startup phase, regardless of NonCritical and any error
+// policies, the services that have started are rolled back and Wait returns a StartupReport.
+// See WithStartupRollback.
+func WithStrictStartup() Option {
+	return func(lifetime *Lifetime) {
+		lifetime.strictStartup = true
+		if lifetime.rollback == nil {
+			lifetime.rollback = &startupRollback{}
+		}
+	}
+}
+
+// strictFailure returns true if a service failure must fail the startup because WithStrictStartup is used
+// and the startup phase has not completed.
+func (lifetime *Lifetime) strictFailure() bool {
+	return lifetime.strictStartup && !lifetime.isStarted()
+}
@@ -0,0 +1,53 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+func TestWithStrictStartup(t *testing.T) {
+	lt := lifetime.New(context.Background(), lifetime.WithStrictStartup()).HandleErrors(nil)
+	lt.AddErrorPolicy(lifetime.ErrorMatches(func(err error) bool { return true }, lifetime.ActionIgnore))
+
+	started := make(chan struct{})
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventServiceStarted && event.Service == "api" {
+			close(started)
+		}
+	})
+	lt.Start(unreadyService{newBlockingService()}, lifetime.WithName("api"))
+	<-started
+	lt.Start(&failingService{err: errors.New("no data")}, lifetime.WithName("loader"), lifetime.NonCritical())
+
+	err := lt.Wait()
+	var report *lifetime.StartupReport
+	if !errors.As(err, &report) {
+		t.Fatalf("expected a StartupReport, got %v", err)
+	}
+	if len(report.Failed) != 1 || report.Failed[0].Service != "loader" {
+		t.Errorf("expected the non-critical service to fail the startup, got %+v", report.Failed)
+	}
+	if len(report.RolledBack) != 1 || report.RolledBack[0] != "api" {
+		t.Errorf("expected api to be rolled back, got %v", report.RolledBack)
+	}
+}
+
+func TestWithStrictStartup_NonCriticalReadiness(t *testing.T) {
+	lt := lifetime.New(context.Background(),
+		lifetime.WithStrictStartup(),
+		lifetime.WithStartupTimeout(time.Millisecond*20),
+	).HandleErrors(nil)
+	lt.Start(newBlockingService(), lifetime.WithName("api"))
+	lt.Start(unreadyService{newBlockingService()}, lifetime.WithName("metrics"), lifetime.NonCritical())
+
+	var timeoutErr *lifetime.StartupTimeoutError
+	if err := lt.Wait(); !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected the non-critical service to hold back readiness, got %v", err)
+	}
+	if len(timeoutErr.NotReady) != 1 || timeoutErr.NotReady[0] != "metrics" {
+		t.Errorf("expected metrics to be reported as not ready, got %v", timeoutErr.NotReady)
+	}
+}
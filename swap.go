@@ -0,0 +1,74 @@
+package lifetime
+
+import (
+	"fmt"
+)
+
+// Swap replaces the service with the given service, with the same options, without a gap in between.
+// The replacement is started and, once it is ready, the handle is switched over to it and the current
+// instance is stopped, so that components such as listeners can be replaced with new configuration.
+// Services that implement StateHandoff carry their state over to the replacement.
+// If the replacement fails before it is ready, the current instance is left running and the error the
+// replacement failed with is returned, without being passed to the error policies or the error handler.
+// If the replacement exits for any other reason, such as being disabled, ErrServiceExited is returned.
+// ErrShuttingDown is returned if the application is shutting down.
+// For a service registered with Lazy that has not been started, the replacement is started by Ensure.
+func (handle *ServiceHandle) Swap(svc Service) error {
+	handle.ensureMu.Lock()
+	defer handle.ensureMu.Unlock()
+
+	if handle.lifetime.ctx.Err() != nil {
+		return ErrShuttingDown
+	}
+	old := handle.current()
+	if old == nil || handle.idleStopped {
+		handle.mu.Lock()
+		handle.svc = svc
+		handle.mu.Unlock()
+		return nil
+	}
+
+	replacement := handle.lifetime.newInstance(handle, svc)
+	replacement.replacing = true
+	if state := snapshot(old.svc); state != nil {
+		handle.setHandoffState(state)
+	}
+	if handle.lifetime.launch(replacement) != handle {
+		return replacement.wrapError(ErrServiceAlreadyStarted)
+	}
+
+	select {
+	case <-replacement.ready:
+	case <-replacement.exited:
+	}
+	replacement.mu.Lock()
+	running := !replacement.failed && !replacement.stopping && !replacement.disabled
+	if running {
+		replacement.replacing = false
+	}
+	replacement.mu.Unlock()
+	if !running {
+		<-replacement.exited
+		return replacement.swapError()
+	}
+
+	handle.mu.Lock()
+	handle.svc = svc
+	handle.managed = replacement
+	handle.mu.Unlock()
+
+	old.stop()
+	handle.lifetime.emit(Event{Type: EventServiceSwapped, Service: replacement.name, Labels: replacement.labels, Metadata: replacement.metadata})
+	replacement.log(LevelInfo, "lifetime "+string(EventServiceSwapped), nil)
+	return nil
+}
+
+// swapError returns the error to return from Swap for a replacement that exited before it was ready.
+func (managed *managedService) swapError() error {
+	managed.mu.Lock()
+	defer managed.mu.Unlock()
+	if managed.swapErr != nil {
+		return managed.swapErr
+	}
+	return fmt.Errorf("%s: %w", managed.name, ErrServiceExited)
+}
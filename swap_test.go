@@ -0,0 +1,126 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+func TestServiceHandle_Swap(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	old := newBlockingService()
+	handle := lt.Start(old, lifetime.WithName("listener"))
+	replacement := &gatedService{blockingService: newBlockingService(), ready: make(chan struct{})}
+
+	swapped := make(chan error)
+	go func() {
+		swapped <- handle.Swap(replacement)
+	}()
+
+	select {
+	case <-old.stop:
+		t.Fatalf("expected the old service not to be stopped before the replacement is ready")
+	case <-swapped:
+		t.Fatalf("expected Swap to wait for the replacement to be ready")
+	case <-time.After(time.Millisecond * 20):
+	}
+	close(replacement.ready)
+	if err := <-swapped; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !old.stopped {
+		t.Errorf("expected the old service to be stopped")
+	}
+	if handle.Service() != replacement {
+		t.Errorf("expected the handle to control the replacement")
+	}
+	if services := lt.Services(); len(services) != 1 || services[0].State != lifetime.ServiceRunning {
+		t.Errorf("expected the replacement to be running, got %v", services)
+	}
+
+	handle.Stop()
+	if !replacement.stopped {
+		t.Errorf("expected the replacement to be stopped through the handle")
+	}
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestServiceHandle_Swap_ReplacementFailed(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	old := newBlockingService()
+	handle := lt.Start(old)
+	if err := handle.Ensure(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	startErr := errors.New("address already in use")
+	err := handle.Swap(&failingService{err: startErr})
+	var serviceErr *lifetime.ServiceError
+	if !errors.As(err, &serviceErr) || !errors.Is(err, startErr) {
+		t.Errorf("expected the error of the replacement, got %v", err)
+	}
+	if handle.Service() != old {
+		t.Errorf("expected the handle to still control the old service")
+	}
+	if services := lt.Services(); len(services) != 1 || services[0].State != lifetime.ServiceRunning {
+		t.Errorf("expected the old service to still be running, got %v", services)
+	}
+
+	lt.Shutdown()
+	if err := lt.WaitErr(); err != nil {
+		t.Fatalf("expected the error not to be reported, got %v", err)
+	}
+	if cause, _ := lt.Cause(); cause != lifetime.CauseManual {
+		t.Errorf("expected the shutdown not to be caused by the replacement, got %s", cause)
+	}
+	if !old.stopped {
+		t.Errorf("expected the old service to be stopped by the shutdown")
+	}
+}
+
+// failOnDemandService runs until it is stopped, or until an error is sent to it which it then returns.
+type failOnDemandService struct {
+	fail chan error
+	stop chan struct{}
+}
+
+func (s *failOnDemandService) Start() error {
+	select {
+	case err := <-s.fail:
+		return err
+	case <-s.stop:
+		return nil
+	}
+}
+
+func (s *failOnDemandService) Stop() {
+	close(s.stop)
+}
+
+func TestServiceHandle_Swap_ReplacementFailedAfterSwap(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	handle := lt.Start(newBlockingService())
+	replacement := &failOnDemandService{fail: make(chan error), stop: make(chan struct{})}
+	if err := handle.Swap(replacement); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Once the swap is complete, the replacement is treated like any other service.
+	failure := errors.New("connection lost")
+	replacement.fail <- failure
+	if err := lt.WaitErr(); !errors.Is(err, failure) {
+		t.Errorf("expected the failure to be reported, got %v", err)
+	}
+	if cause, _ := lt.Cause(); cause != lifetime.CauseServiceError {
+		t.Errorf("expected the shutdown to be caused by the failure, got %s", cause)
+	}
+}
@@ -0,0 +1,179 @@
+package lifetime
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// timingsFileRuns is the number of runs that are kept in a timings file.
+const timingsFileRuns = 100
+
+// WithTimingsFile persists the timings of each run to the given file once the shutdown completes, so that
+// startup and shutdown times can be tracked across restarts. The timings of previous runs are loaded
+// when the lifetime is created, see PreviousTimings. Only the most recent 100 runs are kept.
+func WithTimingsFile(path string) Option {
+	return func(lifetime *Lifetime) {
+		lifetime.timings.file = path
+	}
+}
+
+// RunTimings describes how long a single run of the application took to start up and shut down.
+type RunTimings struct {
+	// Started is the time the lifetime was created.
+	Started time.Time `json:"started"`
+	// Startup is the time taken from the creation of the lifetime until it was ready, if it became ready.
+	Startup time.Duration `json:"startup,omitempty"`
+	// Shutdown is the time taken for the shutdown to complete, if it has completed.
+	// It includes any drain delay and every shutdown stage.
+	Shutdown time.Duration `json:"shutdown,omitempty"`
+	// ServiceStops is the time taken for each service to stop during the shutdown, by name.
+	// If several services share a name the longest time is kept.
+	ServiceStops map[string]time.Duration `json:"service_stops,omitempty"`
+}
+
+// Timings returns the timings of the current run so far.
+func (lifetime *Lifetime) Timings() RunTimings {
+	lifetime.timings.mu.Lock()
+	defer lifetime.timings.mu.Unlock()
+	return lifetime.timings.current.copy()
+}
+
+// PreviousTimings returns the timings of previous runs that were loaded from the file given to
+// WithTimingsFile, oldest first.
+func (lifetime *Lifetime) PreviousTimings() []RunTimings {
+	lifetime.timings.mu.Lock()
+	defer lifetime.timings.mu.Unlock()
+	previous := make([]RunTimings, len(lifetime.timings.previous))
+	copy(previous, lifetime.timings.previous)
+	return previous
+}
+
+// copy returns a copy of the timings that shares no state.
+func (timings RunTimings) copy() RunTimings {
+	stops := timings.ServiceStops
+	timings.ServiceStops = make(map[string]time.Duration, len(stops))
+	for name, d := range stops {
+		timings.ServiceStops[name] = d
+	}
+	return timings
+}
+
+// newTimingsRecorder returns a timingsRecorder that is ready to use.
+func newTimingsRecorder() *timingsRecorder {
+	return &timingsRecorder{
+		current: RunTimings{ServiceStops: map[string]time.Duration{}},
+	}
+}
+
+// timingsRecorder records the timings of the current run from lifetime events.
+type timingsRecorder struct {
+	lifetime *Lifetime
+	// file is the file the timings are persisted to, if any.
+	file string
+
+	mu       sync.Mutex
+	current  RunTimings
+	previous []RunTimings
+}
+
+// start loads the timings of previous runs and starts recording the current run.
+func (recorder *timingsRecorder) start(lifetime *Lifetime) {
+	recorder.lifetime = lifetime
+	recorder.current.Started = lifetime.created
+	if recorder.file != "" {
+		previous, err := loadTimings(recorder.file)
+		if err != nil {
+			lifetime.log(LevelWarn, "lifetime could not load timings", Field{Key: "error", Value: err})
+		}
+		recorder.previous = previous
+	}
+	lifetime.OnEvent(recorder.handle)
+}
+
+// handle records the timings described by the given event.
+func (recorder *timingsRecorder) handle(event Event) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	switch event.Type {
+	case EventReady:
+		recorder.current.Startup = event.Duration
+	case EventServiceStopped:
+		// Services that are stopped outside of a shutdown, such as when they are restarted, are not recorded.
+		if event.ShutdownID != "" && event.Duration > recorder.current.ServiceStops[event.Service] {
+			recorder.current.ServiceStops[event.Service] = event.Duration
+		}
+	case EventShutdownComplete:
+		recorder.current.Shutdown = event.Duration
+		if recorder.file == "" {
+			return
+		}
+		runs := append(recorder.previous, recorder.current)
+		if len(runs) > timingsFileRuns {
+			runs = runs[len(runs)-timingsFileRuns:]
+		}
+		if err := saveTimings(recorder.file, runs); err != nil {
+			recorder.lifetime.log(LevelWarn, "lifetime could not save timings", Field{Key: "error", Value: err})
+		}
+	}
+}
+
+// loadTimings reads the runs stored in the given file, one JSON object per line.
+// A file that does not exist holds no runs.
+func loadTimings(path string) ([]RunTimings, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read timings file: %w", err)
+	}
+	runs := make([]RunTimings, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var run RunTimings
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			return runs, fmt.Errorf("could not parse timings file: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, scanner.Err()
+}
+
+// saveTimings replaces the given file with the given runs, one JSON object per line.
+// The runs are written to a temporary file first so that the file is never left partially written.
+func saveTimings(path string, runs []RunTimings) error {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	for _, run := range runs {
+		if err := encoder.Encode(run); err != nil {
+			return fmt.Errorf("could not encode timings: %w", err)
+		}
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("could not create timings file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("could not write timings file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("could not write timings file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("could not replace timings file: %w", err)
+	}
+	return nil
+}
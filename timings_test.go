@@ -0,0 +1,50 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithTimingsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lifetime")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "timings.json")
+
+	for run := 0; run < 2; run++ {
+		lt := lifetime.New(context.Background(), lifetime.WithTimingsFile(path)).HandleErrors(nil)
+		if previous := lt.PreviousTimings(); len(previous) != run {
+			t.Fatalf("expected %d previous runs, got %d", run, len(previous))
+		}
+
+		lt.Start(newBlockingService(), lifetime.WithName("api"))
+		lt.OnEvent(func(event lifetime.Event) {
+			if event.Type == lifetime.EventReady {
+				go lt.Shutdown()
+			}
+		})
+		_ = lt.Wait()
+
+		timings := lt.Timings()
+		if timings.Startup <= 0 || timings.Shutdown <= 0 {
+			t.Errorf("expected startup and shutdown to be timed, got %+v", timings)
+		}
+		if _, ok := timings.ServiceStops["api"]; !ok {
+			t.Errorf("expected the stop of the api service to be timed, got %+v", timings.ServiceStops)
+		}
+	}
+
+	previous := lifetime.New(context.Background(), lifetime.WithTimingsFile(path)).PreviousTimings()
+	if len(previous) != 2 {
+		t.Fatalf("expected 2 previous runs, got %d", len(previous))
+	}
+	if previous[1].Shutdown <= 0 || !previous[0].Started.Before(previous[1].Started) {
+		t.Errorf("unexpected previous runs: %+v", previous)
+	}
+}
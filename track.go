@@ -0,0 +1,38 @@
+package lifetime
+
+import (
+	"sync"
+)
+
+// TrackDone adopts a goroutine that was not started by the lifetime, such as one started by a third-party
+// library that only exposes a done channel. The goroutine is treated as a service with the given name:
+// cancel is called when the lifetime shuts down, and Wait waits for done to be closed.
+// cancel may be nil if the goroutine stops by itself.
+func (lifetime *Lifetime) TrackDone(name string, done <-chan struct{}, cancel func(), opts ...StartOption) *ServiceHandle {
+	opts = append([]StartOption{WithName(name)}, opts...)
+	return lifetime.Start(&trackedDone{done: done, cancel: cancel}, opts...)
+}
+
+// trackedDone is an implementation of Service that runs until a done channel is closed.
+type trackedDone struct {
+	done       <-chan struct{}
+	cancel     func()
+	cancelOnce sync.Once
+}
+
+// Start will start the service.
+// This is a blocking call and should block for the lifetime of the service.
+// Returns an error which is treated as fatal.
+func (service *trackedDone) Start() error {
+	<-service.done
+	return nil
+}
+
+// Stop will stop the service.
+// Stop is not called if Start returned an error.
+func (service *trackedDone) Stop() {
+	if service.cancel == nil {
+		return
+	}
+	service.cancelOnce.Do(service.cancel)
+}
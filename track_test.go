@@ -0,0 +1,48 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+func TestLifetime_TrackDone(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 50)
+	}()
+
+	lt.TrackDone("legacy", done, cancel)
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	select {
+	case <-done:
+	default:
+		t.Errorf("expected Wait to wait for the tracked goroutine")
+	}
+	if ctx.Err() == nil {
+		t.Errorf("expected the tracked goroutine to be cancelled")
+	}
+}
+
+func TestLifetime_TrackDone_NilCancel(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+
+	done := make(chan struct{})
+	close(done)
+
+	handle := lt.TrackDone("finished", done, nil)
+	lt.Shutdown()
+	_ = lt.Wait()
+
+	if handle.Name() != "finished" {
+		t.Errorf("expected name finished, got %s", handle.Name())
+	}
+}
@@ -0,0 +1,103 @@
+package lifetime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TransactionTracker counts the database transactions or other units of work that are in progress,
+// so that the connections they use are not closed underneath them during a shutdown.
+// See CloseAfterTransactions.
+type TransactionTracker struct {
+	mu     sync.Mutex
+	active int
+	// idle is closed whenever there are no active transactions.
+	idle chan struct{}
+}
+
+// NewTransactionTracker returns a TransactionTracker with no active transactions.
+func NewTransactionTracker() *TransactionTracker {
+	idle := make(chan struct{})
+	close(idle)
+	return &TransactionTracker{idle: idle}
+}
+
+// Begin records the start of a transaction. The returned func must be called once the transaction
+// has been committed or rolled back. It is safe to call the returned func more than once.
+func (tracker *TransactionTracker) Begin() func() {
+	tracker.mu.Lock()
+	if tracker.active == 0 {
+		tracker.idle = make(chan struct{})
+	}
+	tracker.active++
+	tracker.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(tracker.end)
+	}
+}
+
+// end records the end of a transaction.
+func (tracker *TransactionTracker) end() {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	tracker.active--
+	if tracker.active == 0 {
+		close(tracker.idle)
+	}
+}
+
+// Active returns the number of transactions that are in progress.
+func (tracker *TransactionTracker) Active() int {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	return tracker.active
+}
+
+// Wait blocks until there are no active transactions or the given context is done,
+// in which case the context error is returned.
+func (tracker *TransactionTracker) Wait(ctx context.Context) error {
+	for {
+		tracker.mu.Lock()
+		if tracker.active == 0 {
+			tracker.mu.Unlock()
+			return nil
+		}
+		idle := tracker.idle
+		tracker.mu.Unlock()
+
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// CloseAfterTransactions registers a func that closes a connection pool during the StageCloseStorage stage,
+// once the given tracker has no active transactions. The pool is closed anyway if the transactions have
+// not completed within the given timeout, or before the shutdown timeout, and the number of transactions
+// that were still active is logged.
+func (lifetime *Lifetime) CloseAfterTransactions(name string, tracker *TransactionTracker, timeout time.Duration, closePool StageFunc) {
+	lifetime.OnStage(StageCloseStorage, name, func(ctx context.Context) error {
+		waitCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		start := time.Now()
+		if err := tracker.Wait(waitCtx); err != nil {
+			lifetime.log(LevelWarn, "lifetime closing with active transactions",
+				Field{Key: "name", Value: name},
+				Field{Key: "active", Value: tracker.Active()},
+				Field{Key: "waited", Value: time.Since(start).String()},
+			)
+			if closeErr := closePool(ctx); closeErr != nil {
+				return closeErr
+			}
+			return fmt.Errorf("%d transactions still active: %w", tracker.Active(), err)
+		}
+		return closePool(ctx)
+	})
+}
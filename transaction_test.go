@@ -0,0 +1,68 @@
+package lifetime_test
+
+import (
+	"context"
+	"github.com/tomwright/lifetime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLifetime_CloseAfterTransactions(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	tracker := lifetime.NewTransactionTracker()
+
+	var committed, closedWithActive int32
+	done := tracker.Begin()
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventShutdownStarted {
+			go func() {
+				time.Sleep(time.Millisecond * 20)
+				atomic.StoreInt32(&committed, 1)
+				done()
+				done()
+			}()
+		}
+	})
+	lt.CloseAfterTransactions("postgres", tracker, time.Second, func(ctx context.Context) error {
+		if atomic.LoadInt32(&committed) == 0 || tracker.Active() != 0 {
+			atomic.StoreInt32(&closedWithActive, 1)
+		}
+		return nil
+	})
+
+	lt.Shutdown()
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if atomic.LoadInt32(&closedWithActive) == 1 {
+		t.Errorf("expected the pool to be closed once the transaction completed")
+	}
+}
+
+func TestLifetime_CloseAfterTransactions_Timeout(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	tracker := lifetime.NewTransactionTracker()
+	tracker.Begin()
+
+	var stageErr error
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventStageCompleted && event.Stage == lifetime.StageCloseStorage {
+			stageErr = event.Err
+		}
+	})
+	closed := false
+	lt.CloseAfterTransactions("postgres", tracker, time.Millisecond*20, func(ctx context.Context) error {
+		closed = true
+		return nil
+	})
+
+	lt.Shutdown()
+	_ = lt.Wait()
+	if !closed {
+		t.Errorf("expected the pool to be closed once the timeout was reached")
+	}
+	if stageErr == nil {
+		t.Errorf("expected the stage to report the active transactions")
+	}
+}
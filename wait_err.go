@@ -0,0 +1,80 @@
+package lifetime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxRecordedFailures is the number of errors that are kept for WaitErr.
+// Later errors are dropped so that the errors that caused the shutdown are kept.
+const maxRecordedFailures = defaultErrorQueueSize
+
+// ShutdownError is returned by WaitErr and contains the errors that occurred while the application was
+// running and shutting down. Use errors.Is and errors.As to check for a specific error.
+type ShutdownError struct {
+	// Errs are the errors, in the order they occurred.
+	Errs []error
+}
+
+// Error returns the error message.
+func (e *ShutdownError) Error() string {
+	if len(e.Errs) == 1 {
+		return e.Errs[0].Error()
+	}
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors: %s", len(e.Errs), strings.Join(msgs, "; "))
+}
+
+// Is returns true if any of the errors matches the target.
+func (e *ShutdownError) Is(target error) bool {
+	for _, err := range e.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors that matches the target, and if one is found, sets target to it.
+func (e *ShutdownError) As(target interface{}) bool {
+	for _, err := range e.Errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// WaitErr is the same as Wait, but also returns the errors that occurred so that the caller can decide how the
+// application exits, rather than the errors only being passed to the error handler. These are the errors reported
+// by services, jobs and ReportError, the errors returned by stage funcs, and the errors returned by services
+// after they were stopped. Shutdown signals are not included.
+// If any errors occurred a *ShutdownError is returned, with the error returned by Wait first.
+func (lifetime *Lifetime) WaitErr() error {
+	err := lifetime.Wait()
+
+	lifetime.failuresMu.Lock()
+	errs := append([]error(nil), lifetime.failures...)
+	lifetime.failuresMu.Unlock()
+
+	if err != nil {
+		errs = append([]error{err}, errs...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ShutdownError{Errs: errs}
+}
+
+// recordFailure records the given error so that it is returned by WaitErr.
+func (lifetime *Lifetime) recordFailure(err error) {
+	lifetime.failuresMu.Lock()
+	defer lifetime.failuresMu.Unlock()
+	if len(lifetime.failures) < maxRecordedFailures {
+		lifetime.failures = append(lifetime.failures, err)
+	}
+}
@@ -0,0 +1,50 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+)
+
+func TestLifetime_WaitErr(t *testing.T) {
+	t.Run("clean shutdown", func(t *testing.T) {
+		lt := lifetime.New(context.Background()).Init()
+		lt.Start(newBlockingService())
+		lt.Shutdown()
+		if err := lt.WaitErr(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("service and stop failures", func(t *testing.T) {
+		lt := lifetime.New(context.Background()).Init()
+
+		startErr := errors.New("cannot connect")
+		lateErr := errors.New("flush failed")
+		stageErr := errors.New("deregister failed")
+		lt.Start(&errOnStopService{stop: make(chan struct{}), err: lateErr})
+		lt.RegisterDrain("discovery", func(ctx context.Context) error {
+			return stageErr
+		})
+		lt.Start(&failingService{err: startErr})
+
+		err := lt.WaitErr()
+		var shutdownErr *lifetime.ShutdownError
+		if !errors.As(err, &shutdownErr) {
+			t.Fatalf("expected a ShutdownError, got %v", err)
+		}
+		if len(shutdownErr.Errs) != 3 || !errors.Is(shutdownErr.Errs[0], startErr) {
+			t.Errorf("expected the start failure first, got %v", shutdownErr.Errs)
+		}
+		for _, exp := range []error{startErr, lateErr, stageErr} {
+			if !errors.Is(err, exp) {
+				t.Errorf("expected %v to be included, got %v", exp, err)
+			}
+		}
+		var stageError *lifetime.StageError
+		if !errors.As(err, &stageError) || stageError.Name != "discovery" {
+			t.Errorf("expected the stage error to be found, got %v", stageError)
+		}
+	})
+}
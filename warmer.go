@@ -0,0 +1,159 @@
+package lifetime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WarmFunc warms a cache or other resource before the application is ready.
+// It may call progress with the fraction of the work that has been done, between 0 and 1.
+// Returning an error is treated as fatal.
+type WarmFunc func(ctx context.Context, progress func(fraction float64)) error
+
+// WarmerOption is used to configure a warmer.
+type WarmerOption func(w *warmer)
+
+// WithWarmerReadyAfter stops the warmer from holding back readiness once the given duration has passed,
+// so that a slow warmer does not stop the application from becoming ready. The warmer keeps running.
+func WithWarmerReadyAfter(timeout time.Duration) WarmerOption {
+	return func(w *warmer) {
+		w.readyAfter = timeout
+	}
+}
+
+// warmer is a WarmFunc that has been registered with the lifetime.
+type warmer struct {
+	lifetime   *Lifetime
+	name       string
+	fn         WarmFunc
+	readyAfter time.Duration
+
+	// released is closed once the warmer no longer holds back readiness.
+	released    chan struct{}
+	releaseOnce sync.Once
+
+	mu       sync.Mutex
+	progress float64
+	done     bool
+	err      error
+}
+
+// WarmerStatus describes a single warmer.
+type WarmerStatus struct {
+	// Name is the name of the warmer.
+	Name string `json:"name"`
+	// Progress is the fraction of the work the warmer has reported as done, between 0 and 1.
+	Progress float64 `json:"progress"`
+	// Done is true once the warmer has returned.
+	Done bool `json:"done"`
+	// Error is the error returned by the warmer, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// AddWarmer registers a func that warms a cache or other resource, so that cold caches do not cause
+// a thundering herd when a new version is deployed.
+// Warmers that are added before Wait is called run concurrently once every service is ready, and hold back
+// the startup phase until they return. See Ready. Warmers that are added later are run straight away.
+func (lifetime *Lifetime) AddWarmer(name string, fn WarmFunc, opts ...WarmerOption) {
+	w := &warmer{
+		lifetime: lifetime,
+		name:     name,
+		fn:       fn,
+		released: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	lifetime.warmersMu.Lock()
+	lifetime.warmers = append(lifetime.warmers, w)
+	started := lifetime.warmersStarted
+	lifetime.warmersMu.Unlock()
+
+	if started {
+		go w.run()
+	}
+}
+
+// startWarmers runs the given warmers, along with any that are added from now on.
+func (lifetime *Lifetime) startWarmers(warmers []*warmer) {
+	lifetime.warmersMu.Lock()
+	lifetime.warmersStarted = true
+	lifetime.warmersMu.Unlock()
+
+	for _, w := range warmers {
+		go w.run()
+	}
+}
+
+// run executes the warmer and records the result.
+func (w *warmer) run() {
+	if w.readyAfter > 0 {
+		timer := time.AfterFunc(w.readyAfter, func() {
+			w.lifetime.log(LevelWarn, "lifetime warmer did not complete in time",
+				Field{Key: "warmer", Value: w.name},
+				Field{Key: "progress", Value: w.status().Progress},
+			)
+			w.release()
+		})
+		defer timer.Stop()
+	}
+
+	start := time.Now()
+	w.lifetime.log(LevelDebug, "lifetime warmer started", Field{Key: "warmer", Value: w.name})
+	err := w.fn(w.lifetime.ctx, w.setProgress)
+
+	w.mu.Lock()
+	w.done = true
+	w.err = err
+	if err == nil {
+		w.progress = 1
+	}
+	w.mu.Unlock()
+
+	w.lifetime.emit(Event{Type: EventWarmerCompleted, Warmer: w.name, Err: err, Duration: time.Since(start)})
+	if err != nil {
+		// Errors caused by a shutdown are expected.
+		if w.lifetime.ctx.Err() == nil {
+			w.lifetime.reportError(fmt.Errorf("warmer %s: %w", w.name, err))
+		}
+	} else {
+		w.lifetime.log(LevelInfo, "lifetime warmer completed",
+			Field{Key: "warmer", Value: w.name},
+			Field{Key: "took", Value: time.Since(start).String()},
+		)
+	}
+	w.release()
+}
+
+// setProgress records the fraction of the work the warmer has done.
+func (w *warmer) setProgress(fraction float64) {
+	if fraction < 0 {
+		fraction = 0
+	} else if fraction > 1 {
+		fraction = 1
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.progress = fraction
+}
+
+// release stops the warmer from holding back readiness.
+func (w *warmer) release() {
+	w.releaseOnce.Do(func() {
+		close(w.released)
+	})
+}
+
+// status returns the status of the warmer.
+func (w *warmer) status() WarmerStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	status := WarmerStatus{Name: w.name, Progress: w.progress, Done: w.done}
+	if w.err != nil {
+		status.Error = w.err.Error()
+	}
+	return status
+}
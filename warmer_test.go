@@ -0,0 +1,82 @@
+package lifetime_test
+
+import (
+	"context"
+	"errors"
+	"github.com/tomwright/lifetime"
+	"testing"
+	"time"
+)
+
+func TestLifetime_AddWarmer(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(newBlockingService())
+
+	release := make(chan struct{})
+	lt.AddWarmer("products", func(ctx context.Context, progress func(fraction float64)) error {
+		progress(0.5)
+		<-release
+		return nil
+	})
+
+	var progress float64
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventReady {
+			warmers := lt.Status().Warmers
+			if len(warmers) == 1 {
+				progress = warmers[0].Progress
+			}
+			go lt.Shutdown()
+		}
+	})
+	go func() {
+		time.Sleep(time.Millisecond * 20)
+		if lt.IsReady() {
+			t.Errorf("expected the warmer to hold back readiness")
+		}
+		close(release)
+	}()
+
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if progress != 1 {
+		t.Errorf("expected the warmer to be complete once ready, got progress %v", progress)
+	}
+}
+
+func TestLifetime_AddWarmer_ReadyAfter(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(newBlockingService())
+
+	lt.AddWarmer("search", func(ctx context.Context, progress func(fraction float64)) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, lifetime.WithWarmerReadyAfter(time.Millisecond*10))
+
+	lt.OnEvent(func(event lifetime.Event) {
+		if event.Type == lifetime.EventReady {
+			go lt.Shutdown()
+		}
+	})
+	if err := lt.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cause, _ := lt.Cause(); cause != lifetime.CauseManual {
+		t.Errorf("expected the application to become ready despite the warmer, got cause %s", cause)
+	}
+}
+
+func TestLifetime_AddWarmer_Error(t *testing.T) {
+	lt := lifetime.New(context.Background()).HandleErrors(nil)
+	lt.Start(newBlockingService())
+
+	lt.AddWarmer("search", func(ctx context.Context, progress func(fraction float64)) error {
+		return errors.New("index unavailable")
+	})
+	_ = lt.Wait()
+
+	if cause, _ := lt.Cause(); cause != lifetime.CauseServiceError {
+		t.Errorf("expected a failed warmer to trigger a shutdown, got cause %s", cause)
+	}
+}